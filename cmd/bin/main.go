@@ -17,7 +17,10 @@ func main() {
 
 	serverCmd := flag.NewFlagSet("server", flag.ExitOnError)
 	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)
-	// wsCmd := flag.NewFlagSet("ws", flag.ExitOnError)
+	importCmd := flag.NewFlagSet("import", flag.ExitOnError)
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	purgeCmd := flag.NewFlagSet("purge", flag.ExitOnError)
+	wsCmd := flag.NewFlagSet("ws", flag.ExitOnError)
 
 	if len(os.Args) < 2 {
 		log.Info().Msg("No command provided, defaulting to 'server'")
@@ -28,6 +31,14 @@ func main() {
 	switch os.Args[1] {
 	case "seed":
 		cmd.RunSeed(seedCmd, os.Args[2:])
+	case "import":
+		cmd.RunImport(importCmd, os.Args[2:])
+	case "export":
+		cmd.RunExport(exportCmd, os.Args[2:])
+	case "purge":
+		cmd.RunPurge(purgeCmd, os.Args[2:])
+	case "ws":
+		cmd.RunWs(wsCmd, os.Args[2:])
 	case "server":
 		cmd.RunServer(serverCmd, os.Args[2:])
 	default:
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	integSpace "codebase-app/internal/integration/digitaloceanspace"
+	spaceEnt "codebase-app/internal/integration/digitaloceanspace/entity"
+	integStorage "codebase-app/internal/integration/localstorage"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/product/service"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunExport writes the catalog feed to a storage backend, once or every
+// interval, ex: export -format=xml -storage=s3 -key=feeds/google.xml -interval=1h
+func RunExport(cmd *flag.FlagSet, args []string) {
+	var (
+		format      = cmd.String("format", entity.ExportFormatXML, "csv, jsonl or xml (Google Merchant feed)")
+		shopId      = cmd.String("shop_id", "", "only export the products of this shop, the full catalog when empty")
+		categoryId  = cmd.String("category_id", "", "only export the products of this category")
+		isAvailable = cmd.Bool("is_available", false, "only export the products in stock")
+		storage     = cmd.String("storage", "local", "local (public storage path) or s3")
+		key         = cmd.String("key", "", "path of the feed in the storage, feeds/products.<format> when empty")
+		interval    = cmd.Duration("interval", 0, "export again every interval until stopped, once when 0")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if !slices.Contains([]string{entity.ExportFormatCSV, entity.ExportFormatJSONL, entity.ExportFormatXML}, *format) {
+		log.Fatal().Str("format", *format).Msg("Invalid export format")
+	}
+
+	if *key == "" {
+		*key = "feeds/" + entity.ExportFilename(*format)
+	}
+
+	opts := []adapter.Option{adapter.WithShopeefunPostgres()}
+
+	var put func(ctx context.Context, r io.Reader) (string, error)
+	switch *storage {
+	case "local":
+		fullpath := filepath.Join(config.Envs.App.LocalStoragePublicPath, *key)
+		put = func(ctx context.Context, r io.Reader) (string, error) {
+			return fullpath, integStorage.WriteFile(fullpath, r)
+		}
+	case "s3":
+		opts = append(opts, adapter.WithDigihubStorage())
+		put = func(ctx context.Context, r io.Reader) (string, error) {
+			res, err := integSpace.NewDigitalOceanSpaceIntegration().PutFile(ctx, &spaceEnt.PutFileRequest{
+				Key:         *key,
+				ContentType: entity.ExportContentType(*format),
+				Body:        r,
+			})
+			return res.Url, err
+		}
+	default:
+		log.Fatal().Str("storage", *storage).Msg("Invalid export storage")
+	}
+
+	adapter.Adapters.Sync(opts...)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		productService = service.NewProductService(repository.NewProductRepository(adapter.Adapters.ShopeefunPostgres))
+		req            = &entity.ExportProductsRequest{Role: "admin", Format: *format}
+	)
+
+	req.ShopId = *shopId
+	req.CategoryId = *categoryId
+	req.IsAvailable = *isAvailable
+
+	export, err := productService.ExportProducts(ctx, req)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while preparing the export")
+	}
+
+	run := func(ctx context.Context) error {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(export(ctx, pw))
+		}()
+
+		location, err := put(ctx, pr)
+		pr.Close() // unblocks the export when the upload stopped early
+		if err != nil {
+			return err
+		}
+
+		log.Info().Str("location", location).Msg("Export written")
+		return nil
+	}
+
+	if *interval <= 0 {
+		if err := run(ctx); err != nil {
+			log.Error().Err(err).Msg("Error while exporting products")
+		}
+		return
+	}
+
+	scheduler.NewTimer("product_export", *interval).Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		next := now.Add(*interval)
+		return &next, run(ctx)
+	})
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/product/service"
+	"codebase-app/pkg/validator"
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunImport imports a CSV or JSONL file of products into a shop right away,
+// ex: import -shop_id=... -user_id=... -file=products.csv -report=errors.csv
+func RunImport(cmd *flag.FlagSet, args []string) {
+	var (
+		shopId = cmd.String("shop_id", "", "shop to import the products into")
+		userId = cmd.String("user_id", "", "owner of the shop, recorded as the actor")
+		file   = cmd.String("file", "", "path of the CSV or JSONL file")
+		format = cmd.String("format", "", "csv or jsonl, detected from the file name when empty")
+		report = cmd.String("report", "", "path to write the row errors to as CSV, stdout when empty")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	payload, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while reading the import file")
+	}
+
+	if *format == "" {
+		*format = entity.ImportFormatFromFilename(*file)
+	}
+
+	v := validator.NewValidator()
+	req := &entity.CreateImportJobRequest{
+		UserId:  *userId,
+		ShopId:  *shopId,
+		Format:  *format,
+		Payload: payload,
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Fatal().Err(err).Msg("Invalid import flags")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunPostgres(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	importService := service.NewImportService(repository.NewImportRepository(adapter.Adapters.ShopeefunPostgres), v)
+
+	job, err := importService.Import(ctx, req)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while importing products")
+		return
+	}
+
+	log.Info().Any("job", job).Msg("Import finished")
+
+	if job.ErrorCount == 0 {
+		return
+	}
+
+	errs, err := importService.GetImportReport(ctx, &entity.ImportJobRequest{UserId: *userId, JobId: job.Id})
+	if err != nil {
+		log.Error().Err(err).Msg("Error while reading the import report")
+		return
+	}
+
+	out := os.Stdout
+	if *report != "" {
+		if out, err = os.Create(*report); err != nil {
+			log.Error().Err(err).Msg("Error while creating the report file")
+			return
+		}
+		defer out.Close()
+	}
+
+	if err := entity.WriteImportReport(out, errs); err != nil {
+		log.Error().Err(err).Msg("Error while writing the import report")
+	}
+}
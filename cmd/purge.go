@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
+	outboxRepo "codebase-app/internal/module/outbox/repository"
+	productRepo "codebase-app/internal/module/product/repository"
+	productService "codebase-app/internal/module/product/service"
+	shopRepo "codebase-app/internal/module/shop/repository"
+	shopService "codebase-app/internal/module/shop/service"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunPurge hard deletes the products and shops deleted more than
+// TRASH_RETENTION_DAYS ago with their stored images, and the outbox events
+// published more than OUTBOX_RETENTION_DAYS ago, once or every interval, ex:
+// purge -interval=24h
+func RunPurge(cmd *flag.FlagSet, args []string) {
+	var (
+		interval = cmd.Duration("interval", 0, "purge again every interval until stopped, once when 0")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	adapter.Adapters.Sync(adapter.WithShopeefunPostgres())
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		db       = adapter.Adapters.ShopeefunPostgres
+		products = productService.NewProductService(productRepo.NewProductRepository(db))
+		shops    = shopService.NewShopService(shopRepo.NewShopRepository(db), integStorage.NewLocalStorageIntegration())
+		outbox   = outboxRepo.NewOutboxRepository(db)
+	)
+
+	// products go first, a shop is only purged once it has none left
+	run := func(ctx context.Context, now time.Time) error {
+		purgedProducts, err := products.PurgeProducts(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		purgedShops, err := shops.PurgeShops(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		images := append(purgedProducts.Images, purgedShops.Images...)
+		removed := removeStoredImages(images)
+
+		events, err := outbox.DeletePublished(ctx, now.AddDate(0, 0, -config.Envs.Outbox.RetentionDays))
+		if err != nil {
+			return err
+		}
+
+		log.Info().
+			Int("products", purgedProducts.Products).
+			Int("shops", purgedShops.Shops).
+			Int("images", removed).
+			Int("events", events).
+			Msg("Purge completed")
+		return nil
+	}
+
+	if *interval <= 0 {
+		if err := run(ctx, time.Now()); err != nil {
+			log.Error().Err(err).Msg("Error while purging the trash")
+		}
+		return
+	}
+
+	scheduler.NewTimer("trash_purge", *interval).Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		next := now.Add(*interval)
+		return &next, run(ctx, now)
+	})
+}
+
+// removeStoredImages deletes the images kept in the local public storage and
+// returns how many were. Images stored elsewhere are left alone.
+func removeStoredImages(urls []string) int {
+	var (
+		removed    int
+		publicPath = filepath.Clean(config.Envs.App.LocalStoragePublicPath)
+		prefix     = config.Envs.App.BaseURL + "/products/storage"
+	)
+
+	for _, url := range urls {
+		if !strings.HasPrefix(url, prefix+"/") {
+			log.Debug().Str("url", url).Msg("Image is not in the local storage")
+			continue
+		}
+
+		fullpath := filepath.Join(publicPath, strings.TrimPrefix(url, prefix))
+		if !strings.HasPrefix(fullpath, publicPath+string(filepath.Separator)) {
+			log.Warn().Str("url", url).Msg("Image is outside of the local storage")
+			continue
+		}
+
+		if err := integStorage.RemoveFile(fullpath); err != nil {
+			continue
+		}
+		removed++
+	}
+
+	return removed
+}
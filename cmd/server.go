@@ -4,8 +4,15 @@ import (
 	"codebase-app/internal/adapter"
 	"codebase-app/internal/infrastructure"
 	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/middleware"
+	workerOutbox "codebase-app/internal/module/outbox/handler/worker"
+	workerProduct "codebase-app/internal/module/product/handler/worker"
+	workerWebhook "codebase-app/internal/module/webhook/handler/worker"
+	webhookRepo "codebase-app/internal/module/webhook/repository"
+	workerWishlist "codebase-app/internal/module/wishlist/handler/worker"
 	"codebase-app/internal/route"
 	"codebase-app/pkg/validator"
+	"context"
 	"flag"
 	"os"
 	"os/signal"
@@ -53,10 +60,13 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 		}))
 	}
 
+	app.Use(middleware.RequestContext)
+
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,PATCH,OPTIONS,HEAD",
-		AllowHeaders: "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization",
+		AllowOrigins:  "*",
+		AllowMethods:  "GET,POST,PUT,DELETE,PATCH,OPTIONS,HEAD",
+		AllowHeaders:  "Origin,Content-Type,Accept,Content-Length,Accept-Language,Accept-Encoding,Connection,Access-Control-Allow-Origin,Authorization,X-API-KEY,X-Signature-Key-Id,X-Signature-Timestamp,X-Signature-Nonce,X-Signature,X-Request-ID",
+		ExposeHeaders: "X-Request-ID",
 	}))
 	// End Application Middlewares
 
@@ -70,6 +80,14 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	app.Get("/metrics", monitor.New(monitor.Config{Title: config.Envs.App.Name + config.Envs.App.Environtment + " Metrics"}))
 	route.SetupRoutes(app)
 
+	outboxWorker, err := workerOutbox.NewOutboxWorker(
+		webhookRepo.NewDispatcher(adapter.Adapters.ShopeefunPostgres),
+		workerProduct.NewStockAlertMailer(),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while configuring the outbox sink")
+	}
+
 	// print all routes that are registered
 	// for _, route := range app.Stack() {
 	// 	for _, handler := range route {
@@ -86,6 +104,13 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	}()
 	// End Run server in goroutine
 
+	// Background workers, stopped on shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	go workerProduct.NewProductWorker().Start(workerCtx)
+	go workerWishlist.NewWishlistWorker().Start(workerCtx)
+	go outboxWorker.Start(workerCtx)
+	go workerWebhook.NewWebhookWorker().Start(workerCtx)
+
 	// Handle graceful shutdown
 	quit := make(chan os.Signal, 1)
 
@@ -98,6 +123,8 @@ func RunServer(cmd *flag.FlagSet, args []string) {
 	<-quit
 	log.Info().Msg("Server is shutting down ...")
 
+	stopWorkers()
+
 	err = adapter.Adapters.Unsync()
 	if err != nil {
 		log.Error().Msgf("Error while closing adapters: %v", err)
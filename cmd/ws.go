@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure"
+	"codebase-app/internal/infrastructure/config"
+	wsNotification "codebase-app/internal/module/notification/handler/ws"
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RunWs serves the websocket server pushing the notifications of the users
+// in realtime.
+func RunWs(cmd *flag.FlagSet, args []string) {
+	var (
+		envs        = config.Envs
+		flagWsPort  = cmd.String("port", "8080", "Websocket server port")
+		mux         = http.NewServeMux()
+		server      = &http.Server{Handler: mux}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	defer cancel()
+
+	logLevel, err := zerolog.ParseLevel(envs.App.LogLevel)
+	if err != nil {
+		logLevel = zerolog.InfoLevel
+	}
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+	server.Addr = ":" + *flagWsPort
+
+	adapter.Adapters.Sync(
+		adapter.WithWebsocketServer(server),
+		adapter.WithShopeefunPostgres(),
+	)
+
+	infrastructure.InitializeLogger(envs.App.Environtment, envs.App.LogFileWs, logLevel)
+
+	hub := wsNotification.NewNotificationHub()
+	mux.Handle("/ws/notifications", hub.Handler())
+
+	go hub.Listen(ctx, adapter.ShopeefunPostgresDSN())
+
+	go func() {
+		log.Info().Msgf("Websocket server is running on port %s", *flagWsPort)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Msgf("Error while starting websocket server: %v", err)
+		}
+	}()
+
+	// Handle graceful shutdown
+	quit := make(chan os.Signal, 1)
+
+	shutdownSignals := []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGINT}
+	if runtime.GOOS == "windows" {
+		shutdownSignals = []os.Signal{os.Interrupt}
+	}
+
+	signal.Notify(quit, shutdownSignals...)
+	<-quit
+	log.Info().Msg("Websocket server is shutting down ...")
+
+	cancel()
+
+	if err := adapter.Adapters.Unsync(); err != nil {
+		log.Error().Msgf("Error while closing adapters: %v", err)
+	}
+
+	log.Info().Msg("Websocket server gracefully stopped")
+}
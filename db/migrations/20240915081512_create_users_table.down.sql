@@ -0,0 +1,3 @@
+-- users and roles predate the migrations and are relied on by the seeds, the
+-- up only creates them when missing so there is nothing to revert.
+SELECT 1;
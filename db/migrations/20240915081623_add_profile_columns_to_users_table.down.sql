@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_email_verifications;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS pending_email,
+    DROP COLUMN IF EXISTS email_verified_at,
+    DROP COLUMN IF EXISTS token_version;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS shop_status_histories;
+
+DROP INDEX IF EXISTS shops_status_idx;
+
+ALTER TABLE shops
+    DROP CONSTRAINT IF EXISTS shops_status_check,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
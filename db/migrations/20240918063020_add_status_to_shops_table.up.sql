@@ -0,0 +1,24 @@
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS status_reason TEXT,
+    DROP CONSTRAINT IF EXISTS shops_status_check,
+    ADD CONSTRAINT shops_status_check CHECK (status IN ('draft', 'pending_review', 'active', 'suspended', 'closed'));
+
+-- existing shops stay active, new shops start as draft
+ALTER TABLE shops ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX IF NOT EXISTS shops_status_idx ON shops (status) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS shop_status_histories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    actor_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+CREATE INDEX IF NOT EXISTS shop_status_histories_shop_id_idx ON shop_status_histories (shop_id, created_at DESC);
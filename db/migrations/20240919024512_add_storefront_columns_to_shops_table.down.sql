@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_shops_name_lower;
+DROP INDEX IF EXISTS idx_products_shop_id;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS follower_count,
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg,
+    DROP COLUMN IF EXISTS banner_url,
+    DROP COLUMN IF EXISTS logo_url;
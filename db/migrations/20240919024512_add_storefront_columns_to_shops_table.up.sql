@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS shop_categories (
+    shop_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (shop_id, category_id),
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    FOREIGN KEY (category_id) REFERENCES product_categories(id)
+);
+
+ALTER TABLE shops
+    ADD COLUMN logo_url TEXT,
+    ADD COLUMN banner_url TEXT,
+    ADD COLUMN rating_avg DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN follower_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_shop_id ON products (shop_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_shops_name_lower ON shops (LOWER(name)) WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS product_slug_histories;
+DROP TABLE IF EXISTS shop_slug_histories;
+
+DROP INDEX IF EXISTS uq_products_slug;
+DROP INDEX IF EXISTS uq_shops_slug;
+
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
+ALTER TABLE shops DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,35 @@
+ALTER TABLE shops ADD COLUMN slug VARCHAR(255);
+ALTER TABLE products ADD COLUMN slug VARCHAR(255);
+
+-- existing rows get the id prefix as suffix so the backfill never collides
+UPDATE shops
+SET slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')) || '-' || LEFT(id::TEXT, 8);
+
+UPDATE products
+SET slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g')) || '-' || LEFT(id::TEXT, 8);
+
+ALTER TABLE shops ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shops_slug ON shops (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_products_slug ON products (slug);
+
+CREATE TABLE IF NOT EXISTS shop_slug_histories (
+    slug VARCHAR(255) PRIMARY KEY,
+    shop_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_slug_histories_shop_id ON shop_slug_histories (shop_id);
+
+CREATE TABLE IF NOT EXISTS product_slug_histories (
+    slug VARCHAR(255) PRIMARY KEY,
+    product_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_slug_histories_product_id ON product_slug_histories (product_id);
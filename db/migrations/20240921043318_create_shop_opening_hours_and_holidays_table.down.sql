@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS stock_reservations;
+DROP TABLE IF EXISTS shop_holidays;
+DROP TABLE IF EXISTS shop_opening_hours;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS holiday_hides_products,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,47 @@
+ALTER TABLE shops
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    ADD COLUMN holiday_hides_products BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS shop_opening_hours (
+    shop_id UUID NOT NULL,
+    day_of_week SMALLINT NOT NULL, -- 0 = sunday
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL, -- before opens_at when open past midnight
+
+    PRIMARY KEY (shop_id, day_of_week),
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT chk_shop_opening_hours_day CHECK (day_of_week BETWEEN 0 AND 6)
+);
+
+CREATE TABLE IF NOT EXISTS shop_holidays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    message TEXT, -- auto-reply shown to buyers while the shop is away
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT chk_shop_holidays_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_holidays_shop_id_period ON shop_holidays (shop_id, starts_at, ends_at);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id VARCHAR(255) NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT chk_stock_reservations_quantity CHECK (quantity > 0),
+    CONSTRAINT chk_stock_reservations_status CHECK (status IN ('open', 'committed', 'released'))
+);
+
+-- only one open reservation per order and product, a released one does not
+-- keep the order from reserving the product again
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stock_reservations_order_product ON stock_reservations (order_id, product_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_stock_reservations_product_id_open ON stock_reservations (product_id) WHERE status = 'open';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS shop_shipping_profiles;
+
+DROP INDEX IF EXISTS idx_shops_origin_point;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS origin_point,
+    DROP COLUMN IF EXISTS origin_postal_code,
+    DROP COLUMN IF EXISTS origin_city,
+    DROP COLUMN IF EXISTS origin_address;
@@ -0,0 +1,27 @@
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+ALTER TABLE shops
+    ADD COLUMN origin_address TEXT,
+    ADD COLUMN origin_city VARCHAR(100),
+    ADD COLUMN origin_postal_code VARCHAR(10),
+    ADD COLUMN origin_point GEOGRAPHY(POINT, 4326);
+
+CREATE INDEX IF NOT EXISTS idx_shops_origin_point ON shops USING GIST (origin_point);
+
+CREATE TABLE IF NOT EXISTS shop_shipping_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    couriers TEXT[] NOT NULL,
+    handling_days INT NOT NULL DEFAULT 1,
+    free_shipping_threshold DECIMAL(19, 4),
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    CONSTRAINT chk_shop_shipping_profiles_handling_days CHECK (handling_days BETWEEN 0 AND 30)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_shipping_profiles_shop_id ON shop_shipping_profiles (shop_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shop_shipping_profiles_default ON shop_shipping_profiles (shop_id) WHERE is_default;
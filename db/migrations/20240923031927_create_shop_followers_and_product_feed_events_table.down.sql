@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_feed_events;
+DROP TABLE IF EXISTS shop_followers;
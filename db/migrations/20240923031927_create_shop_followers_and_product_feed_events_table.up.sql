@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS shop_followers (
+    user_id UUID NOT NULL,
+    shop_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, shop_id),
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_followers_shop_id ON shop_followers (shop_id);
+
+-- append-only log read by the follower feed, one row per new or restocked product
+CREATE TABLE IF NOT EXISTS product_feed_events (
+    id BIGSERIAL PRIMARY KEY,
+    shop_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT chk_product_feed_events_kind CHECK (kind IN ('created', 'restocked'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_feed_events_shop_id_id ON product_feed_events (shop_id, id DESC);
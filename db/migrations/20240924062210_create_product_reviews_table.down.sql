@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS product_review_reports;
+DROP TABLE IF EXISTS product_reviews;
+
+DROP INDEX IF EXISTS idx_products_rating_avg;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
@@ -0,0 +1,39 @@
+ALTER TABLE products
+    ADD COLUMN rating_avg DECIMAL(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_rating_avg ON products (rating_avg DESC) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL,
+    body TEXT,
+    photo_urls TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'published',
+    moderation_reason TEXT,
+    report_count INT NOT NULL DEFAULT 0,
+    reply TEXT,
+    replied_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT uq_product_reviews_product_user UNIQUE (product_id, user_id),
+    CONSTRAINT chk_product_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT chk_product_reviews_status CHECK (status IN ('published', 'hidden'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reviews_product_id_created_at ON product_reviews (product_id, created_at DESC) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS idx_product_reviews_reported ON product_reviews (report_count DESC) WHERE report_count > 0;
+
+CREATE TABLE IF NOT EXISTS product_review_reports (
+    review_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (review_id, user_id),
+    FOREIGN KEY (review_id) REFERENCES product_reviews(id) ON DELETE CASCADE
+);
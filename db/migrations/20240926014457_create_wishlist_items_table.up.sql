@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    -- state of the product when the user was last notified, used to detect
+    -- price drops and restocks
+    last_price DECIMAL(19, 4) NOT NULL,
+    last_in_stock BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (user_id, product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_product_id ON wishlist_items (product_id);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_user_id_created_at ON wishlist_items (user_id, created_at DESC);
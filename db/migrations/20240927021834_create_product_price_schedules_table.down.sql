@@ -0,0 +1,7 @@
+ALTER TABLE products
+    DROP COLUMN IF EXISTS sale_ends_at,
+    DROP COLUMN IF EXISTS sale_discount_value,
+    DROP COLUMN IF EXISTS sale_discount_type,
+    DROP COLUMN IF EXISTS sale_id;
+
+DROP TABLE IF EXISTS product_price_schedules;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS product_price_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL,
+    -- price: replaces the base price at starts_at
+    -- sale: discounts the base price between starts_at and ends_at
+    kind VARCHAR(20) NOT NULL,
+    price DECIMAL(19, 4),
+    discount_type VARCHAR(20),
+    discount_value DECIMAL(19, 4),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT chk_product_price_schedules_kind CHECK (
+        (kind = 'price' AND price IS NOT NULL AND price >= 0)
+        OR (kind = 'sale' AND discount_type IN ('percentage', 'fixed') AND discount_value > 0 AND ends_at > starts_at)
+    ),
+    CONSTRAINT chk_product_price_schedules_status CHECK (status IN ('scheduled', 'active', 'applied', 'expired', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_price_schedules_product_id ON product_price_schedules (product_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_product_price_schedules_scheduled ON product_price_schedules (starts_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_product_price_schedules_active ON product_price_schedules (ends_at) WHERE status = 'active';
+
+-- terms of the sale currently applied to the product, copied from its
+-- schedule so the effective price is computed without a join
+ALTER TABLE products
+    ADD COLUMN sale_id UUID,
+    ADD COLUMN sale_discount_type VARCHAR(20),
+    ADD COLUMN sale_discount_value DECIMAL(19, 4),
+    ADD COLUMN sale_ends_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS product_price_histories (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL,
+    old_price DECIMAL(19, 4) NOT NULL,
+    new_price DECIMAL(19, 4) NOT NULL,
+    actor_id VARCHAR(255), -- NULL when changed by the price scheduler
+    source VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_price_histories_product_id_changed_at ON product_price_histories (product_id, changed_at DESC);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
+ALTER TABLE shops DROP COLUMN IF EXISTS currency;
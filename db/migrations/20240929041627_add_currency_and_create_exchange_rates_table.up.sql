@@ -0,0 +1,18 @@
+ALTER TABLE shops ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- the currency of a product defaults to the currency of its shop
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency CHAR(3);
+UPDATE products p SET currency = s.currency FROM shops s WHERE s.id = p.shop_id AND p.currency IS NULL;
+ALTER TABLE products ALTER COLUMN currency SET DEFAULT 'IDR';
+ALTER TABLE products ALTER COLUMN currency SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(24, 12) NOT NULL CHECK (rate > 0), -- quote units bought by one base unit
+    updated_by VARCHAR(255),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
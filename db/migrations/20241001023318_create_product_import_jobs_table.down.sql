@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS product_import_jobs;
+
+DROP INDEX IF EXISTS ux_products_shop_id_sku;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
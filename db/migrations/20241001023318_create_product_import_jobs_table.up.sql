@@ -0,0 +1,31 @@
+-- the seller's own product code, imports upsert by it
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_products_shop_id_sku ON products (shop_id, sku) WHERE sku IS NOT NULL AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS product_import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'jsonl')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'validating', 'importing', 'completed', 'failed')),
+    payload BYTEA, -- the uploaded file, cleared once the job is finished
+    total_rows INT NOT NULL DEFAULT 0,
+    validated_rows INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    updated_count INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]', -- per row errors, the downloadable report
+    error TEXT, -- why a job without row errors failed
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    lease INT NOT NULL DEFAULT 0, -- bumped by every claim, only the worker holding the current lease writes the job
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(), -- bumped by progress and heartbeats, a stale running job is picked up again
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_status_created_at ON product_import_jobs (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_product_import_jobs_shop_id_created_at ON product_import_jobs (shop_id, created_at DESC);
@@ -0,0 +1,22 @@
+-- domain events written in the transaction of the change they describe, the
+-- relay worker delivers them to the configured sink
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY, -- delivery order, events of an aggregate are delivered one at a time in this order
+    event_id VARCHAR(26) NOT NULL, -- ulid, sent along so consumers can drop duplicates
+    type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    locked_until TIMESTAMP WITH TIME ZONE, -- lease of the relay delivering it
+    published_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT uq_outbox_events_event_id UNIQUE (event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending_aggregate ON outbox_events (aggregate_type, aggregate_id, id) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS stock_alerts;
+
+DROP INDEX IF EXISTS idx_products_low_stock;
+
+ALTER TABLE shops DROP COLUMN IF EXISTS contact_email;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS stock_level,
+    DROP COLUMN IF EXISTS low_stock_threshold;
@@ -0,0 +1,31 @@
+-- stock_level is the level last alerted for, a change to a lower level
+-- records one alert and going back above the threshold rearms it
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS low_stock_threshold INT CHECK (low_stock_threshold >= 0), -- NULL: only alert when sold out
+    ADD COLUMN IF NOT EXISTS stock_level VARCHAR(10) NOT NULL DEFAULT 'ok' CHECK (stock_level IN ('ok', 'low', 'out'));
+
+-- products already sold out do not alert on deploy
+UPDATE products SET stock_level = 'out' WHERE stock <= 0;
+
+-- the alerts are emailed there when the account of the owner is gone
+ALTER TABLE shops ADD COLUMN IF NOT EXISTS contact_email VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_products_low_stock ON products (shop_id, stock) WHERE stock_level <> 'ok' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS stock_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL,
+    shop_id UUID NOT NULL,
+    level VARCHAR(10) NOT NULL CHECK (level IN ('low', 'out')),
+    stock INT NOT NULL,
+    threshold INT,
+    notified_at TIMESTAMP WITH TIME ZONE, -- NULL until its event and notification are recorded
+    emailed_at TIMESTAMP WITH TIME ZONE, -- NULL until the outbox relay emailed it
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (shop_id) REFERENCES shops(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_pending ON stock_alerts (id) WHERE notified_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_stock_alerts_product_id ON stock_alerts (product_id);
@@ -11,21 +11,26 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// ShopeefunPostgresDSN returns the connection string of the Shopeefun
+// Postgres, also used to open a dedicated connection to LISTEN on.
+func ShopeefunPostgresDSN() string {
+	dbUser := config.Envs.ShopeefunPostgres.Username
+	dbPassword := config.Envs.ShopeefunPostgres.Password
+	dbName := config.Envs.ShopeefunPostgres.Database
+	dbHost := config.Envs.ShopeefunPostgres.Host
+	dbSSLMode := config.Envs.ShopeefunPostgres.SslMode
+	dbPort := config.Envs.ShopeefunPostgres.Port
+
+	return "user=" + dbUser + " password=" + dbPassword + " host=" + dbHost + " port=" + dbPort + " dbname=" + dbName + " sslmode=" + dbSSLMode + " TimeZone=UTC"
+}
+
 func WithShopeefunPostgres() Option {
 	return func(a *Adapter) {
-		dbUser := config.Envs.ShopeefunPostgres.Username
-		dbPassword := config.Envs.ShopeefunPostgres.Password
-		dbName := config.Envs.ShopeefunPostgres.Database
-		dbHost := config.Envs.ShopeefunPostgres.Host
-		dbSSLMode := config.Envs.ShopeefunPostgres.SslMode
-		dbPort := config.Envs.ShopeefunPostgres.Port
-
 		dbMaxPoolSize := config.Envs.DB.MaxOpenCons
 		dbMaxIdleConns := config.Envs.DB.MaxIdleCons
 		dbConnMaxLifetime := config.Envs.DB.ConnMaxLifetime
 
-		connectionString := "user=" + dbUser + " password=" + dbPassword + " host=" + dbHost + " port=" + dbPort + " dbname=" + dbName + " sslmode=" + dbSSLMode + " TimeZone=UTC"
-		db, err := sqlx.Connect("postgres", connectionString)
+		db, err := sqlx.Connect("postgres", ShopeefunPostgresDSN())
 		if err != nil {
 			log.Fatal().Err(err).Msg("Error connecting to Postgres")
 		}
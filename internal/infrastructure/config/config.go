@@ -35,6 +35,9 @@ type Config struct {
 		JwtPrivateKey   string `env:"JWT_PRIVATE_KEY"`
 		JwtPrivateKeyWs string `env:"JWT_PRIVATE_KEY_WS"`
 		JwtWsExp        int    `env:"JWT_WS_EXP" env-default:"10"` // 10 seconds
+
+		ServiceSigningKeys   map[string]string `env:"SERVICE_SIGNING_KEYS"`                     // key_id:secret,key_id2:secret2
+		ServiceSignatureSkew int               `env:"SERVICE_SIGNATURE_SKEW" env-default:"300"` // seconds
 	}
 	ShopeefunPostgres struct {
 		Host     string `env:"SHOPEEFUN_POSTGRES_HOST" env-default:"localhost"`
@@ -51,6 +54,33 @@ type Config struct {
 		Region   string `env:"SHOPEEFUN_STORAGE_REGION"`
 		Bucket   string `env:"SHOPEEFUN_STORAGE_BUCKET"`
 	}
+	Mail struct {
+		Host     string `env:"MAIL_HOST"`
+		Port     string `env:"MAIL_PORT" env-default:"587"`
+		Username string `env:"MAIL_USERNAME"`
+		Password string `env:"MAIL_PASSWORD"`
+		From     string `env:"MAIL_FROM" env-default:"no-reply@shopeefun.local"`
+	}
+	Outbox struct {
+		Sink          string `env:"OUTBOX_SINK" env-default:"log"` // log, stdout, http or nats
+		HTTPURL       string `env:"OUTBOX_HTTP_URL"`
+		NATSURL       string `env:"OUTBOX_NATS_URL" env-default:"nats://localhost:4222"`
+		NATSSubject   string `env:"OUTBOX_NATS_SUBJECT" env-default:"shopeefun"` // events go to <subject>.<type>
+		BatchSize     int    `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+		RetentionDays int    `env:"OUTBOX_RETENTION_DAYS" env-default:"7"` // published events are purged after
+	}
+	Webhook struct {
+		Timeout   int `env:"WEBHOOK_TIMEOUT" env-default:"10"` // seconds a receiver has to answer a delivery
+		BatchSize int `env:"WEBHOOK_BATCH_SIZE" env-default:"50"`
+
+		AllowLoopback bool `env:"WEBHOOK_ALLOW_LOOPBACK" env-default:"false"` // lets webhooks target localhost, for tests and local development only
+	}
+	Trash struct {
+		RetentionDays int `env:"TRASH_RETENTION_DAYS" env-default:"30"` // deleted products and shops can be restored for that long, then they are purged
+	}
+	Frontend struct {
+		ClientBaseURL string `env:"FRONTEND_CLIENT_BASE_URL" env-default:"http://localhost:5000"`
+	}
 	Oauth struct {
 		Google struct {
 			ClientId     string `env:"GOOGLE_CLIENT_ID"`
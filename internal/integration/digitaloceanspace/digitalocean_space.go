@@ -17,6 +17,7 @@ import (
 
 type DigitaloceanSpaceContract interface {
 	UploadFile(ctx context.Context, req *entity.UploadFileRequest) (entity.UploadFileResponse, error)
+	PutFile(ctx context.Context, req *entity.PutFileRequest) (entity.UploadFileResponse, error)
 	DeleteFile(ctx context.Context, req *entity.DeleteFileRequest) error
 	ListFiles(ctx context.Context) ([]types.Object, error)
 }
@@ -67,6 +68,33 @@ func (d *dospace) UploadFile(ctx context.Context, req *entity.UploadFileRequest)
 	return res, nil
 }
 
+// PutFile uploads a public file under a fixed key, replacing the previous one.
+// The body is streamed in parts, so its size does not need to be known.
+func (d *dospace) PutFile(ctx context.Context, req *entity.PutFileRequest) (entity.UploadFileResponse, error) {
+	var res = entity.UploadFileResponse{}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(config.Envs.ShopeefunStorage.Bucket),
+		Key:    aws.String(req.Key),
+		Body:   req.Body,
+		ACL:    types.ObjectCannedACLPublicRead,
+	}
+	if req.ContentType != "" {
+		input.ContentType = aws.String(req.ContentType)
+	}
+
+	result, err := manager.NewUploader(d.storage).Upload(ctx, input)
+	if err != nil {
+		log.Error().Err(err).Str("key", req.Key).Msg("integration::dospace-PutFile Error while uploading file")
+		return res, err
+	}
+
+	res.FileName = req.Key
+	res.Url = result.Location
+
+	return res, nil
+}
+
 func (d *dospace) DeleteFile(ctx context.Context, req *entity.DeleteFileRequest) error {
 	_, err := d.storage.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(config.Envs.ShopeefunStorage.Bucket),
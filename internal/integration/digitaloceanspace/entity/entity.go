@@ -1,6 +1,9 @@
 package entity
 
-import "mime/multipart"
+import (
+	"io"
+	"mime/multipart"
+)
 
 type XxxRequest struct {
 }
@@ -20,3 +23,9 @@ type UploadFileResponse struct {
 type DeleteFileRequest struct {
 	FileName string `json:"filename" validate:"required"`
 }
+
+type PutFileRequest struct {
+	Key         string    `json:"key" validate:"required"`
+	ContentType string    `json:"content_type"`
+	Body        io.Reader `json:"-" validate:"required"`
+}
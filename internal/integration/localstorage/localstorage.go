@@ -4,8 +4,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -15,6 +17,7 @@ import (
 
 type LocalStorageContract interface {
 	Save(base64String, path string) (fullpath string, err error)
+	Remove(fullpath string) error
 }
 
 var (
@@ -66,6 +69,62 @@ func (l *localstorage) Save(base64String, path string) (fullpath string, err err
 	return fullpath, nil
 }
 
+// WriteFile writes r to fullpath. The content goes to a temporary file first,
+// so readers of fullpath never see a partial file.
+func WriteFile(fullpath string, r io.Reader) error {
+	dir := filepath.Dir(fullpath)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		log.Error().Err(err).Msg("localstorage: failed to create directory")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(fullpath)+".*")
+	if err != nil {
+		log.Error().Err(err).Msg("localstorage: failed to create file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		log.Error().Err(err).Msg("localstorage: failed to write data to file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		log.Error().Err(err).Msg("localstorage: failed to write data to file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	// CreateTemp makes the file private, the public storage is served as is
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		log.Error().Err(err).Msg("localstorage: failed to write data to file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), fullpath); err != nil {
+		log.Error().Err(err).Msg("localstorage: failed to rename file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	return nil
+}
+
+func (l *localstorage) Remove(fullpath string) error {
+	return RemoveFile(fullpath)
+}
+
+// RemoveFile deletes the file at fullpath, a file already gone is not an error.
+func RemoveFile(fullpath string) error {
+	if err := os.Remove(fullpath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error().Err(err).Msg("localstorage: failed to remove file")
+		return fmt.Errorf("localstorage: %w", err)
+	}
+
+	return nil
+}
+
 func (l *localstorage) saveFile(fullpath string, data []byte) error {
 	path := strings.Split(fullpath, "/")         // Split path by "/"
 	dir := strings.Join(path[:len(path)-1], "/") // Join path except the last element
@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+type MailerContract interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+type mailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewMailerIntegration returns a mailer backed by the configured SMTP server.
+// When no SMTP host is configured, messages are only written to the log so
+// that local development does not require a mail server.
+func NewMailerIntegration() MailerContract {
+	env := config.Envs.Mail
+
+	return &mailer{
+		host:     env.Host,
+		port:     env.Port,
+		username: env.Username,
+		password: env.Password,
+		from:     env.From,
+	}
+}
+
+func (m *mailer) Send(ctx context.Context, to, subject, body string) error {
+	if m.host == "" {
+		log.Info().Str("to", to).Str("subject", subject).Str("body", body).Msg("integration::mailer-Send SMTP not configured, mail logged only")
+		return nil
+	}
+
+	var (
+		addr = m.host + ":" + m.port
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+		msg  = strings.Join([]string{
+			"From: " + m.from,
+			"To: " + to,
+			"Subject: " + subject,
+			"MIME-Version: 1.0",
+			"Content-Type: text/plain; charset=\"UTF-8\"",
+			"",
+			body,
+		}, "\r\n")
+	)
+
+	if err := smtp.SendMail(addr, auth, m.from, []string{to}, []byte(msg)); err != nil {
+		log.Error().Err(err).Str("to", to).Str("subject", subject).Msg("integration::mailer-Send Error while sending mail")
+		return fmt.Errorf("mailer: %w", err)
+	}
+
+	return nil
+}
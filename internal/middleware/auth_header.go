@@ -32,6 +32,11 @@ func AuthBearer(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
 	}
 
+	if isTokenRevoked(c.Context(), claims.UserId, claims.TokenVersion) {
+		log.Warn().Str("user_id", claims.UserId).Msg("middleware::AuthMiddleware - Token has been revoked")
+		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
+	}
+
 	c.Locals("user_id", claims.UserId)
 	c.Locals("role", claims.Role)
 
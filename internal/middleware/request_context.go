@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oklog/ulid/v2"
+)
+
+// requestIdPattern matches the ids kept from the X-Request-ID header: an
+// ULID, an UUID or a hex trace id. Anything else is replaced, the id ends up
+// in the logs and the audit log.
+var requestIdPattern = regexp.MustCompile(`^[0-9A-Za-z-]{16,64}$`)
+
+// RequestContext stores the request id and client ip in the locals so that
+// lower layers (e.g. the audit log) can read them from the request context.
+func RequestContext(c *fiber.Ctx) error {
+	requestId := c.Get("X-Request-ID")
+	if !requestIdPattern.MatchString(requestId) {
+		requestId = ulid.Make().String()
+	}
+
+	c.Locals("request_id", requestId)
+	c.Locals("ip", c.IP())
+	c.Set("X-Request-ID", requestId)
+
+	return c.Next()
+}
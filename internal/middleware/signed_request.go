@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/pkg/signature"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	nonceCache     *signature.NonceCache
+	nonceCacheOnce sync.Once
+)
+
+func getNonceCache(skew time.Duration) *signature.NonceCache {
+	nonceCacheOnce.Do(func() {
+		// a nonce only has to be remembered while its timestamp is acceptable.
+		// The cache is per process, see signature.NonceCache.
+		nonceCache = signature.NewNonceCache(2 * skew)
+	})
+
+	return nonceCache
+}
+
+// SignedRequest authenticates internal services. Callers sign the method, path,
+// timestamp, nonce and body hash with a shared key identified by key id, see
+// signature.SignRequest.
+func SignedRequest(c *fiber.Ctx) error {
+	var (
+		keyId     = c.Get(signature.HeaderKeyId)
+		timestamp = c.Get(signature.HeaderTimestamp)
+		nonce     = c.Get(signature.HeaderNonce)
+		sign      = c.Get(signature.HeaderSignature)
+		skew      = time.Duration(config.Envs.Guard.ServiceSignatureSkew) * time.Second
+
+		ErrSignatureNotValid = fiber.Map{
+			"success": false,
+			"message": "Signature not valid",
+		}
+	)
+
+	if keyId == "" || timestamp == "" || nonce == "" || sign == "" {
+		log.Warn().Msg("middleware::SignedRequest - Unauthorized [Header not set]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	secret, ok := config.Envs.Guard.ServiceSigningKeys[keyId]
+	if !ok || secret == "" {
+		log.Warn().Str("key_id", keyId).Msg("middleware::SignedRequest - Unauthorized [Unknown key id]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		log.Warn().Str("key_id", keyId).Msg("middleware::SignedRequest - Unauthorized [Invalid timestamp]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	if diff := time.Since(time.Unix(ts, 0)); diff > skew || diff < -skew {
+		log.Warn().Str("key_id", keyId).Dur("diff", diff).Msg("middleware::SignedRequest - Unauthorized [Timestamp outside window]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	if !signature.Verify(secret, sign, c.Method(), c.OriginalURL(), timestamp, nonce, c.Body()) {
+		log.Warn().Str("key_id", keyId).Msg("middleware::SignedRequest - Unauthorized [Signature mismatch]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	// checked last so an attacker cannot burn nonces with invalid signatures
+	if !getNonceCache(skew).Use(keyId + ":" + nonce) {
+		log.Warn().Str("key_id", keyId).Msg("middleware::SignedRequest - Unauthorized [Nonce reused]")
+		return c.Status(fiber.StatusUnauthorized).JSON(ErrSignatureNotValid)
+	}
+
+	c.Locals("role", "service")
+	c.Locals("service_key_id", keyId)
+
+	return c.Next()
+}
+
+// AuthServiceOrApiKey accepts either a signed internal request, a personal api
+// key or the bearer token of a user, depending on which credentials the caller
+// sent.
+func AuthServiceOrApiKey(c *fiber.Ctx) error {
+	if c.Get(signature.HeaderKeyId) != "" {
+		return SignedRequest(c)
+	}
+
+	return AuthApiKeyOrBearer(c)
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"codebase-app/internal/adapter"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// tokenStateTTL is how long the token state of a user is cached. The cache
+	// is per process, so a revoked token can be accepted for up to this long by
+	// an instance that cached the state before the revocation.
+	tokenStateTTL = 30 * time.Second
+
+	// tokenStateMaxSize bounds the cache, it is reset once full.
+	tokenStateMaxSize = 10000
+)
+
+type tokenState struct {
+	version   int
+	deleted   bool
+	expiresAt time.Time
+}
+
+var tokenStates = struct {
+	sync.Mutex
+	m map[string]tokenState
+}{m: make(map[string]tokenState)}
+
+// isTokenRevoked reports whether a token carrying the given version is no
+// longer valid for the user, either because the user changed their password
+// or deleted their account after the token was issued.
+func isTokenRevoked(ctx context.Context, userId string, version int) bool {
+	state, ok := getTokenState(ctx, userId)
+	if !ok {
+		return true
+	}
+
+	return state.deleted || version < state.version
+}
+
+func getTokenState(ctx context.Context, userId string) (tokenState, bool) {
+	now := time.Now()
+
+	tokenStates.Lock()
+	state, ok := tokenStates.m[userId]
+	tokenStates.Unlock()
+	if ok && now.Before(state.expiresAt) {
+		return state, true
+	}
+
+	db := adapter.Adapters.ShopeefunPostgres
+	if db == nil {
+		return tokenState{}, true
+	}
+
+	var row struct {
+		Version int  `db:"token_version"`
+		Deleted bool `db:"deleted"`
+	}
+
+	query := `
+		SELECT token_version, deleted_at IS NOT NULL AS deleted
+		FROM users
+		WHERE id = $1
+	`
+
+	err := db.GetContext(ctx, &row, query, userId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Str("user_id", userId).Msg("middleware::isTokenRevoked - Failed to get user")
+		return tokenState{}, false
+	}
+
+	state = tokenState{version: row.Version, deleted: row.Deleted, expiresAt: now.Add(tokenStateTTL)}
+
+	tokenStates.Lock()
+	if len(tokenStates.m) >= tokenStateMaxSize {
+		tokenStates.m = make(map[string]tokenState)
+	}
+	tokenStates.m[userId] = state
+	tokenStates.Unlock()
+
+	return state, true
+}
@@ -8,6 +8,11 @@ import (
 type Locals struct {
 	UserId string
 	Role   string
+
+	// set only when the request is authenticated with an api key
+	ApiKeyId string
+	ShopId   string
+	Scopes   []string
 }
 
 func GetLocals(c *fiber.Ctx) *Locals {
@@ -19,6 +24,16 @@ func GetLocals(c *fiber.Ctx) *Locals {
 		log.Warn().Msg("middleware::Locals-GetLocals failed to get user_id from locals")
 	}
 
+	if role, ok := c.Locals("role").(string); ok {
+		l.Role = role
+	}
+
+	if apiKeyId, ok := c.Locals("api_key_id").(string); ok {
+		l.ApiKeyId = apiKeyId
+		l.ShopId, _ = c.Locals("api_key_shop_id").(string)
+		l.Scopes, _ = c.Locals("api_key_scopes").([]string)
+	}
+
 	return &l
 }
 
@@ -29,3 +44,13 @@ func (l *Locals) GetUserId() string {
 func (l *Locals) GetRole() string {
 	return l.Role
 }
+
+func (l *Locals) HasScope(scope string) bool {
+	for _, s := range l.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
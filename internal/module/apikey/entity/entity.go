@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	// KeyPrefix is prepended to every generated key so leaked keys are easy to spot.
+	KeyPrefix = "sfk"
+
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+	ScopeStocksWrite   = "stocks:write"
+	ScopeShopsWrite    = "shops:write"
+)
+
+type CreateApiKeyRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Name      string     `json:"name" validate:"required,max=255"`
+	ShopId    *string    `json:"shop_id" validate:"omitempty,uuid"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,unique_in_slice,dive,oneof=products:read products:write stocks:write shops:write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+
+	Prefix     string
+	SecretHash string
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey
+	Key string `json:"key"` // only returned once, on creation
+}
+
+type GetApiKeysRequest struct {
+	UserId string `validate:"required,uuid"`
+}
+
+type RevokeApiKeyRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+type ApiKey struct {
+	Id         string     `json:"id" db:"id"`
+	UserId     string     `json:"user_id" db:"user_id"`
+	ShopId     *string    `json:"shop_id" db:"shop_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	Scopes     []string   `json:"scopes" db:"-"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
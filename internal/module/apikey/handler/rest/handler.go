@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/apikey/entity"
+	"codebase-app/internal/module/apikey/ports"
+	"codebase-app/internal/module/apikey/repository"
+	"codebase-app/internal/module/apikey/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type apiKeyHandler struct {
+	service ports.ApiKeyService
+}
+
+func NewApiKeyHandler() *apiKeyHandler {
+	var (
+		handler = new(apiKeyHandler)
+		repo    = repository.NewApiKeyRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewApiKeyService(repo)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *apiKeyHandler) Register(router fiber.Router) {
+	router.Get("/api-keys", middleware.AuthBearer, h.GetApiKeys)
+	router.Post("/api-keys", middleware.AuthBearer, h.CreateApiKey)
+	router.Delete("/api-keys/:id", middleware.AuthBearer, h.RevokeApiKey)
+}
+
+func (h *apiKeyHandler) CreateApiKey(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateApiKeyRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::CreateApiKey - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateApiKey - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateApiKey(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, "Simpan key ini, key tidak akan ditampilkan lagi"))
+}
+
+func (h *apiKeyHandler) GetApiKeys(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetApiKeysRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetApiKeys - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetApiKeys(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *apiKeyHandler) RevokeApiKey(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RevokeApiKeyRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::RevokeApiKey - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.RevokeApiKey(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
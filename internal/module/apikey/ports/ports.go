@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"codebase-app/internal/module/apikey/entity"
+	"context"
+)
+
+type ApiKeyRepository interface {
+	CreateApiKey(ctx context.Context, req *entity.CreateApiKeyRequest) (*entity.ApiKey, error)
+	GetApiKeys(ctx context.Context, req *entity.GetApiKeysRequest) ([]entity.ApiKey, error)
+	RevokeApiKey(ctx context.Context, req *entity.RevokeApiKeyRequest) error
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+}
+
+type ApiKeyService interface {
+	CreateApiKey(ctx context.Context, req *entity.CreateApiKeyRequest) (*entity.CreateApiKeyResponse, error)
+	GetApiKeys(ctx context.Context, req *entity.GetApiKeysRequest) ([]entity.ApiKey, error)
+	RevokeApiKey(ctx context.Context, req *entity.RevokeApiKeyRequest) error
+}
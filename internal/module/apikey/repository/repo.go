@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"codebase-app/internal/module/apikey/entity"
+	"codebase-app/internal/module/apikey/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.ApiKeyRepository = &apiKeyRepository{}
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewApiKeyRepository(db *sqlx.DB) *apiKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+type apiKeyDao struct {
+	entity.ApiKey
+	Scopes pq.StringArray `db:"scopes"`
+}
+
+func (d apiKeyDao) toEntity() entity.ApiKey {
+	res := d.ApiKey
+	res.Scopes = []string(d.Scopes)
+	return res
+}
+
+func (r *apiKeyRepository) CreateApiKey(ctx context.Context, req *entity.CreateApiKeyRequest) (*entity.ApiKey, error) {
+	var data apiKeyDao
+
+	query := `
+		INSERT INTO api_keys (user_id, shop_id, name, prefix, secret_hash, scopes, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING
+			id, user_id, shop_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.UserId,
+		req.ShopId,
+		req.Name,
+		req.Prefix,
+		req.SecretHash,
+		pq.StringArray(req.Scopes),
+		req.ExpiresAt,
+	).StructScan(&data)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", req.UserId).Msg("repository::CreateApiKey - Failed to create api key")
+		return nil, err
+	}
+
+	res := data.toEntity()
+	return &res, nil
+}
+
+func (r *apiKeyRepository) GetApiKeys(ctx context.Context, req *entity.GetApiKeysRequest) ([]entity.ApiKey, error) {
+	var (
+		data = make([]apiKeyDao, 0)
+		res  = make([]entity.ApiKey, 0)
+	)
+
+	query := `
+		SELECT
+			id, user_id, shop_id, name, prefix, scopes, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetApiKeys - Failed to get api keys")
+		return nil, err
+	}
+
+	for _, d := range data {
+		res = append(res, d.toEntity())
+	}
+
+	return res, nil
+}
+
+func (r *apiKeyRepository) RevokeApiKey(ctx context.Context, req *entity.RevokeApiKeyRequest) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = NOW(), updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RevokeApiKey - Failed to revoke api key")
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository::RevokeApiKey - Api key not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Api key tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	var isOwner bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM shops
+			WHERE
+				user_id = ?
+				AND id = ?
+				AND deleted_at IS NULL
+		)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, r.db.Rebind(query), userId, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId).Str("shop_id", shopId).Msg("repository::IsShopOwner - Failed to check shop owner")
+		return false, err
+	}
+
+	return isOwner, nil
+}
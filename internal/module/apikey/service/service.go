@@ -0,0 +1,75 @@
+package service
+
+import (
+	"codebase-app/internal/module/apikey/entity"
+	"codebase-app/internal/module/apikey/ports"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.ApiKeyService = &apiKeyService{}
+
+type apiKeyService struct {
+	repo ports.ApiKeyRepository
+}
+
+func NewApiKeyService(repo ports.ApiKeyRepository) *apiKeyService {
+	return &apiKeyService{
+		repo: repo,
+	}
+}
+
+func (s *apiKeyService) CreateApiKey(ctx context.Context, req *entity.CreateApiKeyRequest) (*entity.CreateApiKeyResponse, error) {
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("expires_at", "expires at harus di masa depan."))
+	}
+
+	if req.ShopId != nil {
+		isOwner, err := s.repo.IsShopOwner(ctx, req.UserId, *req.ShopId)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isOwner {
+			log.Warn().Any("payload", req).Msg("service::CreateApiKey - User is not shop owner")
+			return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+		}
+	}
+
+	prefix, err := pkg.GenerateRandomToken(6)
+	if err != nil {
+		log.Error().Err(err).Msg("service::CreateApiKey - Failed to generate prefix")
+		return nil, err
+	}
+
+	secret, err := pkg.GenerateRandomToken(32)
+	if err != nil {
+		log.Error().Err(err).Msg("service::CreateApiKey - Failed to generate secret")
+		return nil, err
+	}
+
+	req.Prefix = entity.KeyPrefix + "_" + prefix
+	req.SecretHash = pkg.HashToken(secret)
+
+	key, err := s.repo.CreateApiKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.CreateApiKeyResponse{
+		ApiKey: *key,
+		Key:    req.Prefix + "_" + secret,
+	}, nil
+}
+
+func (s *apiKeyService) GetApiKeys(ctx context.Context, req *entity.GetApiKeysRequest) ([]entity.ApiKey, error) {
+	return s.repo.GetApiKeys(ctx, req)
+}
+
+func (s *apiKeyService) RevokeApiKey(ctx context.Context, req *entity.RevokeApiKeyRequest) error {
+	return s.repo.RevokeApiKey(ctx, req)
+}
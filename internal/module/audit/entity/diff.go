@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Diff returns the fields whose values differ between two snapshots, keyed by
+// their json name.
+func Diff(before, after any) (map[string]Change, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]Change)
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok || !reflect.DeepEqual(av, bv) {
+			diff[k] = Change{From: bv, To: av}
+		}
+	}
+
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			diff[k] = Change{From: bv, To: nil}
+		}
+	}
+
+	return diff, nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	m := make(map[string]any)
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return m, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type product struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+		Stock int     `json:"stock"`
+	}
+
+	diff, err := Diff(&product{"A", 1000, 10}, &product{"A", 1500, 10})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Change{"price": {From: float64(1000), To: float64(1500)}}, diff)
+
+	diff, err = Diff(nil, &product{"A", 1000, 10})
+	assert.NoError(t, err)
+	assert.Len(t, diff, 3)
+	assert.Equal(t, Change{From: nil, To: "A"}, diff["name"])
+
+	var none *product
+	diff, err = Diff(&product{"A", 1000, 10}, none)
+	assert.NoError(t, err)
+	assert.Equal(t, Change{From: float64(10), To: nil}, diff["stock"])
+}
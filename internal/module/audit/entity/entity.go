@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+
+	EntityProduct = "product"
+	EntityShop    = "shop"
+	EntityUser    = "user"
+	EntityReview  = "review"
+
+	EntityExchangeRate = "exchange_rate"
+)
+
+// Entry is a single audited change. Before and After are any json
+// serializable snapshot of the entity, nil when it did not exist.
+type Entry struct {
+	Action     string
+	EntityType string
+	EntityId   string
+	Before     any
+	After      any
+}
+
+type GetAuditLogsRequest struct {
+	ActorId    string `query:"actor_id" validate:"omitempty,max=255"`
+	Action     string `query:"action" validate:"omitempty,oneof=create update delete restore"`
+	EntityType string `query:"entity_type" validate:"omitempty,max=50"`
+	EntityId   string `query:"entity_id" validate:"omitempty,max=255"`
+	RequestId  string `query:"request_id" validate:"omitempty,max=255"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+
+	Page     int `query:"page" validate:"required"`
+	Paginate int `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetAuditLogsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 20
+	}
+}
+
+type AuditLog struct {
+	Id         string          `json:"id" db:"id"`
+	ActorId    *string         `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	EntityType string          `json:"entity_type" db:"entity_type"`
+	EntityId   string          `json:"entity_id" db:"entity_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	Diff       json.RawMessage `json:"diff" db:"diff"`
+	RequestId  *string         `json:"request_id" db:"request_id"`
+	Ip         *string         `json:"ip" db:"ip"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+type GetAuditLogsResponse struct {
+	Items []AuditLog `json:"items"`
+	Meta  types.Meta `json:"meta"`
+}
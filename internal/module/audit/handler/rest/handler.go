@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/audit/entity"
+	"codebase-app/internal/module/audit/ports"
+	"codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/audit/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type auditHandler struct {
+	service ports.AuditService
+}
+
+func NewAuditHandler() *auditHandler {
+	var (
+		handler = new(auditHandler)
+		repo    = repository.NewAuditRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewAuditService(repo)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *auditHandler) Register(router fiber.Router) {
+	router.Get("/admin/audit-logs", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.GetAuditLogs)
+}
+
+func (h *auditHandler) GetAuditLogs(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetAuditLogsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetAuditLogs - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetAuditLogs - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetAuditLogs(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"codebase-app/internal/module/audit/entity"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// Record writes an audit entry using the given transaction so the entry is
+// committed or rolled back together with the audited change. The actor,
+// request id and ip are taken from the request locals carried by ctx.
+func Record(ctx context.Context, tx sqlx.ExecerContext, e *entity.Entry) error {
+	diff, err := entity.Diff(e.Before, e.After)
+	if err != nil {
+		log.Error().Err(err).Any("payload", e).Msg("repository::audit-Record - Failed to compute diff")
+		return err
+	}
+
+	before, err := marshalSnapshot(e.Before)
+	if err != nil {
+		log.Error().Err(err).Any("payload", e).Msg("repository::audit-Record - Failed to marshal before")
+		return err
+	}
+
+	after, err := marshalSnapshot(e.After)
+	if err != nil {
+		log.Error().Err(err).Any("payload", e).Msg("repository::audit-Record - Failed to marshal after")
+		return err
+	}
+
+	diffJson, err := json.Marshal(diff)
+	if err != nil {
+		log.Error().Err(err).Any("payload", e).Msg("repository::audit-Record - Failed to marshal diff")
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_logs (actor_id, action, entity_type, entity_id, before, after, diff, request_id, ip)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = tx.ExecContext(ctx, query,
+		ActorFromContext(ctx),
+		e.Action,
+		e.EntityType,
+		e.EntityId,
+		before,
+		after,
+		diffJson,
+		stringFromContext(ctx, "request_id"),
+		stringFromContext(ctx, "ip"),
+	)
+	if err != nil {
+		log.Error().Err(err).Str("entity_type", e.EntityType).Str("entity_id", e.EntityId).Msg("repository::audit-Record - Failed to insert audit log")
+		return err
+	}
+
+	return nil
+}
+
+func marshalSnapshot(v any) ([]byte, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+
+	return json.Marshal(v)
+}
+
+// ActorFromContext returns the user or service key that made the request
+// carried by ctx, nil for background jobs not acting for a user.
+func ActorFromContext(ctx context.Context) *string {
+	if userId, ok := ctx.Value(actorKey{}).(string); ok {
+		return &userId
+	}
+
+	if userId := stringFromContext(ctx, "user_id"); userId != nil {
+		return userId
+	}
+
+	if keyId := stringFromContext(ctx, "service_key_id"); keyId != nil {
+		actor := "service:" + *keyId
+		return &actor
+	}
+
+	return nil
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx acting as userId, for background jobs
+// working on behalf of the user who requested them.
+func WithActor(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userId)
+}
+
+func stringFromContext(ctx context.Context, key string) *string {
+	if v, ok := ctx.Value(key).(string); ok && v != "" {
+		return &v
+	}
+
+	return nil
+}
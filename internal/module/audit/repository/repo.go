@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"codebase-app/internal/module/audit/entity"
+	"codebase-app/internal/module/audit/ports"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.AuditRepository = &auditRepository{}
+
+type auditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *auditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) GetAuditLogs(ctx context.Context, req *entity.GetAuditLogsRequest) (*entity.GetAuditLogsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.AuditLog
+	}
+
+	var (
+		resp = new(entity.GetAuditLogsResponse)
+		data = make([]dao, 0, req.Paginate)
+		args = make([]any, 0)
+	)
+	resp.Items = make([]entity.AuditLog, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			actor_id,
+			action,
+			entity_type,
+			entity_id,
+			before,
+			after,
+			diff,
+			request_id,
+			ip,
+			created_at
+		FROM audit_logs
+		WHERE 1 = 1
+	`
+
+	if req.ActorId != "" {
+		query += " AND actor_id = ?"
+		args = append(args, req.ActorId)
+	}
+
+	if req.Action != "" {
+		query += " AND action = ?"
+		args = append(args, req.Action)
+	}
+
+	if req.EntityType != "" {
+		query += " AND entity_type = ?"
+		args = append(args, req.EntityType)
+	}
+
+	if req.EntityId != "" {
+		query += " AND entity_id = ?"
+		args = append(args, req.EntityId)
+	}
+
+	if req.RequestId != "" {
+		query += " AND request_id = ?"
+		args = append(args, req.RequestId)
+	}
+
+	if req.From != "" {
+		query += " AND created_at >= ?"
+		args = append(args, req.From)
+	}
+
+	if req.To != "" {
+		query += " AND created_at <= ?"
+		args = append(args, req.To)
+	}
+
+	query += `
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetAuditLogs - Failed to get audit logs")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.AuditLog)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"encoding/json"
+	"time"
+)
+
+// Types of the notifications, the client picks an icon and a link from it.
+const (
+	TypeStockLow          = "stock.low"
+	TypeStockOut          = "stock.out"
+	TypeReviewCreated     = "review.created"
+	TypeReviewModerated   = "review.moderated"
+	TypeShopStatusChanged = "shop.status_changed"
+)
+
+// Channel is the Postgres channel a Signal is sent on whenever the
+// notifications of a user change.
+const Channel = "notifications"
+
+// Types of the messages pushed to the websocket clients.
+const (
+	MessageNotification = "notification"
+	MessageUnreadCount  = "unread_count"
+)
+
+// Notification is an in-app notification of a user.
+type Notification struct {
+	Id        string          `json:"id" db:"id"`
+	UserId    string          `json:"-" db:"user_id"`
+	Type      string          `json:"type" db:"type"`
+	Title     string          `json:"title" db:"title"`
+	Body      string          `json:"body" db:"body"`
+	Data      json.RawMessage `json:"data" db:"data"`
+	ReadAt    *time.Time      `json:"read_at" db:"read_at"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Signal is the payload sent on Channel. Id is set when a notification was
+// created, empty when notifications were marked read.
+type Signal struct {
+	UserId string `json:"user_id"`
+	Id     string `json:"id,omitempty"`
+}
+
+// Message is pushed to the websocket clients of a user.
+type Message struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type UnreadCount struct {
+	Count int `json:"count"`
+}
+
+type GetNotificationsRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Unread   bool `query:"unread"` // only the notifications not read yet
+	Page     int  `query:"page" validate:"required"`
+	Paginate int  `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetNotificationsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 20
+	}
+}
+
+type GetNotificationsResponse struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int            `json:"unread_count"`
+	Meta        types.Meta     `json:"meta"`
+}
+
+type NotificationRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+type MarkAllReadRequest struct {
+	UserId string `validate:"required,uuid"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int `json:"updated"`
+}
+
+type WsTokenRequest struct {
+	UserId string `validate:"required,uuid"`
+	Role   string
+}
+
+type WsTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// New returns a notification of the user with data marshaled, ex: the ids the
+// client links to.
+func New(userId, typ, title, body string, data any) *Notification {
+	raw, _ := json.Marshal(data)
+
+	return &Notification{
+		UserId: userId,
+		Type:   typ,
+		Title:  title,
+		Body:   body,
+		Data:   raw,
+	}
+}
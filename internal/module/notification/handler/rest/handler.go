@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/internal/module/notification/ports"
+	"codebase-app/internal/module/notification/repository"
+	"codebase-app/internal/module/notification/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type notificationHandler struct {
+	service ports.NotificationService
+}
+
+func NewNotificationHandler() *notificationHandler {
+	var (
+		handler = new(notificationHandler)
+		repo    = repository.NewNotificationRepository(adapter.Adapters.ShopeefunPostgres)
+		service = service.NewNotificationService(repo)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *notificationHandler) Register(router fiber.Router) {
+	router.Get("/notifications", middleware.AuthBearer, h.GetNotifications)
+	router.Post("/notifications/read-all", middleware.AuthBearer, h.MarkAllRead)
+	router.Post("/notifications/ws-token", middleware.AuthBearer, h.CreateWsToken)
+	router.Post("/notifications/:id/read", middleware.AuthBearer, h.MarkRead)
+}
+
+func (h *notificationHandler) GetNotifications(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetNotificationsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetNotifications - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetNotifications - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetNotifications(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *notificationHandler) MarkRead(c *fiber.Ctx) error {
+	var (
+		req = new(entity.NotificationRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::MarkRead - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.MarkRead(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *notificationHandler) MarkAllRead(c *fiber.Ctx) error {
+	var (
+		req = new(entity.MarkAllReadRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::MarkAllRead - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.MarkAllRead(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *notificationHandler) CreateWsToken(c *fiber.Ctx) error {
+	var (
+		req = new(entity.WsTokenRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.Role = l.Role
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateWsToken - Validate request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateWsToken(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,261 @@
+package ws
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/internal/module/notification/ports"
+	"codebase-app/internal/module/notification/repository"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// sendBuffer is how many messages a client can lag behind before it is
+	// disconnected.
+	sendBuffer = 16
+
+	// writeTimeout bounds the write of a message to a client.
+	writeTimeout = 10 * time.Second
+
+	// pingInterval is how often the idle listener connection is checked.
+	pingInterval = 90 * time.Second
+)
+
+type client struct {
+	userId string
+	conn   *websocket.Conn
+	send   chan entity.Message
+}
+
+// Hub pushes the notifications of a user to their websocket clients. It
+// listens on entity.Channel, so notifications recorded by any process reach
+// the clients connected to any instance of the websocket server.
+type Hub struct {
+	repo ports.NotificationRepository
+
+	mu      sync.RWMutex
+	clients map[string]map[*client]struct{}
+}
+
+func NewHub(repo ports.NotificationRepository) *Hub {
+	return &Hub{
+		repo:    repo,
+		clients: make(map[string]map[*client]struct{}),
+	}
+}
+
+func NewNotificationHub() *Hub {
+	return NewHub(repository.NewNotificationRepository(adapter.Adapters.ShopeefunPostgres))
+}
+
+// Handler accepts the websocket connections authenticated with the ephemeral
+// token in the query string.
+func (h *Hub) Handler() http.Handler {
+	return middleware.AuthWs(websocket.Server{
+		// any origin is accepted, the client is authenticated by its token
+		// and not by a cookie
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   h.serve,
+	})
+}
+
+func (h *Hub) serve(conn *websocket.Conn) {
+	claims, err := middleware.GetClaims(conn.Request().Context())
+	if err != nil {
+		log.Error().Err(err).Msg("ws::Hub - Failed to get claims")
+		conn.Close()
+		return
+	}
+
+	userId, _ := claims["user_id"].(string)
+	c := &client{
+		userId: userId,
+		conn:   conn,
+		send:   make(chan entity.Message, sendBuffer),
+	}
+
+	h.register(c)
+	defer h.unregister(c)
+
+	go c.write()
+
+	h.pushUnreadCount(conn.Request().Context(), userId)
+
+	// the client sends nothing, reading only detects when it goes away
+	var discard string
+	for {
+		if err := websocket.Message.Receive(conn, &discard); err != nil {
+			return
+		}
+	}
+}
+
+func (c *client) write() {
+	defer c.conn.Close()
+
+	for msg := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := websocket.JSON.Send(c.conn, msg); err != nil {
+			log.Warn().Err(err).Str("user_id", c.userId).Msg("ws::Hub - Failed to send message")
+			return
+		}
+	}
+}
+
+func (h *Hub) register(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[c.userId] == nil {
+		h.clients[c.userId] = make(map[*client]struct{})
+	}
+	h.clients[c.userId][c] = struct{}{}
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[c.userId][c]; !ok {
+		return
+	}
+
+	delete(h.clients[c.userId], c)
+	if len(h.clients[c.userId]) == 0 {
+		delete(h.clients, c.userId)
+	}
+	close(c.send)
+}
+
+func (h *Hub) connected(userId string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[userId]) > 0
+}
+
+// broadcast queues msg for every client of the user. A client too slow to
+// keep up is disconnected, it gets the unread count again on reconnect.
+func (h *Hub) broadcast(userId string, msg entity.Message) {
+	h.mu.RLock()
+	slow := make([]*client, 0)
+	for c := range h.clients[userId] {
+		select {
+		case c.send <- msg:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		log.Warn().Str("user_id", userId).Msg("ws::Hub - Client too slow, disconnecting")
+		h.unregister(c)
+	}
+}
+
+func (h *Hub) pushUnreadCount(ctx context.Context, userId string) {
+	count, err := h.repo.CountUnread(ctx, userId)
+	if err != nil {
+		return
+	}
+
+	h.broadcast(userId, entity.Message{Type: entity.MessageUnreadCount, Data: entity.UnreadCount{Count: count}})
+}
+
+// Dispatch pushes to the clients of the user of s the notification created,
+// if any, and their unread count.
+func (h *Hub) Dispatch(ctx context.Context, s entity.Signal) {
+	if !h.connected(s.UserId) {
+		return
+	}
+
+	if s.Id != "" {
+		n, err := h.repo.GetNotification(ctx, s.Id)
+		if err != nil {
+			return
+		}
+
+		h.broadcast(s.UserId, entity.Message{Type: entity.MessageNotification, Data: n})
+	}
+
+	h.pushUnreadCount(ctx, s.UserId)
+}
+
+// Listen dispatches the signals sent on entity.Channel until ctx is done, then
+// disconnects every client.
+func (h *Hub) Listen(ctx context.Context, dsn string) {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("ws::Hub - Listener connection error")
+		}
+	})
+	defer listener.Close()
+	defer h.closeAll()
+
+	if err := listener.Listen(entity.Channel); err != nil {
+		log.Error().Err(err).Msg("ws::Hub - Failed to listen")
+		return
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("ws::Hub - Stopped")
+			return
+		case <-ticker.C:
+			go listener.Ping()
+		case n := <-listener.Notify:
+			if n == nil {
+				// reconnected, signals may have been missed meanwhile
+				h.resync(ctx)
+				continue
+			}
+
+			var s entity.Signal
+			if err := json.Unmarshal([]byte(n.Extra), &s); err != nil {
+				log.Warn().Err(err).Str("payload", n.Extra).Msg("ws::Hub - Invalid signal")
+				continue
+			}
+
+			h.Dispatch(ctx, s)
+		}
+	}
+}
+
+// resync pushes again the unread count of every connected user.
+func (h *Hub) resync(ctx context.Context) {
+	h.mu.RLock()
+	users := make([]string, 0, len(h.clients))
+	for userId := range h.clients {
+		users = append(users, userId)
+	}
+	h.mu.RUnlock()
+
+	for _, userId := range users {
+		h.pushUnreadCount(ctx, userId)
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userId, clients := range h.clients {
+		for c := range clients {
+			close(c.send)
+		}
+		delete(h.clients, userId)
+	}
+}
@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/notification/entity"
+	mockPort "codebase-app/mock/module/notification/ports"
+	"codebase-app/pkg/jwthandler"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/websocket"
+)
+
+type HubList struct {
+	suite.Suite
+	mockNotificationRepo *mockPort.MockNotificationRepo
+	hub                  *Hub
+	srv                  *httptest.Server
+}
+
+func (suite *HubList) SetupTest() {
+	config.Envs = new(config.Config)
+	config.Envs.Guard.JwtPrivateKeyWs = "ws-secret"
+	config.Envs.Guard.JwtWsExp = 10
+
+	suite.mockNotificationRepo = mockPort.NewMockNotificationRepo()
+	suite.hub = NewHub(suite.mockNotificationRepo)
+	suite.srv = httptest.NewServer(suite.hub.Handler())
+	suite.T().Cleanup(suite.srv.Close)
+}
+
+type received struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (suite *HubList) dial(userId string) *websocket.Conn {
+	token, err := jwthandler.GenerateEphemeralToken(jwthandler.CostumClaimsPayloadWs{UserId: userId, Role: "end_user"})
+	suite.Require().NoError(err)
+
+	url := "ws" + strings.TrimPrefix(suite.srv.URL, "http") + "/?token=" + token
+	conn, err := websocket.Dial(url, "", suite.srv.URL)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func (suite *HubList) receive(conn *websocket.Conn) received {
+	var msg received
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	suite.Require().NoError(websocket.JSON.Receive(conn, &msg))
+
+	return msg
+}
+
+// Testing Dispatch
+
+func (suite *HubList) TestDispatch_PushesNotificationsOfUser() {
+	n := &entity.Notification{Id: "n1", UserId: "owner", Type: entity.TypeStockLow, Title: "Stok menipis"}
+
+	suite.mockNotificationRepo.On("CountUnread", mock.Anything, "owner").Return(2, nil).Once()
+	suite.mockNotificationRepo.On("CountUnread", mock.Anything, "other").Return(0, nil).Once()
+	owner := suite.dial("owner")
+	other := suite.dial("other")
+
+	msg := suite.receive(owner)
+	suite.Equal(entity.MessageUnreadCount, msg.Type)
+	suite.JSONEq(`{"count":2}`, string(msg.Data))
+	suite.receive(other)
+
+	suite.mockNotificationRepo.On("GetNotification", mock.Anything, "n1").Return(n, nil).Once()
+	suite.mockNotificationRepo.On("CountUnread", mock.Anything, "owner").Return(3, nil).Once()
+	suite.hub.Dispatch(context.Background(), entity.Signal{UserId: "owner", Id: "n1"})
+
+	msg = suite.receive(owner)
+	suite.Equal(entity.MessageNotification, msg.Type)
+	suite.Contains(string(msg.Data), `"id":"n1"`)
+
+	msg = suite.receive(owner)
+	suite.Equal(entity.MessageUnreadCount, msg.Type)
+	suite.JSONEq(`{"count":3}`, string(msg.Data))
+
+	// nothing is pushed to the other user
+	other.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	var discard received
+	suite.Error(websocket.JSON.Receive(other, &discard))
+	suite.mockNotificationRepo.AssertExpectations(suite.T())
+}
+
+func (suite *HubList) TestDispatch_SkipsDisconnectedUser() {
+	suite.hub.Dispatch(context.Background(), entity.Signal{UserId: "owner", Id: "n1"})
+
+	suite.mockNotificationRepo.AssertNotCalled(suite.T(), "GetNotification", mock.Anything, mock.Anything)
+}
+
+// Testing Handler
+
+func (suite *HubList) TestHandler_RejectsMissingToken() {
+	_, err := websocket.Dial("ws"+strings.TrimPrefix(suite.srv.URL, "http")+"/", "", suite.srv.URL)
+
+	suite.Error(err)
+	suite.mockNotificationRepo.AssertNotCalled(suite.T(), "CountUnread", mock.Anything, mock.Anything)
+}
+
+func TestHub(t *testing.T) {
+	suite.Run(t, new(HubList))
+}
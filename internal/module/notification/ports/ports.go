@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"codebase-app/internal/module/notification/entity"
+	"context"
+)
+
+type NotificationRepository interface {
+	GetNotifications(ctx context.Context, req *entity.GetNotificationsRequest) (*entity.GetNotificationsResponse, error)
+	GetNotification(ctx context.Context, id string) (*entity.Notification, error)
+	CountUnread(ctx context.Context, userId string) (int, error)
+	MarkRead(ctx context.Context, req *entity.NotificationRequest) (*entity.Notification, error)
+	MarkAllRead(ctx context.Context, req *entity.MarkAllReadRequest) (*entity.MarkAllReadResponse, error)
+}
+
+type NotificationService interface {
+	GetNotifications(ctx context.Context, req *entity.GetNotificationsRequest) (*entity.GetNotificationsResponse, error)
+	MarkRead(ctx context.Context, req *entity.NotificationRequest) (*entity.Notification, error)
+	MarkAllRead(ctx context.Context, req *entity.MarkAllReadRequest) (*entity.MarkAllReadResponse, error)
+	CreateWsToken(ctx context.Context, req *entity.WsTokenRequest) (*entity.WsTokenResponse, error)
+}
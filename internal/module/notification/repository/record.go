@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"codebase-app/internal/module/notification/entity"
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// Record stores a notification for its user and fills in its id and creation
+// time. It takes a transaction or the database, the websocket hub is signaled
+// once it commits.
+func Record(ctx context.Context, db sqlx.ExtContext, n *entity.Notification) error {
+	if len(n.Data) == 0 {
+		n.Data = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, title, body, data)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := db.QueryRowxContext(ctx, query, n.UserId, n.Type, n.Title, n.Body, n.Data).Scan(&n.Id, &n.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", n.UserId).Str("type", n.Type).Msg("repository::notification-Record - Failed to insert notification")
+		return err
+	}
+
+	return signal(ctx, db, entity.Signal{UserId: n.UserId, Id: n.Id})
+}
+
+// signal notifies the listeners of entity.Channel, only the ids are sent as a
+// payload is limited to 8000 bytes.
+func signal(ctx context.Context, db sqlx.ExecerContext, s entity.Signal) error {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if _, err = db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, entity.Channel, string(payload)); err != nil {
+		log.Error().Err(err).Str("user_id", s.UserId).Msg("repository::notification-signal - Failed to notify listeners")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/internal/module/notification/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.NotificationRepository = &notificationRepository{}
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *notificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+const notificationColumns = `
+	id, user_id, type, title, body, data, read_at, created_at
+`
+
+func (r *notificationRepository) GetNotifications(ctx context.Context, req *entity.GetNotificationsRequest) (*entity.GetNotificationsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Notification
+	}
+
+	var (
+		resp = new(entity.GetNotificationsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.Notification, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			` + notificationColumns + `
+		FROM notifications
+		WHERE
+			user_id = ?
+			AND (NOT ? OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		req.Unread,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetNotifications - Failed to get notifications")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.Notification)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	if resp.UnreadCount, err = r.CountUnread(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *notificationRepository) GetNotification(ctx context.Context, id string) (*entity.Notification, error) {
+	var resp = new(entity.Notification)
+
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = ?`
+
+	if err := r.db.GetContext(ctx, resp, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::GetNotification - Notification not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Notification not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::GetNotification - Failed to get notification")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userId string) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL`
+
+	if err := r.db.GetContext(ctx, &count, r.db.Rebind(query), userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId).Msg("repository::CountUnread - Failed to count unread notifications")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a notification of the user read, keeping the time it was
+// first read.
+func (r *notificationRepository) MarkRead(ctx context.Context, req *entity.NotificationRequest) (*entity.Notification, error) {
+	var resp = new(entity.Notification)
+
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = ? AND user_id = ?
+		RETURNING ` + notificationColumns
+
+	if err := r.db.GetContext(ctx, resp, r.db.Rebind(query), req.Id, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::MarkRead - Notification not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Notification not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkRead - Failed to mark notification read")
+		return nil, err
+	}
+
+	if err := signal(ctx, r.db, entity.Signal{UserId: req.UserId}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, req *entity.MarkAllReadRequest) (*entity.MarkAllReadResponse, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = ? AND read_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkAllRead - Failed to mark notifications read")
+		return nil, err
+	}
+
+	updated, _ := result.RowsAffected()
+	if updated > 0 {
+		if err = signal(ctx, r.db, entity.Signal{UserId: req.UserId}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entity.MarkAllReadResponse{Updated: int(updated)}, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/internal/module/notification/ports"
+	"codebase-app/pkg/jwthandler"
+	"context"
+	"time"
+)
+
+var _ ports.NotificationService = &notificationService{}
+
+type notificationService struct {
+	repo ports.NotificationRepository
+}
+
+func NewNotificationService(repo ports.NotificationRepository) *notificationService {
+	return &notificationService{
+		repo: repo,
+	}
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, req *entity.GetNotificationsRequest) (*entity.GetNotificationsResponse, error) {
+	return s.repo.GetNotifications(ctx, req)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, req *entity.NotificationRequest) (*entity.Notification, error) {
+	return s.repo.MarkRead(ctx, req)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context, req *entity.MarkAllReadRequest) (*entity.MarkAllReadResponse, error) {
+	return s.repo.MarkAllRead(ctx, req)
+}
+
+// CreateWsToken mints the short lived token the client passes in the query
+// string when it connects to the websocket server.
+func (s *notificationService) CreateWsToken(ctx context.Context, req *entity.WsTokenRequest) (*entity.WsTokenResponse, error) {
+	expiresAt := time.Now().Add(time.Duration(config.Envs.Guard.JwtWsExp) * time.Second)
+
+	token, err := jwthandler.GenerateEphemeralToken(jwthandler.CostumClaimsPayloadWs{
+		UserId:          req.UserId,
+		Role:            req.Role,
+		TokenExpiration: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.WsTokenResponse{Token: token, ExpiresAt: expiresAt}, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/pkg/jwthandler"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateWsToken(t *testing.T) {
+	config.Envs = new(config.Config)
+	config.Envs.Guard.JwtPrivateKeyWs = "ws-secret"
+	config.Envs.Guard.JwtWsExp = 10
+
+	s := NewNotificationService(nil)
+
+	resp, err := s.CreateWsToken(context.Background(), &entity.WsTokenRequest{UserId: "user-1", Role: "end_user"})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), resp.ExpiresAt, time.Second)
+
+	claims, err := jwthandler.ParseEphemeralToken(resp.Token)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "user-1", claims.UserId)
+		assert.Equal(t, "end_user", claims.Role)
+	}
+
+	config.Envs.Guard.JwtPrivateKeyWs = "rotated"
+	_, err = jwthandler.ParseEphemeralToken(resp.Token)
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"codebase-app/pkg/event"
+	"encoding/json"
+	"time"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+	StatusDead      = "dead" // gave up after MaxAttempts, no longer holds back its aggregate
+
+	MaxAttempts = 15
+
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = time.Hour
+)
+
+// OutboxEvent is an event waiting in the outbox, or delivered from it.
+type OutboxEvent struct {
+	Id            int64           `db:"id"`
+	EventId       string          `db:"event_id"`
+	Type          string          `db:"type"`
+	AggregateType string          `db:"aggregate_type"`
+	AggregateId   string          `db:"aggregate_id"`
+	Payload       json.RawMessage `db:"payload"`
+	OccurredAt    time.Time       `db:"occurred_at"`
+	Attempts      int             `db:"attempts"`
+}
+
+// Event returns the event as it is published.
+func (e *OutboxEvent) Event() event.Event {
+	return event.Event{
+		Id:            e.EventId,
+		Type:          e.Type,
+		AggregateType: e.AggregateType,
+		AggregateId:   e.AggregateId,
+		Payload:       e.Payload,
+		OccurredAt:    e.OccurredAt,
+	}
+}
+
+// RetryDelay returns how long to wait before the next delivery of an event
+// that failed attempts times, doubling from 5 seconds up to an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
+
+// RelayResult tells what a relay run did.
+type RelayResult struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+	Dead      int `json:"dead"`
+}
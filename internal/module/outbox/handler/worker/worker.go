@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/outbox/ports"
+	"codebase-app/internal/module/outbox/repository"
+	"codebase-app/internal/module/outbox/service"
+	"codebase-app/pkg/event"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// pollInterval is how often the outbox is checked for new events
+	pollInterval = time.Second
+	sinkTimeout  = 10 * time.Second
+)
+
+type outboxWorker struct {
+	service ports.OutboxService
+}
+
+// NewOutboxWorker returns the relay worker. The events go through hooks
+// before the configured sink, ex: the webhook dispatcher.
+func NewOutboxWorker(hooks ...event.Publisher) (*outboxWorker, error) {
+	sink, err := NewSink()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hooks) > 0 {
+		sink = event.NewMultiPublisher(append(hooks, sink)...)
+	}
+
+	var (
+		worker = new(outboxWorker)
+		repo   = repository.NewOutboxRepository(adapter.Adapters.ShopeefunPostgres)
+	)
+	worker.service = service.NewOutboxService(repo, sink, config.Envs.Outbox.BatchSize)
+
+	return worker, nil
+}
+
+// NewSink returns the publisher configured by OUTBOX_SINK.
+func NewSink() (event.Publisher, error) {
+	cfg := config.Envs.Outbox
+
+	switch cfg.Sink {
+	case "log":
+		return event.NewLogPublisher(), nil
+	case "stdout":
+		return event.NewWriterPublisher(os.Stdout), nil
+	case "http":
+		if cfg.HTTPURL == "" {
+			return nil, errors.New("OUTBOX_HTTP_URL is required by the http sink")
+		}
+		return event.NewHTTPPublisher(cfg.HTTPURL, sinkTimeout), nil
+	case "nats":
+		return event.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubject, sinkTimeout)
+	}
+
+	return nil, errors.New("unknown OUTBOX_SINK " + cfg.Sink)
+}
+
+// Start relays the outbox events to the sink until ctx is done.
+func (w *outboxWorker) Start(ctx context.Context) {
+	scheduler.NewTimer("outbox_relay", pollInterval).Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		res, err := w.service.Relay(ctx)
+		if res.Published+res.Failed+res.Dead > 0 {
+			log.Info().Any("result", res).Msg("worker::Outbox - Relayed events")
+		}
+
+		return nil, err
+	})
+}
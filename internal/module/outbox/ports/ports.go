@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"codebase-app/internal/module/outbox/entity"
+	"context"
+	"time"
+)
+
+type OutboxRepository interface {
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+}
+
+type OutboxService interface {
+	Relay(ctx context.Context) (entity.RelayResult, error)
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"codebase-app/pkg/event"
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// Record writes events to the outbox using the given transaction, so they are
+// committed or rolled back together with the change they describe. The relay
+// worker delivers them afterwards.
+func Record(ctx context.Context, tx sqlx.ExecerContext, events ...event.Event) error {
+	query := `
+		INSERT INTO outbox_events (event_id, type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, e := range events {
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			log.Error().Err(err).Any("event", e).Msg("repository::outbox-Record - Failed to marshal payload")
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, e.Id, e.Type, e.AggregateType, e.AggregateId, payload, e.OccurredAt)
+		if err != nil {
+			log.Error().Err(err).Str("type", e.Type).Str("aggregate_id", e.AggregateId).Msg("repository::outbox-Record - Failed to insert event")
+			return err
+		}
+	}
+
+	return nil
+}
+
+type outboxPublisher struct {
+	db sqlx.ExecerContext
+}
+
+// NewPublisher returns a publisher writing events to the outbox outside of
+// any transaction, for the events not tied to a database change.
+func NewPublisher(db sqlx.ExecerContext) event.Publisher {
+	return &outboxPublisher{db: db}
+}
+
+func (p *outboxPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	return Record(ctx, p.db, events...)
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"codebase-app/internal/module/outbox/entity"
+	"codebase-app/internal/module/outbox/ports"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.OutboxRepository = &outboxRepository{}
+
+type outboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *outboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// ClaimEvents leases up to limit events due for delivery, in delivery order.
+// Only the oldest pending event of an aggregate can be claimed, so the events
+// of an aggregate are delivered one after the other even across relays.
+func (r *outboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	var events = make([]entity.OutboxEvent, 0, limit)
+
+	query := `
+		UPDATE outbox_events
+		SET locked_until = NOW() + ? * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT e.id
+			FROM outbox_events e
+			WHERE
+				e.status = 'pending'
+				AND e.next_attempt_at <= NOW()
+				AND (e.locked_until IS NULL OR e.locked_until < NOW())
+				AND NOT EXISTS (
+					SELECT 1
+					FROM outbox_events o
+					WHERE
+						o.status = 'pending'
+						AND o.aggregate_type = e.aggregate_type
+						AND o.aggregate_id = e.aggregate_id
+						AND o.id < e.id
+				)
+			ORDER BY e.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_id, type, aggregate_type, aggregate_id, payload, occurred_at, attempts
+	`
+
+	if err := r.db.SelectContext(ctx, &events, r.db.Rebind(query), lease.Milliseconds(), limit); err != nil {
+		log.Error().Err(err).Msg("repository::ClaimEvents - Failed to claim events")
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Id < events[j].Id })
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	query := `
+		UPDATE outbox_events
+		SET
+			status = 'published',
+			attempts = attempts + 1,
+			last_error = NULL,
+			locked_until = NULL,
+			published_at = NOW()
+		WHERE id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), id); err != nil {
+		log.Error().Err(err).Int64("id", id).Msg("repository::MarkPublished - Failed to update event")
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed delivery. The event is delivered again at
+// retryAt, or never when retryAt is nil.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET
+			status = CASE WHEN CAST(? AS TIMESTAMPTZ) IS NULL THEN 'dead' ELSE 'pending' END,
+			attempts = attempts + 1,
+			last_error = ?,
+			locked_until = NULL,
+			next_attempt_at = COALESCE(CAST(? AS TIMESTAMPTZ), next_attempt_at)
+		WHERE id = ?
+	`
+
+	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), retryAt, reason, retryAt, id); err != nil {
+		log.Error().Err(err).Int64("id", id).Msg("repository::MarkFailed - Failed to update event")
+		return err
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM outbox_events WHERE status = 'published' AND published_at < ?`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), before)
+	if err != nil {
+		log.Error().Err(err).Time("before", before).Msg("repository::DeletePublished - Failed to delete events")
+		return 0, err
+	}
+
+	deleted, _ := result.RowsAffected()
+
+	return int(deleted), nil
+}
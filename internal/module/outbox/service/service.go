@@ -0,0 +1,89 @@
+package service
+
+import (
+	"codebase-app/internal/module/outbox/entity"
+	"codebase-app/internal/module/outbox/ports"
+	"codebase-app/pkg/event"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// relayLease is how long a claimed event is kept from other relays, longer
+// than a delivery can take.
+const relayLease = time.Minute
+
+var _ ports.OutboxService = &outboxService{}
+
+type outboxService struct {
+	repo      ports.OutboxRepository
+	sink      event.Publisher
+	batchSize int
+}
+
+func NewOutboxService(repo ports.OutboxRepository, sink event.Publisher, batchSize int) *outboxService {
+	return &outboxService{
+		repo:      repo,
+		sink:      sink,
+		batchSize: batchSize,
+	}
+}
+
+// Relay delivers the due events to the sink until none is left. A failed
+// event is retried later with a growing delay and holds back the events of its
+// aggregate meanwhile, until it is given up after MaxAttempts.
+func (s *outboxService) Relay(ctx context.Context) (entity.RelayResult, error) {
+	var res entity.RelayResult
+
+	for ctx.Err() == nil {
+		events, err := s.repo.ClaimEvents(ctx, s.batchSize, relayLease)
+		if err != nil {
+			return res, err
+		}
+
+		done := res.Published + res.Dead
+
+		for i := range events {
+			if err = s.deliver(ctx, &events[i], &res); err != nil {
+				return res, err
+			}
+		}
+
+		// the next events of the delivered or given up aggregates are due now
+		if len(events) == 0 || res.Published+res.Dead == done {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (s *outboxService) deliver(ctx context.Context, e *entity.OutboxEvent, res *entity.RelayResult) error {
+	errPublish := s.sink.Publish(ctx, e.Event())
+	if errPublish == nil {
+		res.Published++
+		return s.repo.MarkPublished(ctx, e.Id)
+	}
+
+	var (
+		attempts = e.Attempts + 1
+		retryAt  *time.Time
+	)
+
+	if attempts < entity.MaxAttempts {
+		next := time.Now().Add(entity.RetryDelay(attempts))
+		retryAt = &next
+		res.Failed++
+		log.Warn().Err(errPublish).Str("event_id", e.EventId).Int("attempts", attempts).Time("retry_at", next).Msg("service::Relay - Failed to publish event")
+	} else {
+		res.Dead++
+		log.Error().Err(errPublish).Str("event_id", e.EventId).Int("attempts", attempts).Msg("service::Relay - Giving up on event")
+	}
+
+	return s.repo.MarkFailed(ctx, e.Id, errPublish.Error(), retryAt)
+}
+
+func (s *outboxService) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	return s.repo.DeletePublished(ctx, before)
+}
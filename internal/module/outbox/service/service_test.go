@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"codebase-app/internal/module/outbox/entity"
+	mockPort "codebase-app/mock/module/outbox/ports"
+	mockEvent "codebase-app/mock/pkg/event"
+	"codebase-app/pkg/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceList struct {
+	suite.Suite
+	mockOutboxRepo *mockPort.MockOutboxRepo
+	mockPublisher  *mockEvent.MockPublisher
+	service        *outboxService
+}
+
+func (suite *ServiceList) SetupTest() {
+	suite.mockOutboxRepo = mockPort.NewMockOutboxRepo()
+	suite.mockPublisher = mockEvent.NewMockPublisher()
+	suite.service = NewOutboxService(suite.mockOutboxRepo, suite.mockPublisher, 10)
+}
+
+func newOutboxEvent(id int64, aggregateId, eventType string) entity.OutboxEvent {
+	return entity.OutboxEvent{
+		Id:            id,
+		EventId:       eventType + "-" + aggregateId,
+		Type:          eventType,
+		AggregateType: "product",
+		AggregateId:   aggregateId,
+	}
+}
+
+// Testing Relay
+
+func (suite *ServiceList) TestRelay_KeepsAggregateOrder() {
+	ctx := context.Background()
+
+	// the repository claims the oldest pending event of each aggregate, the
+	// next one of p1 is claimed once the first was published
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{
+		newOutboxEvent(1, "p1", "product.created"),
+		newOutboxEvent(2, "p2", "product.created"),
+	}, nil).Once()
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{
+		newOutboxEvent(3, "p1", "stock.changed"),
+	}, nil).Once()
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{}, nil).Once()
+	suite.mockOutboxRepo.On("MarkPublished", ctx, mock.Anything).Return(nil)
+
+	var p1 []string
+	suite.mockPublisher.On("Publish", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		for _, e := range args.Get(1).([]event.Event) {
+			if e.AggregateId == "p1" {
+				p1 = append(p1, e.Type)
+			}
+		}
+	}).Return(nil)
+	res, err := suite.service.Relay(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.RelayResult{Published: 3}, res)
+	suite.Equal([]string{"product.created", "stock.changed"}, p1)
+	suite.mockOutboxRepo.AssertNumberOfCalls(suite.T(), "MarkPublished", 3)
+}
+
+func (suite *ServiceList) TestRelay_RetriesFailedEvent() {
+	ctx := context.Background()
+
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{
+		newOutboxEvent(1, "p1", "product.created"),
+	}, nil).Once()
+	suite.mockPublisher.On("Publish", ctx, mock.Anything).Return(errors.New("sink is down"))
+	suite.mockOutboxRepo.On("MarkFailed", ctx, int64(1), "sink is down", mock.MatchedBy(func(retryAt *time.Time) bool {
+		return retryAt != nil && time.Until(*retryAt).Round(time.Second) == entity.RetryDelay(1)
+	})).Return(nil)
+	res, err := suite.service.Relay(ctx)
+
+	// nothing was delivered, so the relay stops instead of claiming again
+	suite.Nil(err)
+	suite.Equal(entity.RelayResult{Failed: 1}, res)
+	suite.mockOutboxRepo.AssertNumberOfCalls(suite.T(), "ClaimEvents", 1)
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestRelay_GivesUpAfterMaxAttempts() {
+	ctx := context.Background()
+	first := newOutboxEvent(1, "p1", "product.created")
+	first.Attempts = entity.MaxAttempts - 1
+
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{first}, nil).Once()
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return([]entity.OutboxEvent{
+		newOutboxEvent(2, "p1", "product.updated"),
+	}, nil).Once()
+	suite.mockPublisher.On("Publish", ctx, mock.Anything).Return(errors.New("sink is down"))
+	suite.mockOutboxRepo.On("MarkFailed", ctx, int64(1), "sink is down", (*time.Time)(nil)).Return(nil)
+	suite.mockOutboxRepo.On("MarkFailed", ctx, int64(2), "sink is down", mock.AnythingOfType("*time.Time")).Return(nil)
+	res, err := suite.service.Relay(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.RelayResult{Dead: 1, Failed: 1}, res)
+	suite.mockOutboxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestRelay_ClaimError() {
+	ctx := context.Background()
+
+	suite.mockOutboxRepo.On("ClaimEvents", ctx, 10, relayLease).Return(nil, errors.New("error"))
+	_, err := suite.service.Relay(ctx)
+
+	suite.Equal(errors.New("error"), err)
+	suite.mockPublisher.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything)
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceList))
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 5*time.Second, entity.RetryDelay(1))
+	assert.Equal(t, 10*time.Second, entity.RetryDelay(2))
+	assert.Equal(t, 40*time.Second, entity.RetryDelay(4))
+	assert.Equal(t, time.Hour, entity.RetryDelay(entity.MaxAttempts))
+}
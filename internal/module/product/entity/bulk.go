@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"errors"
+	"strconv"
+)
+
+const (
+	BulkActionSetPrice     = "set_price"
+	BulkActionAdjustPrice  = "adjust_price"
+	BulkActionMoveCategory = "move_category"
+	BulkActionSetStock     = "set_stock"
+	BulkActionDelete       = "delete"
+
+	BulkStatusUpdated   = "updated"
+	BulkStatusDeleted   = "deleted"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusFailed    = "failed"
+
+	MaxBulkProducts = 1000
+)
+
+// BulkProductsRequest applies one action to the products listed by id or
+// matching a filter, all of them or none.
+type BulkProductsRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductIds []string            `json:"product_ids" validate:"omitempty,max=1000,unique,dive,uuid"`
+	Filter     *BulkProductsFilter `json:"filter"`
+
+	Action     string       `json:"action" validate:"required,oneof=set_price adjust_price move_category set_stock delete"`
+	Price      *types.Money `json:"price" validate:"omitempty,gt=0"`       // set_price
+	Percent    *types.Money `json:"percent"`                               // adjust_price, ex: -10 for 10% off
+	CategoryId string       `json:"category_id" validate:"omitempty,uuid"` // move_category
+	Stock      *int64       `json:"stock" validate:"omitempty,gte=0"`      // set_stock
+
+	DryRun bool `json:"dry_run"` // reports what would change without changing it
+}
+
+// BulkProductsFilter selects products of one shop. Prices are base prices,
+// before any running sale.
+type BulkProductsFilter struct {
+	ShopId     string       `json:"shop_id" validate:"required,uuid"`
+	CategoryId string       `json:"category_id" validate:"omitempty,uuid"`
+	Name       string       `json:"name" validate:"omitempty,max=255,min=3"`
+	PriceMin   *types.Money `json:"price_min" validate:"omitempty,gte=0"`
+	PriceMax   *types.Money `json:"price_max" validate:"omitempty,gte=0"`
+}
+
+func (r *BulkProductsRequest) CostumValidation() (int, map[string][]string) {
+	errors := make(map[string][]string)
+
+	switch {
+	case len(r.ProductIds) == 0 && r.Filter == nil:
+		errors["product_ids"] = append(errors["product_ids"], "product_ids or filter is required.")
+	case len(r.ProductIds) > 0 && r.Filter != nil:
+		errors["product_ids"] = append(errors["product_ids"], "product_ids cannot be used together with filter.")
+	}
+
+	switch r.Action {
+	case BulkActionSetPrice:
+		if r.Price == nil {
+			errors["price"] = append(errors["price"], "price is required to set the price.")
+		}
+	case BulkActionAdjustPrice:
+		switch {
+		case r.Percent == nil || r.Percent.IsZero():
+			errors["percent"] = append(errors["percent"], "percent is required to adjust the price.")
+		case *r.Percent <= types.NewMoney(-100):
+			errors["percent"] = append(errors["percent"], "percent must be greater than -100.")
+		case *r.Percent > types.NewMoney(1000):
+			errors["percent"] = append(errors["percent"], "percent must be at most 1000.")
+		}
+	case BulkActionMoveCategory:
+		if r.CategoryId == "" {
+			errors["category_id"] = append(errors["category_id"], "category_id is required to move the category.")
+		}
+	case BulkActionSetStock:
+		if r.Stock == nil {
+			errors["stock"] = append(errors["stock"], "stock is required to set the stock.")
+		}
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	return 0, nil
+}
+
+// Apply returns the state of a product after the action.
+func (r *BulkProductsRequest) Apply(p BulkProductState) (BulkProductState, error) {
+	switch r.Action {
+	case BulkActionSetPrice:
+		p.Price = *r.Price
+	case BulkActionAdjustPrice:
+		delta, err := p.Price.Percent(*r.Percent)
+		if err != nil {
+			return p, err
+		}
+
+		if p.Price = p.Price.Add(delta); p.Price <= 0 {
+			return p, errors.New("the adjusted price must be greater than 0")
+		}
+	case BulkActionMoveCategory:
+		p.CategoryId = r.CategoryId
+	case BulkActionSetStock:
+		p.Stock = int(*r.Stock)
+	case BulkActionDelete:
+		p.Deleted = true
+	default:
+		return p, errors.New("unknown action " + strconv.Quote(r.Action))
+	}
+
+	return p, nil
+}
+
+// BulkProductState is the part of a product a bulk action can change.
+type BulkProductState struct {
+	CategoryId string      `json:"category_id"`
+	Price      types.Money `json:"price"`
+	Stock      int         `json:"stock"`
+	Deleted    bool        `json:"deleted"`
+}
+
+type BulkProductsResponse struct {
+	Action  string              `json:"action"`
+	DryRun  bool                `json:"dry_run"`
+	Matched int                 `json:"matched"`
+	Changed int                 `json:"changed"`
+	Failed  int                 `json:"failed"`
+	Items   []BulkProductResult `json:"items"`
+}
+
+// BulkProductResult is the outcome of the action on one product, or the
+// outcome it would have on a dry run.
+type BulkProductResult struct {
+	ProductId string            `json:"product_id"`
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Error     string            `json:"error,omitempty"`
+	Before    *BulkProductState `json:"before"` // nil when the product was not found
+	After     *BulkProductState `json:"after"`  // nil when the action failed
+}
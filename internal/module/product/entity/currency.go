@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type ExchangeRate struct {
+	BaseCurrency  string     `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency" db:"quote_currency"`
+	Rate          types.Rate `json:"rate" db:"rate"` // quote units bought by one base unit
+	UpdatedBy     *string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type UpsertExchangeRateRequest struct {
+	BaseCurrency  string     `params:"base" validate:"required,iso4217"`
+	QuoteCurrency string     `params:"quote" validate:"required,iso4217,nefield=BaseCurrency"`
+	Rate          types.Rate `json:"rate"`
+}
+
+// CostumValidation checks the rate, a struct the validator cannot require.
+func (r *UpsertExchangeRateRequest) CostumValidation() (int, map[string][]string) {
+	if r.Rate.IsZero() {
+		return 400, map[string][]string{"rate": {"rate is required."}}
+	}
+
+	return 0, nil
+}
@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"strconv"
+	"codebase-app/pkg/types"
 	"strings"
 	"time"
 )
@@ -9,47 +9,66 @@ import (
 type CreateProductRequest struct {
 	UserId string `validate:"required,uuid"`
 
-	ShopId      string  `json:"shop_id" validate:"required,uuid"`
-	CategoryId  string  `json:"category_id" validate:"required,uuid"`
-	Name        string  `json:"name" validate:"required,max=255,min=3"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url"`
-	Price       float64 `json:"price" validate:"required,numeric"`
-	Stock       int64   `json:"stock" validate:"required,numeric"`
+	ShopId      string      `json:"shop_id" validate:"required,uuid"`
+	CategoryId  string      `json:"category_id" validate:"required,uuid"`
+	Name        string      `json:"name" validate:"required,max=255,min=3"`
+	Description *string     `json:"description" validate:"omitempty,max=255,min=3"`
+	ImageUrl    *string     `json:"image_url" validate:"omitempty,url"`
+	Sku         *string     `json:"sku" validate:"omitempty,max=100"`
+	Price       types.Money `json:"price" validate:"required,gt=0"`
+	Currency    string      `json:"currency" validate:"omitempty,iso4217"` // defaults to the currency of the shop
+	Stock       int64       `json:"stock" validate:"required,numeric"`
 }
 
 type UpdateProductRequest struct {
 	UserId string `query:"user_id" validate:"required,uuid"`
 
-	Id          string  `params:"id" validate:"required,uuid"`
-	CategoryId  string  `json:"category_id" validate:"omitempty,uuid"`
-	Name        string  `json:"name" validate:"required,max=255,min=3"`
-	Description *string `json:"description" validate:"omitempty,max=255,min=3"`
-	ImageUrl    *string `json:"image_url" validate:"omitempty,url"`
-	Price       float64 `json:"price" validate:"required,numeric"`
-	Stock       int64   `json:"stock" validate:"required,numeric"`
+	Id          string      `params:"id" validate:"required,uuid"`
+	CategoryId  string      `json:"category_id" validate:"omitempty,uuid"`
+	Name        string      `json:"name" validate:"required,max=255,min=3"`
+	Description *string     `json:"description" validate:"omitempty,max=255,min=3"`
+	ImageUrl    *string     `json:"image_url" validate:"omitempty,url"`
+	Sku         *string     `json:"sku" validate:"omitempty,max=100"` // unchanged when omitted
+	Price       types.Money `json:"price" validate:"required,gt=0"`
+	Stock       int64       `json:"stock" validate:"required,numeric"`
 }
 
 type UpdateProductStockRequest struct {
+	UserId string `validate:"omitempty,uuid"` // empty for trusted internal callers
+	ShopId string `validate:"omitempty,uuid"` // set when the api key is restricted to a shop
+
 	Items []UpdateStock `validate:"required,dive,required"`
 }
 
+func (r *UpdateProductStockRequest) ProductIds() []string {
+	ids := make([]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		ids = append(ids, item.ProductId)
+	}
+
+	return ids
+}
+
 type UpdateStock struct {
 	ProductId string `json:"product_id" validate:"required,uuid"`
 	Stock     int64  `json:"stock" validate:"required,numeric"`
 }
 
 type UpsertProductResponse struct {
-	Id          string    `json:"id" db:"id"`
-	UserId      string    `json:"user_id" db:"user_id"`
-	ShopId      string    `json:"shop_id" db:"shop_id"`
-	Name        string    `json:"name" db:"name"`
-	Description *string   `json:"description" db:"description"`
-	ImageUrl    *string   `json:"image_url" db:"image_url"`
-	Price       float64   `json:"price" db:"price"`
-	Stock       int       `json:"stock" db:"stock"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	Id          string      `json:"id" db:"id"`
+	UserId      string      `json:"user_id" db:"user_id"`
+	ShopId      string      `json:"shop_id" db:"shop_id"`
+	CategoryId  string      `json:"category_id" db:"category_id"`
+	Name        string      `json:"name" db:"name"`
+	Slug        string      `json:"slug" db:"slug"`
+	Description *string     `json:"description" db:"description"`
+	ImageUrl    *string     `json:"image_url" db:"image_url"`
+	Sku         *string     `json:"sku" db:"sku"`
+	Price       types.Money `json:"price" db:"price"`
+	Currency    string      `json:"currency" db:"currency"`
+	Stock       int         `json:"stock" db:"stock"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
 }
 
 type DeleteProductRequest struct {
@@ -58,20 +77,26 @@ type DeleteProductRequest struct {
 }
 
 type GetProductsRequest struct {
-	ShopId        string `query:"shop_id" validate:"omitempty,uuid"`
-	CategoryId    string `query:"category_id" validate:"omitempty,uuid"`
-	Name          string `query:"name" validate:"omitempty,max=255,min=3"`
-	PriceMinStr   string `query:"price_min" validate:"omitempty,numeric,gte=0"`
-	PriceMaxStr   string `query:"price_max" validate:"omitempty,numeric,gte=0"`
-	IsAvailable   bool   `query:"is_available"`
-	ProductIdsStr string `query:"product_ids"`
+	ShopId        string  `query:"shop_id" validate:"omitempty,uuid"`
+	CategoryId    string  `query:"category_id" validate:"omitempty,uuid"`
+	Name          string  `query:"name" validate:"omitempty,max=255,min=3"`
+	PriceMinStr   string  `query:"price_min" validate:"omitempty,numeric,gte=0"`
+	PriceMaxStr   string  `query:"price_max" validate:"omitempty,numeric,gte=0"`
+	IsAvailable   bool    `query:"is_available"`
+	ProductIdsStr string  `query:"product_ids"`
+	Near          string  `query:"near"` // "lat,lng" of the buyer, matched against the shop origin
+	RadiusKm      float64 `query:"radius_km" validate:"omitempty,gt=0,max=500"`
+	RatingMin     float64 `query:"rating_min" validate:"omitempty,min=1,max=5"`
+	Sort          string  `query:"sort" validate:"omitempty,oneof=newest rating"`
+	Currency      string  `query:"currency" validate:"omitempty,iso4217"` // adds the prices converted into this currency, required by price_min and price_max
 
 	Page  int `query:"page" validate:"required,min=1"`
 	Limit int `query:"limit" validate:"required,min=1,max=100"`
 
-	PriceMin   float64
-	PriceMax   float64
+	PriceMin   types.Money
+	PriceMax   types.Money
 	ProductIds []string
+	NearPoint  *types.Point
 }
 
 func (r *GetProductsRequest) SetDefaults() {
@@ -83,6 +108,10 @@ func (r *GetProductsRequest) SetDefaults() {
 		r.Limit = 10
 	}
 
+	if r.Near != "" && r.RadiusKm == 0 {
+		r.RadiusKm = 10
+	}
+
 	if r.ProductIdsStr != "" {
 		// split product ids string by comma
 		ids := strings.Split(r.ProductIdsStr, ",")
@@ -94,26 +123,36 @@ func (r *GetProductsRequest) SetDefaults() {
 
 func (r *GetProductsRequest) CostumValidation() (int, map[string][]string) {
 	var (
-		errors   = make(map[string][]string)
-		err      error
-		priceMin float64
-		priceMax float64
+		errors = make(map[string][]string)
+		err    error
 	)
 
 	if r.PriceMinStr != "" {
-		priceMin, err = strconv.ParseFloat(r.PriceMinStr, 64)
+		r.PriceMin, err = types.ParseMoney(r.PriceMinStr)
 		if err != nil {
-			errors["price_min"] = append(errors["price_min"], "price_min must be a number.")
+			errors["price_min"] = append(errors["price_min"], "price_min "+err.Error()+".")
 		}
-		r.PriceMin = priceMin
 	}
 
 	if r.PriceMaxStr != "" {
-		priceMax, err = strconv.ParseFloat(r.PriceMaxStr, 64)
+		r.PriceMax, err = types.ParseMoney(r.PriceMaxStr)
+		if err != nil {
+			errors["price_max"] = append(errors["price_max"], "price_max "+err.Error()+".")
+		}
+	}
+
+	// prices are stored in the currency of each product, the bounds are
+	// compared with the prices converted into the requested one
+	if (r.PriceMinStr != "" || r.PriceMaxStr != "") && r.Currency == "" {
+		errors["currency"] = append(errors["currency"], "currency is required to filter by price.")
+	}
+
+	if r.Near != "" {
+		point, err := types.ParseLatLng(r.Near)
 		if err != nil {
-			errors["price_max"] = append(errors["price_max"], "price_max must be a number.")
+			errors["near"] = append(errors["near"], "near "+err.Error()+".")
 		}
-		r.PriceMax = priceMax
+		r.NearPoint = &point
 	}
 
 	if len(errors) > 0 {
@@ -130,15 +169,41 @@ type GetProductsResponse struct {
 }
 
 type Product struct {
-	Id         string    `json:"id" db:"id"`
-	CategoryId string    `json:"category_id" db:"category_id"`
-	ShopId     string    `json:"shop_id" db:"shop_id"`
-	Name       string    `json:"name" db:"name"`
-	ImageUrl   *string   `json:"image_url" db:"image_url"`
-	Price      float64   `json:"price" db:"price"`
-	Stock      int       `json:"stock" db:"stock"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Id         string      `json:"id" db:"id"`
+	CategoryId string      `json:"category_id" db:"category_id"`
+	ShopId     string      `json:"shop_id" db:"shop_id"`
+	Name       string      `json:"name" db:"name"`
+	Slug       string      `json:"slug" db:"slug"`
+	ImageUrl   *string     `json:"image_url" db:"image_url"`
+	Sku        *string     `json:"sku" db:"sku"`
+	Price      types.Money `json:"price" db:"price"` // effective price, after the running sale
+	Currency   string      `json:"currency" db:"currency"`
+	Stock      int         `json:"stock" db:"stock"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at" db:"updated_at"`
+
+	OriginalPrice  types.Money `json:"original_price" db:"original_price"`
+	SaleEndsAt     *time.Time  `json:"sale_ends_at" db:"sale_ends_at"`         // nil when no sale is running
+	LowestPrice30d types.Money `json:"lowest_price_30d" db:"lowest_price_30d"` // lowest price paid over the last 30 days, sales included
+
+	// Display is the price converted into the requested currency, nil when no
+	// currency was requested or no exchange rate is known.
+	Display *DisplayPrice `json:"display,omitempty" db:"-"`
+
+	RatingAvg   float64 `json:"rating_avg" db:"rating_avg"`
+	RatingCount int     `json:"rating_count" db:"rating_count"`
+
+	DistanceKm *float64 `json:"distance_km" db:"distance_km"` // from the near point to the shop origin
+
+	IsOpen      bool `json:"is_open" db:"-"`      // the shop is within its opening hours
+	IsAvailable bool `json:"is_available" db:"-"` // in stock and not hidden by holiday mode
+}
+
+type DisplayPrice struct {
+	Currency      string      `json:"currency"`
+	Price         types.Money `json:"price"`
+	OriginalPrice types.Money `json:"original_price"`
+	Rate          types.Rate  `json:"rate"`
 }
 
 type Meta struct {
@@ -159,3 +224,127 @@ func (m *Meta) CountTotalPage() {
 		m.TotalPage++
 	}
 }
+
+type GetProductBySlugRequest struct {
+	Slug     string `params:"slug" validate:"required,max=255"`
+	Currency string `query:"currency" validate:"omitempty,iso4217"`
+}
+
+// GetProductBySlugResponse holds either the product, or the current slug
+// when the requested one is an old slug of a renamed product.
+type GetProductBySlugResponse struct {
+	Product      *Product
+	RedirectSlug string
+}
+
+type SlugLookup struct {
+	Id   string `db:"id"`
+	Slug string `db:"slug"`
+}
+
+type SlugRedirectResponse struct {
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
+
+const (
+	ReservationOpen      = "open"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+type ReserveStockRequest struct {
+	OrderId string        `json:"order_id" validate:"required,max=255"`
+	Items   []ReserveItem `json:"items" validate:"required,min=1,dive"`
+}
+
+type ReserveItem struct {
+	ProductId string `json:"product_id" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" validate:"required,min=1"`
+}
+
+type ReservationRequest struct {
+	OrderId string `params:"order_id" validate:"required,max=255"`
+}
+
+type Reservation struct {
+	Id        string    `json:"id" db:"id"`
+	OrderId   string    `json:"order_id" db:"order_id"`
+	ProductId string    `json:"product_id" db:"product_id"`
+	Quantity  int       `json:"quantity" db:"quantity"`
+	Status    string    `json:"status" db:"status"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type ReservationResponse struct {
+	OrderId string        `json:"order_id"`
+	Items   []Reservation `json:"items"`
+}
+
+const (
+	FeedKindCreated   = "created"
+	FeedKindRestocked = "restocked"
+)
+
+// Domain events written to the outbox with the change they describe, the
+// aggregate of all of them is the product.
+const (
+	AggregateProduct = "product"
+
+	EventProductCreated  = "product.created"
+	EventProductUpdated  = "product.updated"
+	EventProductDeleted  = "product.deleted"
+	EventProductRestored = "product.restored"
+	EventStockChanged    = "stock.changed"
+)
+
+// ProductRef is the payload of product.deleted and
+// product.restored.
+type ProductRef struct {
+	Id     string `json:"id"`
+	ShopId string `json:"shop_id"`
+}
+
+// StockChangedPayload is the payload of stock.changed, Reason tells what
+// changed the stock, ex: update, reservation, release.
+type StockChangedPayload struct {
+	ProductId string `json:"product_id"`
+	ShopId    string `json:"shop_id"`
+	From      int64  `json:"from"`
+	To        int64  `json:"to"`
+	Reason    string `json:"reason"`
+}
+
+const (
+	StockReasonUpdate      = "update"
+	StockReasonBulk        = "bulk"
+	StockReasonImport      = "import"
+	StockReasonReservation = "reservation"
+	StockReasonRelease     = "release"
+)
+
+type GetFeedRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Cursor string `query:"cursor" validate:"omitempty,number"`
+	Limit  int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetFeedRequest) SetDefaults() {
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type FeedItem struct {
+	EventId    int64     `json:"-" db:"event_id"`
+	Kind       string    `json:"kind" db:"kind"`
+	OccurredAt time.Time `json:"occurred_at" db:"occurred_at"`
+	Product    Product   `json:"product" db:"product"`
+}
+
+type GetFeedResponse struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor *string    `json:"next_cursor"` // nil on the last page
+}
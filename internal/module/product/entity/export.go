@@ -0,0 +1,245 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatXML   = "xml" // Google Merchant RSS 2.0 product feed, also read by Facebook Catalog
+)
+
+// ExportProductsRequest exports the products matching the filters of
+// GetProductsRequest, its page and limit are ignored.
+type ExportProductsRequest struct {
+	UserId string `validate:"required,uuid"`
+	Role   string
+
+	Format string `query:"format" validate:"required,oneof=csv jsonl xml"`
+
+	GetProductsRequest
+}
+
+// ExportFunc streams an export into w.
+type ExportFunc func(ctx context.Context, w io.Writer) error
+
+// ExportFilename returns the file name of an export, ex: products.csv.
+func ExportFilename(format string) string {
+	return "products." + format
+}
+
+// ExportContentType returns the content type of an export format.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatJSONL:
+		return "application/x-ndjson"
+	case ExportFormatXML:
+		return "application/xml; charset=utf-8"
+	}
+
+	return "application/octet-stream"
+}
+
+type ExportProduct struct {
+	Id           string      `json:"id" db:"id"`
+	ShopId       string      `json:"shop_id" db:"shop_id"`
+	ShopName     string      `json:"shop_name" db:"shop_name"`
+	CategoryId   string      `json:"category_id" db:"category_id"`
+	CategoryName string      `json:"category_name" db:"category_name"`
+	Sku          *string     `json:"sku" db:"sku"`
+	Name         string      `json:"name" db:"name"`
+	Slug         string      `json:"slug" db:"slug"`
+	Description  *string     `json:"description" db:"description"`
+	ImageUrl     *string     `json:"image_url" db:"image_url"`
+	Link         string      `json:"link" db:"-"`
+	Price        types.Money `json:"price" db:"price"`           // regular price, as imported
+	SalePrice    types.Money `json:"sale_price" db:"sale_price"` // effective price, equal to price when no sale is running
+	SaleEndsAt   *time.Time  `json:"sale_ends_at" db:"sale_ends_at"`
+	Currency     string      `json:"currency" db:"currency"`
+	Stock        int         `json:"stock" db:"stock"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// ExportWriter writes exported products one at a time.
+type ExportWriter interface {
+	Write(p *ExportProduct) error
+	// Close ends the export, it does not close the underlying writer.
+	Close() error
+}
+
+// NewExportWriter returns the writer of an export format. title and link
+// describe the catalog in the feed formats.
+func NewExportWriter(format string, w io.Writer, title, link string) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w)
+	case ExportFormatJSONL:
+		return &jsonlExportWriter{enc: json.NewEncoder(w)}, nil
+	case ExportFormatXML:
+		return newXMLExportWriter(w, title, link)
+	}
+
+	return nil, errors.New("unsupported export format " + format)
+}
+
+// csvExportWriter starts with the columns of an import file, so an export can
+// be edited and imported back.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	cw := csv.NewWriter(w)
+
+	header := append(append([]string{}, importColumns...),
+		"id", "shop_id", "shop_name", "category_name", "slug", "link", "sale_price", "sale_ends_at", "updated_at",
+	)
+
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvExportWriter{w: cw}, nil
+}
+
+func (e *csvExportWriter) Write(p *ExportProduct) error {
+	var saleEndsAt string
+	if p.SaleEndsAt != nil {
+		saleEndsAt = p.SaleEndsAt.Format(time.RFC3339)
+	}
+
+	return e.w.Write([]string{
+		deref(p.Sku),
+		p.CategoryId,
+		p.Name,
+		deref(p.Description),
+		deref(p.ImageUrl),
+		p.Price.String(),
+		p.Currency,
+		strconv.Itoa(p.Stock),
+		p.Id,
+		p.ShopId,
+		p.ShopName,
+		p.CategoryName,
+		p.Slug,
+		p.Link,
+		p.SalePrice.String(),
+		saleEndsAt,
+		p.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (e *csvExportWriter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExportWriter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonlExportWriter) Write(p *ExportProduct) error {
+	return e.enc.Encode(p)
+}
+
+func (e *jsonlExportWriter) Close() error {
+	return nil
+}
+
+// googleItem is a product of the Google Merchant feed, see
+// https://support.google.com/merchants/answer/7052112. Its id is the product
+// id rather than the sku, skus are only unique within a shop.
+type googleItem struct {
+	XMLName          xml.Name `xml:"item"`
+	Id               string   `xml:"g:id"`
+	Title            string   `xml:"g:title"`
+	Description      string   `xml:"g:description"`
+	Link             string   `xml:"g:link"`
+	ImageLink        string   `xml:"g:image_link,omitempty"`
+	Availability     string   `xml:"g:availability"`
+	Price            string   `xml:"g:price"`
+	SalePrice        string   `xml:"g:sale_price,omitempty"`
+	Condition        string   `xml:"g:condition"`
+	Brand            string   `xml:"g:brand"`
+	ProductType      string   `xml:"g:product_type,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists"`
+}
+
+type xmlExportWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newXMLExportWriter(w io.Writer, title, link string) (*xmlExportWriter, error) {
+	e := &xmlExportWriter{w: w, enc: xml.NewEncoder(w)}
+
+	if _, err := io.WriteString(w, xml.Header+`<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0"><channel>`); err != nil {
+		return nil, err
+	}
+
+	for _, el := range [][2]string{{"title", title}, {"link", link}, {"description", title}} {
+		if err := e.enc.EncodeElement(el[1], xml.StartElement{Name: xml.Name{Local: el[0]}}); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+func (e *xmlExportWriter) Write(p *ExportProduct) error {
+	item := googleItem{
+		Id:               p.Id,
+		Title:            p.Name,
+		Description:      p.Name,
+		Link:             p.Link,
+		ImageLink:        deref(p.ImageUrl),
+		Availability:     "out_of_stock",
+		Price:            p.Price.String() + " " + p.Currency,
+		Condition:        "new",
+		Brand:            p.ShopName,
+		ProductType:      p.CategoryName,
+		IdentifierExists: "no",
+	}
+
+	if p.Description != nil {
+		item.Description = *p.Description
+	}
+
+	if p.Stock > 0 {
+		item.Availability = "in_stock"
+	}
+
+	if p.SalePrice.Cmp(p.Price) < 0 {
+		item.SalePrice = p.SalePrice.String() + " " + p.Currency
+	}
+
+	return e.enc.Encode(item)
+}
+
+func (e *xmlExportWriter) Close() error {
+	if err := e.enc.Flush(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(e.w, "</channel></rss>\n")
+	return err
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
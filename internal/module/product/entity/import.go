@@ -0,0 +1,261 @@
+package entity
+
+import (
+	"bufio"
+	"bytes"
+	"codebase-app/pkg/types"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+
+	ImportPending    = "pending"
+	ImportValidating = "validating"
+	ImportImporting  = "importing"
+	ImportCompleted  = "completed"
+	ImportFailed     = "failed" // nothing was imported
+
+	MaxImportRows = 10000
+)
+
+// ErrImportJobLost is returned when writing a job that was claimed again by
+// another worker after this one stopped heartbeating.
+var ErrImportJobLost = errors.New("import job was claimed by another worker")
+
+type CreateImportJobRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId  string `form:"shop_id" validate:"required,uuid"`
+	Format  string `form:"format" validate:"required,oneof=csv jsonl"` // detected from the file name when empty
+	Payload []byte `form:"-" validate:"required"`
+}
+
+// ImportFormatFromFilename returns the import format of a file name, empty
+// when the extension is unknown.
+func ImportFormatFromFilename(name string) string {
+	name = strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return ImportFormatCSV
+	case strings.HasSuffix(name, ".jsonl"), strings.HasSuffix(name, ".ndjson"):
+		return ImportFormatJSONL
+	}
+
+	return ""
+}
+
+type ImportJobRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	JobId string `params:"id" validate:"required,uuid"`
+}
+
+type ImportJob struct {
+	Id            string     `json:"id" db:"id"`
+	ShopId        string     `json:"shop_id" db:"shop_id"`
+	UserId        string     `json:"user_id" db:"user_id"`
+	Format        string     `json:"format" db:"format"`
+	Status        string     `json:"status" db:"status"`
+	TotalRows     int        `json:"total_rows" db:"total_rows"`
+	ValidatedRows int        `json:"validated_rows" db:"validated_rows"`
+	ImportedRows  int        `json:"imported_rows" db:"imported_rows"`
+	CreatedCount  int        `json:"created_count" db:"created_count"`
+	UpdatedCount  int        `json:"updated_count" db:"updated_count"`
+	ErrorCount    int        `json:"error_count" db:"error_count"`
+	Error         *string    `json:"error" db:"error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	StartedAt     *time.Time `json:"started_at" db:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at" db:"finished_at"`
+
+	Lease   int    `json:"-" db:"lease"`
+	Payload []byte `json:"-" db:"payload"`
+}
+
+// ImportRow is a product of an import file, upserted by its sku.
+type ImportRow struct {
+	Row int `json:"-"` // 1-based, header excluded
+
+	Sku         string      `json:"sku" validate:"required,max=100"`
+	CategoryId  string      `json:"category_id" validate:"required,uuid"`
+	Name        string      `json:"name" validate:"required,max=255,min=3"`
+	Description *string     `json:"description" validate:"omitempty,max=255,min=3"`
+	ImageUrl    *string     `json:"image_url" validate:"omitempty,url"`
+	Price       types.Money `json:"price" validate:"required,gt=0"`
+	Currency    string      `json:"currency" validate:"omitempty,iso4217"` // the currency of the shop when empty
+	Stock       int64       `json:"stock" validate:"gte=0"`
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Sku     string `json:"sku"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ImportResult is the outcome of a processed import job.
+type ImportResult struct {
+	Status       string
+	CreatedCount int
+	UpdatedCount int
+	Errors       []ImportRowError
+	Error        *string
+}
+
+var importColumns = []string{"sku", "category_id", "name", "description", "image_url", "price", "currency", "stock"}
+
+// ParseImportRows reads the rows of an import file. A row that cannot be read
+// is returned empty with its errors, the error is set when the whole file is
+// unreadable.
+func ParseImportRows(format string, payload []byte) ([]ImportRow, []ImportRowError, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseImportCSV(payload)
+	case ImportFormatJSONL:
+		return parseImportJSONL(payload)
+	}
+
+	return nil, nil, errors.New("unsupported import format " + format)
+}
+
+func parseImportCSV(payload []byte) ([]ImportRow, []ImportRowError, error) {
+	var (
+		rows   = make([]ImportRow, 0)
+		errs   = make([]ImportRowError, 0)
+		r      = csv.NewReader(bytes.NewReader(bytes.TrimPrefix(payload, []byte("\xef\xbb\xbf"))))
+		header = make(map[string]int)
+	)
+
+	r.FieldsPerRecord = -1
+
+	names, err := r.Read()
+	if err != nil {
+		return nil, nil, errors.New("the file has no header row")
+	}
+
+	for i, name := range names {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, column := range []string{"sku", "category_id", "name", "price"} {
+		if _, ok := header[column]; !ok {
+			return nil, nil, errors.New("the header has no " + column + " column, expected: " + strings.Join(importColumns, ","))
+		}
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := ImportRow{Row: len(rows) + 1}
+		rows = append(rows, row)
+		if len(rows) > MaxImportRows {
+			return nil, nil, errors.New("the file has more than " + strconv.Itoa(MaxImportRows) + " rows")
+		}
+
+		if err != nil {
+			errs = append(errs, ImportRowError{Row: row.Row, Field: "row", Message: err.Error()})
+			continue
+		}
+
+		cell := func(column string) string {
+			if i, ok := header[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		optional := func(column string) *string {
+			if v := cell(column); v != "" {
+				return &v
+			}
+			return nil
+		}
+
+		row.Sku = cell("sku")
+		row.CategoryId = cell("category_id")
+		row.Name = cell("name")
+		row.Description = optional("description")
+		row.ImageUrl = optional("image_url")
+		row.Currency = strings.ToUpper(cell("currency"))
+
+		if v := cell("price"); v != "" {
+			if row.Price, err = types.ParseMoney(v); err != nil {
+				errs = append(errs, ImportRowError{Row: row.Row, Sku: row.Sku, Field: "price", Message: "price " + err.Error() + "."})
+			}
+		}
+
+		if v := cell("stock"); v != "" {
+			if row.Stock, err = strconv.ParseInt(v, 10, 64); err != nil {
+				errs = append(errs, ImportRowError{Row: row.Row, Sku: row.Sku, Field: "stock", Message: "stock must be a whole number."})
+			}
+		}
+
+		rows[len(rows)-1] = row
+	}
+
+	return rows, errs, nil
+}
+
+func parseImportJSONL(payload []byte) ([]ImportRow, []ImportRowError, error) {
+	var (
+		rows    = make([]ImportRow, 0)
+		errs    = make([]ImportRowError, 0)
+		scanner = bufio.NewScanner(bytes.NewReader(payload))
+	)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := ImportRow{Row: len(rows) + 1}
+		if len(rows)+1 > MaxImportRows {
+			return nil, nil, errors.New("the file has more than " + strconv.Itoa(MaxImportRows) + " rows")
+		}
+
+		if err := json.Unmarshal(line, &row); err != nil {
+			errs = append(errs, ImportRowError{Row: row.Row, Sku: row.Sku, Field: "row", Message: err.Error()})
+		}
+
+		row.Currency = strings.ToUpper(row.Currency)
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return rows, errs, nil
+}
+
+// WriteImportReport writes the row errors of an import as CSV.
+func WriteImportReport(w io.Writer, errs []ImportRowError) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"row", "sku", "field", "message"}); err != nil {
+		return err
+	}
+
+	for _, e := range errs {
+		if err := cw.Write([]string{strconv.Itoa(e.Row), e.Sku, e.Field, e.Message}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+const (
+	PriceScheduleKindPrice = "price" // replaces the base price at starts_at
+	PriceScheduleKindSale  = "sale"  // discounts the base price between starts_at and ends_at
+
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+
+	PriceScheduleScheduled = "scheduled"
+	PriceScheduleActive    = "active"  // a running sale
+	PriceScheduleApplied   = "applied" // a base price change that took effect
+	PriceScheduleExpired   = "expired"
+	PriceScheduleCancelled = "cancelled"
+)
+
+type CreatePriceScheduleRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductId     string       `params:"id" validate:"required,uuid"`
+	Kind          string       `json:"kind" validate:"required,oneof=price sale"`
+	Price         *types.Money `json:"price" validate:"required_if=Kind price,omitempty,gte=0"`
+	DiscountType  string       `json:"discount_type" validate:"required_if=Kind sale,omitempty,oneof=percentage fixed"`
+	DiscountValue types.Money  `json:"discount_value" validate:"required_if=Kind sale,omitempty,gt=0"` // a percentage or an amount
+	StartsAt      time.Time    `json:"starts_at" validate:"required"`
+	EndsAt        *time.Time   `json:"ends_at" validate:"required_if=Kind sale"`
+}
+
+// CostumValidation checks the rules across fields the validator cannot express.
+func (r *CreatePriceScheduleRequest) CostumValidation() (int, map[string][]string) {
+	errors := make(map[string][]string)
+
+	if r.Kind == PriceScheduleKindSale {
+		if r.DiscountType == DiscountPercentage && r.DiscountValue >= types.NewMoney(100) {
+			errors["discount_value"] = append(errors["discount_value"], "discount_value must be less than 100 for a percentage discount.")
+		}
+
+		if r.EndsAt != nil && !r.EndsAt.After(r.StartsAt) {
+			errors["ends_at"] = append(errors["ends_at"], "ends_at must be after starts_at.")
+		}
+	}
+
+	if r.Kind == PriceScheduleKindPrice && r.EndsAt != nil {
+		errors["ends_at"] = append(errors["ends_at"], "ends_at is only allowed for a sale.")
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	return 0, nil
+}
+
+type PriceScheduleRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductId  string `params:"id" validate:"required,uuid"`
+	ScheduleId string `params:"schedule_id" validate:"omitempty,uuid"`
+}
+
+type PriceSchedule struct {
+	Id            string       `json:"id" db:"id"`
+	ProductId     string       `json:"product_id" db:"product_id"`
+	Kind          string       `json:"kind" db:"kind"`
+	Price         *types.Money `json:"price" db:"price"`
+	DiscountType  *string      `json:"discount_type" db:"discount_type"`
+	DiscountValue *types.Money `json:"discount_value" db:"discount_value"`
+	StartsAt      time.Time    `json:"starts_at" db:"starts_at"`
+	EndsAt        *time.Time   `json:"ends_at" db:"ends_at"`
+	Status        string       `json:"status" db:"status"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// ApplyPriceSchedulesResult reports what a scheduler run changed and when the
+// next schedule is due, nil when nothing is scheduled.
+type ApplyPriceSchedulesResult struct {
+	Applied   int
+	Activated int
+	Expired   int
+	NextDueAt *time.Time
+}
+
+const (
+	PriceSourceUpdate   = "update"
+	PriceSourceSchedule = "schedule"
+	PriceSourceImport   = "import"
+	PriceSourceBulk     = "bulk"
+	PriceSourceSale     = "sale" // a sale starting or ending
+)
+
+type GetPriceHistoryRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
+
+	Page  int `query:"page" validate:"required,min=1"`
+	Limit int `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetPriceHistoryRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type PriceHistory struct {
+	Id        int64       `json:"id" db:"id"`
+	ProductId string      `json:"product_id" db:"product_id"`
+	OldPrice  types.Money `json:"old_price" db:"old_price"`
+	NewPrice  types.Money `json:"new_price" db:"new_price"`
+	ActorId   *string     `json:"actor_id" db:"actor_id"`
+	Source    string      `json:"source" db:"source"`
+	ChangedAt time.Time   `json:"changed_at" db:"changed_at"`
+}
+
+type GetPriceHistoryResponse struct {
+	Items []PriceHistory `json:"items"`
+	Meta  Meta           `json:"meta"`
+}
@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stock levels of a product. A product is low once its stock reaches its low
+// stock threshold and out once sold out.
+const (
+	StockLevelOk  = "ok"
+	StockLevelLow = "low"
+	StockLevelOut = "out"
+
+	EventStockLow = "stock.low"
+	EventStockOut = "stock.out"
+)
+
+// StockLevel returns the level of a stock for a threshold, nil when the
+// product only alerts once sold out.
+func StockLevel(stock int64, threshold *int) string {
+	switch {
+	case stock <= 0:
+		return StockLevelOut
+	case threshold != nil && stock <= int64(*threshold):
+		return StockLevelLow
+	}
+
+	return StockLevelOk
+}
+
+type SetLowStockThresholdRequest struct {
+	UserId    string `validate:"required,uuid"`
+	ProductId string `params:"id" validate:"required,uuid"`
+
+	Threshold *int `json:"threshold" validate:"omitempty,min=0,max=1000000"` // null turns the low stock alert off
+}
+
+type SetLowStockThresholdResponse struct {
+	ProductId string `json:"product_id" db:"id"`
+	Stock     int64  `json:"stock" db:"stock"`
+	Threshold *int   `json:"threshold" db:"low_stock_threshold"`
+	Level     string `json:"level" db:"stock_level"`
+}
+
+// GetLowStockRequest lists the products of the shops of the user at or below
+// their threshold, or sold out.
+type GetLowStockRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId string `query:"shop_id" validate:"omitempty,uuid"`
+	Level  string `query:"level" validate:"omitempty,oneof=low out"`
+	Page   int    `query:"page" validate:"required,min=1"`
+	Limit  int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetLowStockRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type LowStockProduct struct {
+	Id        string    `json:"id" db:"id"`
+	ShopId    string    `json:"shop_id" db:"shop_id"`
+	Name      string    `json:"name" db:"name"`
+	Sku       *string   `json:"sku" db:"sku"`
+	ImageUrl  *string   `json:"image_url" db:"image_url"`
+	Stock     int64     `json:"stock" db:"stock"`
+	Threshold *int      `json:"threshold" db:"low_stock_threshold"`
+	Level     string    `json:"level" db:"stock_level"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type GetLowStockResponse struct {
+	Items []LowStockProduct `json:"items"`
+	Meta  Meta              `json:"meta"`
+}
+
+// StockAlert is a product going down to the low or out level, to be sent to
+// the owner of its shop.
+type StockAlert struct {
+	Id          int64     `json:"id" db:"id"`
+	ProductId   string    `json:"product_id" db:"product_id"`
+	ProductName string    `json:"product_name" db:"product_name"`
+	ShopId      string    `json:"shop_id" db:"shop_id"`
+	ShopName    string    `json:"shop_name" db:"shop_name"`
+	OwnerId     string    `json:"owner_id" db:"owner_id"`
+	Level       string    `json:"level" db:"level"`
+	Stock       int64     `json:"stock" db:"stock"`
+	Threshold   *int      `json:"threshold" db:"threshold"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// EventType returns the type of the event and notification of the alert.
+func (a *StockAlert) EventType() string {
+	if a.Level == StockLevelOut {
+		return EventStockOut
+	}
+
+	return EventStockLow
+}
+
+// Text returns the title and the body of the messages of the alert.
+func (a *StockAlert) Text() (string, string) {
+	if a.Level == StockLevelOut {
+		return fmt.Sprintf("Stok %s habis", a.ProductName),
+			fmt.Sprintf("Stok produk %s di toko %s sudah habis.", a.ProductName, a.ShopName)
+	}
+
+	threshold := 0
+	if a.Threshold != nil {
+		threshold = *a.Threshold
+	}
+
+	return fmt.Sprintf("Stok %s menipis", a.ProductName),
+		fmt.Sprintf("Stok produk %s di toko %s tinggal %d, di bawah batas %d.", a.ProductName, a.ShopName, a.Stock, threshold)
+}
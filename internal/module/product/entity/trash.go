@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+// GetProductTrashRequest lists the deleted products of the shops of the user
+// that can still be restored.
+type GetProductTrashRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId string `query:"shop_id" validate:"omitempty,uuid"`
+	Page   int    `query:"page" validate:"required,min=1"`
+	Limit  int    `query:"limit" validate:"required,min=1,max=100"`
+}
+
+func (r *GetProductTrashRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type TrashedProduct struct {
+	Id        string      `json:"id" db:"id"`
+	ShopId    string      `json:"shop_id" db:"shop_id"`
+	Name      string      `json:"name" db:"name"`
+	Slug      string      `json:"slug" db:"slug"`
+	Sku       *string     `json:"sku" db:"sku"`
+	ImageUrl  *string     `json:"image_url" db:"image_url"`
+	Price     types.Money `json:"price" db:"price"`
+	Currency  string      `json:"currency" db:"currency"`
+	Stock     int         `json:"stock" db:"stock"`
+	DeletedAt time.Time   `json:"deleted_at" db:"deleted_at"`
+	PurgeAt   time.Time   `json:"purge_at" db:"-"` // when the purge job removes it for good
+}
+
+type GetProductTrashResponse struct {
+	Items []TrashedProduct `json:"items"`
+	Meta  Meta             `json:"meta"`
+}
+
+type RestoreProductRequest struct {
+	UserId    string `validate:"required,uuid"`
+	ProductId string `params:"id" validate:"required,uuid"`
+}
+
+// PurgeResult tells what a purge removed. Images are the urls of the stored
+// images of the removed rows, to be deleted once the rows are gone.
+type PurgeResult struct {
+	Products int      `json:"products"`
+	Images   []string `json:"images"`
+}
@@ -0,0 +1,66 @@
+package rest
+
+import (
+	"bufio"
+	"codebase-app/internal/adapter"
+	m "codebase-app/internal/middleware"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *producthandler) exportProducts(c *fiber.Ctx) error {
+	var (
+		req = &entity.ExportProductsRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.Role = l.GetRole()
+	req.SetDefaults()
+
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	export, err := h.service.ExportProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	c.Attachment(entity.ExportFilename(req.Format))
+	c.Set(fiber.HeaderContentType, entity.ExportContentType(req.Format))
+
+	// the body is written after the handler returns, an error past this point
+	// can only cut the download short
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export(context.Background(), w); err != nil {
+			log.Error().Err(err).Str("format", req.Format).Msg("service: Failed to stream the export")
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Warn().Err(err).Msg("service: Failed to flush the export")
+		}
+	})
+
+	return nil
+}
@@ -3,12 +3,14 @@ package rest
 import (
 	"codebase-app/internal/adapter"
 	m "codebase-app/internal/middleware"
+	apikey "codebase-app/internal/module/apikey/entity"
 	"codebase-app/internal/module/product/entity"
 	"codebase-app/internal/module/product/ports"
 	"codebase-app/internal/module/product/repository"
 	"codebase-app/internal/module/product/service"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
+	"context"
 	"encoding/json"
 	"time"
 
@@ -19,25 +21,47 @@ import (
 )
 
 type producthandler struct {
-	service ports.ProductService
+	service       ports.ProductService
+	importService ports.ImportService
 }
 
 func NewProductHandler() *producthandler {
 	repo := repository.NewProductRepository(adapter.Adapters.ShopeefunPostgres)
-	service := service.NewProductService(repo)
+	importRepo := repository.NewImportRepository(adapter.Adapters.ShopeefunPostgres)
 
 	return &producthandler{
-		service: service,
+		service:       service.NewProductService(repo),
+		importService: service.NewImportService(importRepo, adapter.Adapters.Validator),
 	}
 }
 
 func (h *producthandler) Register(router fiber.Router) {
 	router.Get("/products", h.getProducts)
+	router.Get("/products/slug/:slug", h.getProductBySlug)
+	router.Get("/products/:id/price-history", h.getPriceHistory)
+	router.Get("/products/export", m.AuthBearer, h.exportProducts)
+	router.Get("/feed", m.AuthBearer, h.getFeed)
+	router.Get("/exchange-rates", h.getExchangeRates)
+	router.Put("/admin/exchange-rates/:base/:quote", m.AuthBearer, m.AuthRole([]string{"admin"}), h.upsertExchangeRate)
 
 	router.Post("/products", m.UserIdHeader, h.createProduct)
-	router.Patch("/product-stocks", h.updateProductStock)
+	router.Patch("/product-stocks", m.AuthServiceOrApiKey, m.ApiKeyScope(apikey.ScopeStocksWrite), h.updateProductStock)
+	router.Post("/product-reservations", m.SignedRequest, h.reserveStock)
+	router.Post("/product-reservations/:order_id/commit", m.SignedRequest, h.commitReservation)
+	router.Post("/product-reservations/:order_id/release", m.SignedRequest, h.releaseReservation)
 	router.Patch("/products/:id", m.UserIdHeader, h.updateProduct)
 	router.Delete("/products/:id", m.UserIdHeader, h.deleteProduct)
+	router.Post("/products/bulk", m.UserIdHeader, h.bulkUpdateProducts)
+	router.Get("/products/trash", m.UserIdHeader, h.getProductTrash)
+	router.Post("/products/:id/restore", m.UserIdHeader, h.restoreProduct)
+	router.Get("/products/low-stock", m.UserIdHeader, h.getLowStockProducts)
+	router.Put("/products/:id/low-stock-threshold", m.UserIdHeader, h.setLowStockThreshold)
+	router.Get("/products/:id/price-schedules", m.UserIdHeader, h.getPriceSchedules)
+	router.Post("/products/:id/price-schedules", m.UserIdHeader, h.createPriceSchedule)
+	router.Delete("/products/:id/price-schedules/:schedule_id", m.UserIdHeader, h.cancelPriceSchedule)
+	router.Post("/product-imports", m.UserIdHeader, h.createImportJob)
+	router.Get("/product-imports/:id", m.UserIdHeader, h.getImportJob)
+	router.Get("/product-imports/:id/report", m.UserIdHeader, h.getImportReport)
 }
 
 func (h *producthandler) createProduct(c *fiber.Ctx) error {
@@ -107,6 +131,7 @@ func (h *producthandler) updateProductStock(c *fiber.Ctx) error {
 		ctx       = c.Context()
 		v         = adapter.Adapters.Validator
 		req       = &entity.UpdateProductStockRequest{}
+		l         = m.GetLocals(c)
 		message   = "Your request has been successfully processed"
 		timeStart = time.Now()
 	)
@@ -124,6 +149,8 @@ func (h *producthandler) updateProductStock(c *fiber.Ctx) error {
 	}
 
 	req.Items = reqArray
+	req.UserId = l.GetUserId()
+	req.ShopId = l.ShopId
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
 		// code, errs := errmsg.Errors(err, req)
@@ -202,3 +229,295 @@ func (h *producthandler) getProducts(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
+
+func (h *producthandler) getProductBySlug(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetProductBySlugRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.Slug = c.Params("slug")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetProductBySlug(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if resp.RedirectSlug != "" {
+		location := "/products/products/slug/" + resp.RedirectSlug
+		if req.Currency != "" {
+			location += "?currency=" + req.Currency
+		}
+		c.Location(location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(response.Success(entity.SlugRedirectResponse{
+			Slug:     resp.RedirectSlug,
+			Location: location,
+		}, "Produk telah dipindahkan"))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp.Product, ""))
+}
+
+func (h *producthandler) reserveStock(c *fiber.Ctx) error {
+	var (
+		req = &entity.ReserveStockRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.ReserveStock(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) commitReservation(c *fiber.Ctx) error {
+	return h.finishReservation(c, h.service.CommitReservation)
+}
+
+func (h *producthandler) releaseReservation(c *fiber.Ctx) error {
+	return h.finishReservation(c, h.service.ReleaseReservation)
+}
+
+func (h *producthandler) finishReservation(c *fiber.Ctx, fn func(context.Context, *entity.ReservationRequest) (entity.ReservationResponse, error)) error {
+	var (
+		req = &entity.ReservationRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.OrderId = c.Params("order_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := fn(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getFeed(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetFeedRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetFeed(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) createPriceSchedule(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreatePriceScheduleRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreatePriceSchedule(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getPriceSchedules(c *fiber.Ctx) error {
+	var (
+		req = &entity.PriceScheduleRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetPriceSchedules(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) cancelPriceSchedule(c *fiber.Ctx) error {
+	var (
+		req = &entity.PriceScheduleRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.ProductId = c.Params("id")
+	req.ScheduleId = c.Params("schedule_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.CancelPriceSchedule(ctx, req); err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *producthandler) getPriceHistory(c *fiber.Ctx) error {
+	var (
+		req = &entity.GetPriceHistoryRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefaults()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetPriceHistory(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getExchangeRates(c *fiber.Ctx) error {
+	resp, err := h.service.GetExchangeRates(c.Context())
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) upsertExchangeRate(c *fiber.Ctx) error {
+	var (
+		req = &entity.UpsertExchangeRateRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Error().Err(err).Msg("service: Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.BaseCurrency = c.Params("base")
+	req.QuoteCurrency = c.Params("quote")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if code, errs := req.CostumValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpsertExchangeRate(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
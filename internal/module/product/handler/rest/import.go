@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"codebase-app/internal/adapter"
+	m "codebase-app/internal/middleware"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *producthandler) createImportJob(c *fiber.Ctx) error {
+	var (
+		req = &entity.CreateImportJobRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.ShopId = c.FormValue("shop_id")
+	req.Format = c.FormValue("format")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Msg("service: Failed to read the uploaded file")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(map[string][]string{"file": {"file harus diisi."}}))
+	}
+
+	if req.Format == "" {
+		req.Format = entity.ImportFormatFromFilename(file.Filename)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Msg("service: Failed to open the uploaded file")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+	defer f.Close()
+
+	if req.Payload, err = io.ReadAll(f); err != nil {
+		log.Error().Err(err).Msg("service: Failed to read the uploaded file")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Str("shop_id", req.ShopId).Str("format", req.Format).Msg("service: Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.importService.CreateImportJob(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(response.Success(resp, ""))
+}
+
+func (h *producthandler) getImportJob(c *fiber.Ctx) error {
+	var (
+		req = &entity.ImportJobRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.JobId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.importService.GetImportJob(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+// getImportReport downloads the row errors of an import as CSV.
+func (h *producthandler) getImportReport(c *fiber.Ctx) error {
+	var (
+		req = &entity.ImportJobRequest{}
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.JobId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("service: Invalid request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.importService.GetImportReport(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	c.Attachment("import-" + req.JobId + "-errors.csv")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return entity.WriteImportReport(c, resp)
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	integMailer "codebase-app/internal/integration/mailer"
+	"codebase-app/internal/module/product/ports"
+	"codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/product/service"
+	"codebase-app/pkg/event"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type productWorker struct {
+	service           ports.ProductService
+	importService     ports.ImportService
+	stockAlertService ports.StockAlertService
+}
+
+func NewProductWorker() *productWorker {
+	db := adapter.Adapters.ShopeefunPostgres
+	repo := repository.NewProductRepository(db)
+	importRepo := repository.NewImportRepository(db)
+
+	return &productWorker{
+		service:           service.NewProductService(repo),
+		importService:     service.NewImportService(importRepo, adapter.Adapters.Validator),
+		stockAlertService: service.NewStockAlertService(repo),
+	}
+}
+
+// NewStockAlertMailer returns the outbox hook emailing the stock alerts.
+func NewStockAlertMailer() event.Publisher {
+	repo := repository.NewProductRepository(adapter.Adapters.ShopeefunPostgres)
+	return service.NewStockAlertMailer(repo, integMailer.NewMailerIntegration())
+}
+
+// Start applies price schedules when they are due, runs pending import jobs
+// and records stock alerts until ctx is done.
+func (w *productWorker) Start(ctx context.Context) {
+	go service.StockAlertScheduler.Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		n, err := w.stockAlertService.NotifyStockAlerts(ctx)
+		if n > 0 {
+			log.Info().Int("alerts", n).Msg("worker: Recorded stock alerts")
+		}
+
+		return nil, err
+	})
+
+	go service.ImportScheduler.Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		n, err := w.importService.RunImportJobs(ctx)
+		if n > 0 {
+			log.Info().Int("jobs", n).Msg("worker: Ran import jobs")
+		}
+
+		return nil, err
+	})
+
+	service.PriceScheduler.Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		res, err := w.service.ApplyPriceSchedules(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Applied+res.Activated+res.Expired > 0 {
+			log.Info().Any("result", res).Msg("worker: Applied price schedules")
+		}
+
+		return res.NextDueAt, nil
+	})
+}
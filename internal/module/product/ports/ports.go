@@ -2,7 +2,9 @@ package ports
 
 import (
 	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/types"
 	"context"
+	"time"
 )
 
 type ProductService interface {
@@ -11,6 +13,25 @@ type ProductService interface {
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error)
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 	UpdateProductStock(ctx context.Context, req *entity.UpdateProductStockRequest) error
+	GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (entity.GetProductBySlugResponse, error)
+	ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.ReservationResponse, error)
+	CommitReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error)
+	GetFeed(ctx context.Context, req *entity.GetFeedRequest) (entity.GetFeedResponse, error)
+	CreatePriceSchedule(ctx context.Context, req *entity.CreatePriceScheduleRequest) (entity.PriceSchedule, error)
+	GetPriceSchedules(ctx context.Context, req *entity.PriceScheduleRequest) ([]entity.PriceSchedule, error)
+	CancelPriceSchedule(ctx context.Context, req *entity.PriceScheduleRequest) error
+	ApplyPriceSchedules(ctx context.Context, now time.Time) (entity.ApplyPriceSchedulesResult, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error)
+	GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	UpsertExchangeRate(ctx context.Context, req *entity.UpsertExchangeRateRequest) (entity.ExchangeRate, error)
+	ExportProducts(ctx context.Context, req *entity.ExportProductsRequest) (entity.ExportFunc, error)
+	BulkUpdateProducts(ctx context.Context, req *entity.BulkProductsRequest) (entity.BulkProductsResponse, error)
+	GetProductTrash(ctx context.Context, req *entity.GetProductTrashRequest) (entity.GetProductTrashResponse, error)
+	RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) (entity.UpsertProductResponse, error)
+	PurgeProducts(ctx context.Context, now time.Time) (entity.PurgeResult, error)
+	SetLowStockThreshold(ctx context.Context, req *entity.SetLowStockThresholdRequest) (entity.SetLowStockThresholdResponse, error)
+	GetLowStockProducts(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error)
 }
 
 type ProductRepository interface {
@@ -19,7 +40,61 @@ type ProductRepository interface {
 	UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error)
 	DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error
 	UpdateProductStock(ctx context.Context, req *entity.UpdateProductStockRequest) error
+	ResolveProductSlug(ctx context.Context, slug string) (entity.SlugLookup, error)
+	ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.ReservationResponse, error)
+	CommitReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error)
+	GetFeed(ctx context.Context, req *entity.GetFeedRequest) (entity.GetFeedResponse, error)
+	CreatePriceSchedule(ctx context.Context, req *entity.CreatePriceScheduleRequest) (entity.PriceSchedule, error)
+	GetPriceSchedules(ctx context.Context, productId string) ([]entity.PriceSchedule, error)
+	CancelPriceSchedule(ctx context.Context, req *entity.PriceScheduleRequest) error
+	ApplyPriceSchedules(ctx context.Context, now time.Time) (entity.ApplyPriceSchedulesResult, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error)
+	GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	GetRatesTo(ctx context.Context, quote string) (map[string]types.Rate, error)
+	UpsertExchangeRate(ctx context.Context, req *entity.UpsertExchangeRateRequest) (entity.ExchangeRate, error)
+	ExportProducts(ctx context.Context, req *entity.GetProductsRequest, fn func(p *entity.ExportProduct) error) error
+	BulkUpdateProducts(ctx context.Context, req *entity.BulkProductsRequest) (entity.BulkProductsResponse, error)
+	GetProductTrash(ctx context.Context, req *entity.GetProductTrashRequest, since time.Time) (entity.GetProductTrashResponse, error)
+	RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest, since time.Time) (entity.UpsertProductResponse, error)
+	PurgeProducts(ctx context.Context, before time.Time, limit int) (entity.PurgeResult, error)
+	SetLowStockThreshold(ctx context.Context, req *entity.SetLowStockThresholdRequest) (entity.SetLowStockThresholdResponse, error)
+	GetLowStockProducts(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error)
+	NotifyStockAlerts(ctx context.Context, limit int) (int, error)
+	GetStockAlertEmail(ctx context.Context, alertId int64) (string, error)
+	MarkStockAlertEmailed(ctx context.Context, alertId int64) error
 
 	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
 	IsProductOwner(ctx context.Context, userId, productId string) (bool, error)
+	IsProductsOwner(ctx context.Context, userId, shopId string, productIds []string) (bool, error)
+}
+
+type StockAlertService interface {
+	NotifyStockAlerts(ctx context.Context) (int, error)
+}
+
+type Validator interface {
+	Validate(i any) error
+}
+
+type ImportService interface {
+	CreateImportJob(ctx context.Context, req *entity.CreateImportJobRequest) (entity.ImportJob, error)
+	GetImportJob(ctx context.Context, req *entity.ImportJobRequest) (entity.ImportJob, error)
+	GetImportReport(ctx context.Context, req *entity.ImportJobRequest) ([]entity.ImportRowError, error)
+	Import(ctx context.Context, req *entity.CreateImportJobRequest) (entity.ImportJob, error)
+	RunImportJobs(ctx context.Context) (int, error)
+}
+
+type ImportRepository interface {
+	CreateImportJob(ctx context.Context, req *entity.CreateImportJobRequest, status string) (entity.ImportJob, error)
+	GetImportJob(ctx context.Context, id string) (entity.ImportJob, error)
+	GetImportErrors(ctx context.Context, id string) ([]entity.ImportRowError, error)
+	ClaimImportJob(ctx context.Context) (*entity.ImportJob, error)
+	HeartbeatImportJob(ctx context.Context, job *entity.ImportJob) error
+	UpdateImportProgress(ctx context.Context, job *entity.ImportJob) error
+	FinishImportJob(ctx context.Context, job *entity.ImportJob, res *entity.ImportResult) error
+	MissingCategoryIds(ctx context.Context, ids []string) ([]string, error)
+	ImportProducts(ctx context.Context, job *entity.ImportJob, rows []entity.ImportRow, progress func(imported int)) (created, updated int, err error)
+
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
 }
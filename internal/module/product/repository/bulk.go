@@ -0,0 +1,274 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// BulkUpdateProducts applies the action of req to every selected product of
+// the user in a single transaction. Nothing is changed when any product fails
+// or on a dry run, the results then tell what would have happened.
+func (p *productRepository) BulkUpdateProducts(ctx context.Context, req *entity.BulkProductsRequest) (entity.BulkProductsResponse, error) {
+	var res = entity.BulkProductsResponse{
+		Action: req.Action,
+		DryRun: req.DryRun,
+		Items:  make([]entity.BulkProductResult, 0),
+	}
+
+	if req.Action == entity.BulkActionMoveCategory {
+		missing, err := p.MissingCategoryIds(ctx, []string{req.CategoryId})
+		if err != nil {
+			return res, err
+		}
+
+		if len(missing) > 0 {
+			log.Warn().Str("category_id", req.CategoryId).Msg("repository: Category not found")
+			return res, errmsg.NewCustomErrors(400, errmsg.WithErrors("category_id", "category_id does not exist."))
+		}
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: BulkUpdateProducts failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	products, err := p.getBulkProductsForUpdate(ctx, tx, req)
+	if err != nil {
+		return res, err
+	}
+
+	found := make(map[string]bool, len(products))
+	afters := make([]entity.BulkProductState, len(products))
+
+	for i, product := range products {
+		found[product.Id] = true
+
+		before := entity.BulkProductState{CategoryId: product.CategoryId, Price: product.Price, Stock: product.Stock}
+		item := entity.BulkProductResult{ProductId: product.Id, Name: product.Name, Before: &before}
+
+		after, err := req.Apply(before)
+		switch {
+		case err != nil:
+			item.Status = entity.BulkStatusFailed
+			item.Error = err.Error() + "."
+			res.Failed++
+		case after.Deleted:
+			item.Status = entity.BulkStatusDeleted
+			item.After = &after
+			res.Changed++
+		case after == before:
+			item.Status = entity.BulkStatusUnchanged
+			item.After = &after
+		default:
+			item.Status = entity.BulkStatusUpdated
+			item.After = &after
+			res.Changed++
+		}
+
+		afters[i] = after
+		res.Items = append(res.Items, item)
+	}
+
+	for _, id := range req.ProductIds {
+		if !found[id] {
+			res.Items = append(res.Items, entity.BulkProductResult{ProductId: id, Status: entity.BulkStatusFailed, Error: "Product not found."})
+			res.Failed++
+		}
+	}
+
+	res.Matched = len(products)
+
+	if res.Failed > 0 {
+		log.Warn().Str("action", req.Action).Int("failed", res.Failed).Msg("repository: Bulk update rejected")
+
+		if req.DryRun {
+			return res, nil
+		}
+
+		errCustom := errmsg.NewCustomErrors(422, errmsg.WithMessage("No product was changed, "+strconv.Itoa(res.Failed)+" of the products cannot be changed"))
+		for _, item := range res.Items {
+			if item.Status == entity.BulkStatusFailed {
+				errCustom.Add(item.ProductId, item.Error)
+			}
+		}
+		return res, errCustom
+	}
+
+	if req.DryRun {
+		return res, nil
+	}
+
+	for i, product := range products {
+		if res.Items[i].Status == entity.BulkStatusUnchanged {
+			continue
+		}
+
+		if err = p.applyBulkChange(ctx, tx, product, afters[i]); err != nil {
+			log.Error().Err(err).Str("product_id", product.Id).Msg("repository: BulkUpdateProducts failed")
+			return res, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: BulkUpdateProducts failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// getBulkProductsForUpdate locks the live products selected by req, among the
+// products of the user.
+func (p *productRepository) getBulkProductsForUpdate(ctx context.Context, tx *sqlx.Tx, req *entity.BulkProductsRequest) ([]entity.UpsertProductResponse, error) {
+	var (
+		res = make([]entity.UpsertProductResponse, 0)
+		arg = map[string]any{"user_id": req.UserId}
+	)
+
+	query := `
+		SELECT
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+		FROM
+			products
+		WHERE
+			deleted_at IS NULL
+			AND shop_id IN (SELECT id FROM shops WHERE user_id = :user_id AND deleted_at IS NULL)
+	`
+
+	if len(req.ProductIds) > 0 {
+		query += " AND id = ANY(CAST(:product_ids AS UUID[]))"
+		arg["product_ids"] = pq.Array(req.ProductIds)
+	}
+
+	if f := req.Filter; f != nil {
+		query += " AND shop_id = :shop_id"
+		arg["shop_id"] = f.ShopId
+
+		if f.CategoryId != "" {
+			query += " AND category_id = :category_id"
+			arg["category_id"] = f.CategoryId
+		}
+
+		if f.Name != "" {
+			query += " AND name ILIKE '%' || :name || '%'"
+			arg["name"] = f.Name
+		}
+
+		if f.PriceMin != nil {
+			query += " AND price >= :price_min"
+			arg["price_min"] = *f.PriceMin
+		}
+
+		if f.PriceMax != nil {
+			query += " AND price <= :price_max"
+			arg["price_max"] = *f.PriceMax
+		}
+	}
+
+	// one more than allowed tells a filter matching too many products apart
+	query += " ORDER BY created_at, id LIMIT :limit FOR UPDATE"
+	arg["limit"] = entity.MaxBulkProducts + 1
+
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: getBulkProductsForUpdate failed")
+		return res, err
+	}
+
+	if err = tx.SelectContext(ctx, &res, tx.Rebind(query), args...); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: getBulkProductsForUpdate failed")
+		return res, err
+	}
+
+	if len(res) > entity.MaxBulkProducts {
+		log.Warn().Any("payload", req).Msg("repository: Bulk filter matches too many products")
+		return res, errmsg.NewCustomErrors(400, errmsg.WithErrors("filter", "filter matches more than "+strconv.Itoa(entity.MaxBulkProducts)+" products, narrow it down."))
+	}
+
+	return res, nil
+}
+
+// applyBulkChange writes the new state of a product with its audit entry,
+// price history and feed event, like the single product endpoints do.
+func (p *productRepository) applyBulkChange(ctx context.Context, tx *sqlx.Tx, before entity.UpsertProductResponse, state entity.BulkProductState) error {
+	if state.Deleted {
+		query := `UPDATE products SET deleted_at = NOW() WHERE id = $1`
+
+		if _, err := tx.ExecContext(ctx, query, before.Id); err != nil {
+			return err
+		}
+
+		err := auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionDelete,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   before.Id,
+			Before:     before,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, tx, entity.EventProductDeleted, before.Id, entity.ProductRef{
+			Id:     before.Id,
+			ShopId: before.ShopId,
+		})
+	}
+
+	var after entity.UpsertProductResponse
+
+	query := `
+		UPDATE
+			products
+		SET
+			category_id = $2,
+			price = $3,
+			stock = $4,
+			updated_at = NOW()
+		WHERE
+			id = $1
+		RETURNING
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+	`
+
+	err := tx.QueryRowxContext(ctx, query, before.Id, state.CategoryId, state.Price, state.Stock).StructScan(&after)
+	if err != nil {
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   after.Id,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = recordPriceChange(ctx, tx, after.Id, before.Price, after.Price, entity.PriceSourceBulk); err != nil {
+		return err
+	}
+
+	if isRestock(int64(before.Stock), int64(after.Stock)) {
+		if err = recordFeedEvent(ctx, tx, after.ShopId, after.Id, entity.FeedKindRestocked); err != nil {
+			return err
+		}
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventProductUpdated, after.Id, after); err != nil {
+		return err
+	}
+
+	return recordStockChange(ctx, tx, after.Id, after.ShopId, int64(before.Stock), int64(after.Stock), entity.StockReasonBulk)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+const exchangeRateColumns = `
+	base_currency, quote_currency, rate, updated_by, updated_at
+`
+
+func (p *productRepository) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	res := make([]entity.ExchangeRate, 0)
+
+	query := `
+		SELECT` + exchangeRateColumns + `
+		FROM exchange_rates
+		ORDER BY base_currency, quote_currency
+	`
+
+	if err := p.db.SelectContext(ctx, &res, query); err != nil {
+		log.Error().Err(err).Msg("repository: GetExchangeRates failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetRatesTo returns the rates converting each known currency into quote. A
+// missing direct rate falls back to the inverse of the opposite rate.
+func (p *productRepository) GetRatesTo(ctx context.Context, quote string) (map[string]types.Rate, error) {
+	var (
+		res  = make(map[string]types.Rate)
+		data = make([]entity.ExchangeRate, 0)
+	)
+
+	query := `
+		SELECT` + exchangeRateColumns + `
+		FROM exchange_rates
+		WHERE quote_currency = $1 OR base_currency = $1
+	`
+
+	if err := p.db.SelectContext(ctx, &data, query, quote); err != nil {
+		log.Error().Err(err).Str("quote", quote).Msg("repository: GetRatesTo failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		if d.QuoteCurrency == quote {
+			res[d.BaseCurrency] = d.Rate
+		}
+	}
+
+	for _, d := range data {
+		if _, ok := res[d.QuoteCurrency]; d.BaseCurrency == quote && !ok {
+			res[d.QuoteCurrency] = d.Rate.Inverse()
+		}
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) UpsertExchangeRate(ctx context.Context, req *entity.UpsertExchangeRateRequest) (entity.ExchangeRate, error) {
+	var (
+		res    entity.ExchangeRate
+		before *entity.ExchangeRate
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpsertExchangeRate failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	var current entity.ExchangeRate
+
+	query := `
+		SELECT` + exchangeRateColumns + `
+		FROM exchange_rates
+		WHERE base_currency = $1 AND quote_currency = $2
+		FOR UPDATE
+	`
+
+	err = tx.QueryRowxContext(ctx, query, req.BaseCurrency, req.QuoteCurrency).StructScan(&current)
+	switch {
+	case err == nil:
+		before = &current
+	case err != sql.ErrNoRows:
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpsertExchangeRate failed")
+		return res, err
+	}
+
+	query = `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (base_currency, quote_currency) DO UPDATE
+		SET
+			rate = EXCLUDED.rate,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING` + exchangeRateColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		req.BaseCurrency,
+		req.QuoteCurrency,
+		req.Rate,
+		auditRepo.ActorFromContext(ctx),
+	).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpsertExchangeRate failed")
+		return res, err
+	}
+
+	entry := &auditEnt.Entry{
+		Action:     auditEnt.ActionCreate,
+		EntityType: auditEnt.EntityExchangeRate,
+		EntityId:   res.BaseCurrency + "/" + res.QuoteCurrency,
+		After:      res,
+	}
+	if before != nil {
+		entry.Action = auditEnt.ActionUpdate
+		entry.Before = before
+	}
+
+	if err = auditRepo.Record(ctx, tx, entry); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpsertExchangeRate failed")
+		return res, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	outboxRepo "codebase-app/internal/module/outbox/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/event"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// recordEvent writes a product event to the outbox in the transaction of the
+// change it describes.
+func recordEvent(ctx context.Context, tx sqlx.ExecerContext, eventType, productId string, payload any) error {
+	return outboxRepo.Record(ctx, tx, event.New(eventType, entity.AggregateProduct, productId, payload))
+}
+
+// recordStockChange writes a stock.changed event when the stock of a product
+// did change, and evaluates its stock level again for the low stock alerts.
+func recordStockChange(ctx context.Context, tx sqlx.ExtContext, productId, shopId string, from, to int64, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	err := recordEvent(ctx, tx, entity.EventStockChanged, productId, entity.StockChangedPayload{
+		ProductId: productId,
+		ShopId:    shopId,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return updateStockLevel(ctx, tx, productId)
+}
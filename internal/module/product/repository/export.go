@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"codebase-app/internal/module/product/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExportProducts calls fn with every product matching the filters of req, in
+// creation order. Rows are read as fn consumes them, so the catalog is never
+// held in memory.
+func (p *productRepository) ExportProducts(ctx context.Context, req *entity.GetProductsRequest, fn func(p *entity.ExportProduct) error) error {
+	arg := make(map[string]any)
+
+	query := `
+		SELECT
+			id,
+			shop_id,
+			(SELECT s.name FROM shops s WHERE s.id = products.shop_id) AS shop_name,
+			category_id,
+			COALESCE((SELECT c.name FROM product_categories c WHERE c.id = products.category_id), '') AS category_name,
+			sku,
+			name,
+			slug,
+			description,
+			image_url,
+			price,
+			` + EffectivePriceSQL + ` AS sale_price,
+			` + saleEndsAtSQL + ` AS sale_ends_at,
+			currency,
+			stock,
+			updated_at
+		FROM
+			products
+		WHERE` + productFiltersSQL(req, arg) + `
+		ORDER BY created_at, id
+	`
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ExportProducts failed")
+		return err
+	}
+	defer nstmt.Close()
+
+	rows, err := nstmt.QueryxContext(ctx, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ExportProducts failed")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product entity.ExportProduct
+
+		if err := rows.StructScan(&product); err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: ExportProducts failed")
+			return err
+		}
+
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ExportProducts failed")
+		return err
+	}
+
+	return nil
+}
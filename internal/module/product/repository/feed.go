@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// recordFeedEvent appends a product to the follower feed of its shop. It is
+// written in the same transaction as the product change.
+func recordFeedEvent(ctx context.Context, tx sqlx.ExecerContext, shopId, productId, kind string) error {
+	query := `
+		INSERT INTO product_feed_events (shop_id, product_id, kind)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := tx.ExecContext(ctx, query, shopId, productId, kind)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Str("kind", kind).Msg("repository: recordFeedEvent failed")
+		return err
+	}
+
+	return nil
+}
+
+// isRestock reports whether a stock change makes a sold out product available.
+func isRestock(before, after int64) bool {
+	return before <= 0 && after > 0
+}
+
+func (p *productRepository) GetFeed(ctx context.Context, req *entity.GetFeedRequest) (entity.GetFeedResponse, error) {
+	var (
+		res    = entity.GetFeedResponse{Items: make([]entity.FeedItem, 0, req.Limit)}
+		data   = make([]entity.FeedItem, 0, req.Limit+1)
+		cursor int64
+	)
+
+	if req.Cursor != "" {
+		var err error
+		cursor, err = strconv.ParseInt(req.Cursor, 10, 64)
+		if err != nil || cursor <= 0 {
+			log.Warn().Any("payload", req).Msg("repository: GetFeed invalid cursor")
+			return res, errmsg.NewCustomErrors(400, errmsg.WithErrors("cursor", "cursor is not valid."))
+		}
+	}
+
+	// walks the (shop_id, id) index of every followed shop, newest first
+	query := `
+		SELECT
+			e.id AS event_id,
+			e.kind,
+			e.created_at AS occurred_at,
+			p.id AS "product.id",
+			p.category_id AS "product.category_id",
+			p.shop_id AS "product.shop_id",
+			p.name AS "product.name",
+			p.slug AS "product.slug",
+			p.image_url AS "product.image_url",
+			p.sku AS "product.sku",
+			` + EffectivePriceSQL + ` AS "product.price",
+			p.price AS "product.original_price",
+			p.currency AS "product.currency",
+			` + saleEndsAtSQL + ` AS "product.sale_ends_at",
+			` + lowestPrice30dSQL("p") + ` AS "product.lowest_price_30d",
+			p.stock AS "product.stock",
+			p.rating_avg AS "product.rating_avg",
+			p.rating_count AS "product.rating_count",
+			p.created_at AS "product.created_at",
+			p.updated_at AS "product.updated_at"
+		FROM
+			shop_followers f
+		JOIN
+			product_feed_events e ON e.shop_id = f.shop_id
+		JOIN
+			products p ON p.id = e.product_id
+		JOIN
+			shops s ON s.id = e.shop_id
+		WHERE
+			f.user_id = $1
+			AND ($2 = 0 OR e.id < $2)
+			AND p.deleted_at IS NULL
+			AND s.deleted_at IS NULL
+			AND s.status = 'active'
+		ORDER BY e.id DESC
+		LIMIT $3
+	`
+
+	err := p.db.SelectContext(ctx, &data, query, req.UserId, cursor, req.Limit+1)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetFeed failed")
+		return res, err
+	}
+
+	if len(data) > req.Limit {
+		data = data[:req.Limit]
+		next := strconv.FormatInt(data[len(data)-1].EventId, 10)
+		res.NextCursor = &next
+	}
+
+	shopIds := make([]string, 0, len(data))
+	for _, d := range data {
+		shopIds = append(shopIds, d.Product.ShopId)
+	}
+
+	schedules, err := loadShopSchedules(ctx, p.db, shopIds)
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	for _, d := range data {
+		d.Product.IsOpen = true
+		d.Product.IsAvailable = d.Product.Stock > 0
+
+		if schedule, ok := schedules[d.Product.ShopId]; ok {
+			d.Product.IsOpen = schedule.IsOpen(now)
+			d.Product.IsAvailable = d.Product.IsAvailable && schedule.IsAvailable(now)
+		}
+
+		res.Items = append(res.Items, d)
+	}
+
+	return res, nil
+}
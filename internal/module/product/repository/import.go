@@ -0,0 +1,421 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/product/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const importJobColumns = `
+	id, shop_id, user_id, format, status, total_rows, validated_rows, imported_rows,
+	created_count, updated_count, error_count, error, created_at, started_at, finished_at, lease
+`
+
+// importProgressEvery is how many rows are imported between progress reports.
+const importProgressEvery = 100
+
+func NewImportRepository(db *sqlx.DB) ports.ImportRepository {
+	return &productRepository{
+		db,
+	}
+}
+
+func (p *productRepository) CreateImportJob(ctx context.Context, req *entity.CreateImportJobRequest, status string) (entity.ImportJob, error) {
+	var (
+		res       entity.ImportJob
+		startedAt *time.Time
+	)
+
+	if status != entity.ImportPending {
+		now := time.Now()
+		startedAt = &now
+	}
+
+	query := `
+		INSERT INTO product_import_jobs (shop_id, user_id, format, status, payload, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING` + importJobColumns + `, payload
+	`
+
+	err := p.db.QueryRowxContext(ctx, query, req.ShopId, req.UserId, req.Format, status, req.Payload, startedAt).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", req.ShopId).Msg("repository: CreateImportJob failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) GetImportJob(ctx context.Context, id string) (entity.ImportJob, error) {
+	var res entity.ImportJob
+
+	query := `SELECT` + importJobColumns + `FROM product_import_jobs WHERE id = $1`
+
+	if err := p.db.GetContext(ctx, &res, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository: Import job not found")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Import job not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository: GetImportJob failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) GetImportErrors(ctx context.Context, id string) ([]entity.ImportRowError, error) {
+	var (
+		res  = make([]entity.ImportRowError, 0)
+		data []byte
+	)
+
+	query := `SELECT errors FROM product_import_jobs WHERE id = $1`
+
+	if err := p.db.GetContext(ctx, &data, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository: Import job not found")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Import job not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository: GetImportErrors failed")
+		return res, err
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: GetImportErrors failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ClaimImportJob starts the oldest pending job, or a running job whose worker
+// stopped heartbeating. Every claim takes a new lease, so the worker that lost
+// the job can no longer write it. It returns nil when there is nothing to run.
+func (p *productRepository) ClaimImportJob(ctx context.Context) (*entity.ImportJob, error) {
+	var res = new(entity.ImportJob)
+
+	query := `
+		UPDATE product_import_jobs
+		SET
+			status = 'validating',
+			validated_rows = 0,
+			imported_rows = 0,
+			lease = lease + 1,
+			started_at = NOW(),
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM product_import_jobs
+			WHERE
+				status = 'pending'
+				OR (status IN ('validating', 'importing') AND updated_at < NOW() - INTERVAL '10 minutes')
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + importJobColumns + `, payload
+	`
+
+	if err := p.db.QueryRowxContext(ctx, query).StructScan(res); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Error().Err(err).Msg("repository: ClaimImportJob failed")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// HeartbeatImportJob tells the job is still running so it is not claimed
+// again. It returns entity.ErrImportJobLost when the lease was taken over.
+func (p *productRepository) HeartbeatImportJob(ctx context.Context, job *entity.ImportJob) error {
+	query := `UPDATE product_import_jobs SET updated_at = NOW() WHERE id = $1 AND lease = $2`
+
+	return p.writeImportJob(ctx, p.db, "HeartbeatImportJob", job, query, job.Id, job.Lease)
+}
+
+func (p *productRepository) UpdateImportProgress(ctx context.Context, job *entity.ImportJob) error {
+	query := `
+		UPDATE product_import_jobs
+		SET
+			status = $3,
+			total_rows = $4,
+			validated_rows = $5,
+			imported_rows = $6,
+			updated_at = NOW()
+		WHERE id = $1 AND lease = $2
+	`
+
+	return p.writeImportJob(ctx, p.db, "UpdateImportProgress", job, query, job.Id, job.Lease, job.Status, job.TotalRows, job.ValidatedRows, job.ImportedRows)
+}
+
+func (p *productRepository) FinishImportJob(ctx context.Context, job *entity.ImportJob, res *entity.ImportResult) error {
+	errs := res.Errors
+	if errs == nil {
+		errs = make([]entity.ImportRowError, 0)
+	}
+
+	errsJson, err := json.Marshal(errs)
+	if err != nil {
+		log.Error().Err(err).Str("id", job.Id).Msg("repository: FinishImportJob failed")
+		return err
+	}
+
+	rows := make(map[int]bool)
+	for _, e := range errs {
+		rows[e.Row] = true
+	}
+
+	query := `
+		UPDATE product_import_jobs
+		SET
+			status = $3,
+			created_count = $4,
+			updated_count = $5,
+			error_count = $6,
+			errors = $7,
+			error = $8,
+			payload = NULL,
+			updated_at = NOW(),
+			finished_at = NOW()
+		WHERE id = $1 AND lease = $2
+	`
+
+	return p.writeImportJob(ctx, p.db, "FinishImportJob", job, query, job.Id, job.Lease, res.Status, res.CreatedCount, res.UpdatedCount, len(rows), errsJson, res.Error)
+}
+
+// writeImportJob runs an update of the job guarded by its lease and returns
+// entity.ErrImportJobLost when it matched no row.
+func (p *productRepository) writeImportJob(ctx context.Context, db sqlx.ExecerContext, name string, job *entity.ImportJob, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Error().Err(err).Str("id", job.Id).Msg("repository: " + name + " failed")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Str("id", job.Id).Msg("repository: " + name + " failed")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Str("id", job.Id).Int("lease", job.Lease).Msg("repository: Import job lease lost")
+		return entity.ErrImportJobLost
+	}
+
+	return nil
+}
+
+func (p *productRepository) MissingCategoryIds(ctx context.Context, ids []string) ([]string, error) {
+	var res = make([]string, 0)
+
+	query := `
+		SELECT t.id
+		FROM UNNEST(CAST($1 AS UUID[])) AS t(id)
+		WHERE NOT EXISTS (SELECT 1 FROM product_categories c WHERE c.id = t.id)
+	`
+
+	if err := p.db.SelectContext(ctx, &res, query, pq.Array(ids)); err != nil {
+		log.Error().Err(err).Msg("repository: MissingCategoryIds failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ImportProducts upserts the rows by sku in a single transaction, so a failed
+// import leaves no row behind. progress is called with the number of imported
+// rows as the import goes.
+func (p *productRepository) ImportProducts(ctx context.Context, job *entity.ImportJob, rows []entity.ImportRow, progress func(imported int)) (created, updated int, err error) {
+	ctx = auditRepo.WithActor(ctx, job.UserId)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", job.Id).Msg("repository: ImportProducts failed")
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	for i := range rows {
+		isCreated, err := p.importProduct(ctx, tx, job.ShopId, &rows[i])
+		if err != nil {
+			log.Error().Err(err).Str("job_id", job.Id).Int("row", rows[i].Row).Msg("repository: ImportProducts failed")
+			return 0, 0, err
+		}
+
+		if isCreated {
+			created++
+		} else {
+			updated++
+		}
+
+		if (i+1)%importProgressEvery == 0 {
+			progress(i + 1)
+		}
+	}
+
+	// the job row stays locked until the commit, so the job cannot be claimed
+	// again between the lease check and the commit
+	query := `UPDATE product_import_jobs SET updated_at = NOW() WHERE id = $1 AND lease = $2`
+	if err = p.writeImportJob(ctx, tx, "ImportProducts", job, query, job.Id, job.Lease); err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Str("job_id", job.Id).Msg("repository: ImportProducts failed")
+		return 0, 0, err
+	}
+
+	progress(len(rows))
+	return created, updated, nil
+}
+
+// importProduct creates the product of a row, or updates the product of the
+// shop with the same sku. It reports whether the product was created.
+func (p *productRepository) importProduct(ctx context.Context, tx *sqlx.Tx, shopId string, row *entity.ImportRow) (bool, error) {
+	var (
+		before entity.UpsertProductResponse
+		after  entity.UpsertProductResponse
+	)
+
+	query := `
+		SELECT
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+		FROM
+			products
+		WHERE
+			shop_id = $1
+			AND sku = $2
+			AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.QueryRowxContext(ctx, query, shopId, row.Sku).StructScan(&before)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+
+	if err == sql.ErrNoRows {
+		query = `
+			INSERT INTO products (shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), (SELECT currency FROM shops WHERE id = $1)), $10)
+			RETURNING
+				id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+		`
+
+		_, err = p.withProductSlug(ctx, tx, row.Name, "", func(slug string) error {
+			return tx.QueryRowxContext(ctx, query,
+				shopId,
+				row.CategoryId,
+				row.Name,
+				slug,
+				row.Description,
+				row.ImageUrl,
+				row.Sku,
+				row.Price,
+				row.Currency,
+				row.Stock,
+			).StructScan(&after)
+		})
+		if err != nil {
+			return false, err
+		}
+
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionCreate,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   after.Id,
+			After:      after,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if err = recordFeedEvent(ctx, tx, shopId, after.Id, entity.FeedKindCreated); err != nil {
+			return false, err
+		}
+
+		if err = recordEvent(ctx, tx, entity.EventProductCreated, after.Id, after); err != nil {
+			return false, err
+		}
+
+		// a product created sold out is out of stock right away, and alerts
+		return true, updateStockLevel(ctx, tx, after.Id)
+	}
+
+	slug, err := p.renameProductSlug(ctx, tx, before, row.Name)
+	if err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE products
+		SET
+			category_id = $2,
+			name = $3,
+			slug = $4,
+			description = $5,
+			image_url = $6,
+			price = $7,
+			currency = COALESCE(NULLIF($8, ''), currency),
+			stock = $9,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query,
+		before.Id,
+		row.CategoryId,
+		row.Name,
+		slug,
+		row.Description,
+		row.ImageUrl,
+		row.Price,
+		row.Currency,
+		row.Stock,
+	).StructScan(&after)
+	if err != nil {
+		return false, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   after.Id,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err = recordPriceChange(ctx, tx, after.Id, before.Price, after.Price, entity.PriceSourceImport); err != nil {
+		return false, err
+	}
+
+	if isRestock(int64(before.Stock), int64(after.Stock)) {
+		if err = recordFeedEvent(ctx, tx, shopId, after.Id, entity.FeedKindRestocked); err != nil {
+			return false, err
+		}
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventProductUpdated, after.Id, after); err != nil {
+		return false, err
+	}
+
+	return false, recordStockChange(ctx, tx, after.Id, shopId, int64(before.Stock), int64(after.Stock), entity.StockReasonImport)
+}
@@ -0,0 +1,439 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// EffectivePriceSQL is the price a buyer pays for a row of products: the base
+// price discounted by the running sale. The sale end is compared with NOW() so
+// an ended sale stops applying even before the scheduler clears it.
+const EffectivePriceSQL = `(CASE
+	WHEN sale_ends_at IS NULL OR sale_ends_at <= NOW() THEN price
+	ELSE ` + salePriceSQL + `
+END)`
+
+// salePriceSQL is the base price discounted by the sale terms of the row,
+// whether the sale is still running or not.
+const salePriceSQL = `(CASE
+	WHEN sale_discount_type = 'percentage' THEN ROUND(price * (100 - sale_discount_value) / 100, 4)
+	ELSE GREATEST(price - sale_discount_value, 0)
+END)`
+
+// convertedPriceSQL is the effective price of a row of products converted into
+// the currency bound to :currency, either through the direct exchange rate or
+// the inverse of the opposite one. It is NULL when no rate is known, so such a
+// product never matches a price filter.
+const convertedPriceSQL = `(CASE
+	WHEN products.currency = :currency THEN ` + EffectivePriceSQL + `
+	ELSE ` + EffectivePriceSQL + ` * COALESCE(
+		(SELECT er.rate FROM exchange_rates er WHERE er.base_currency = products.currency AND er.quote_currency = :currency),
+		(SELECT 1 / er.rate FROM exchange_rates er WHERE er.base_currency = :currency AND er.quote_currency = products.currency)
+	)
+END)`
+
+// saleEndsAtSQL is the end of the running sale, NULL when there is none.
+const saleEndsAtSQL = `(CASE WHEN sale_ends_at > NOW() THEN sale_ends_at END)`
+
+const priceScheduleColumns = `
+	id, product_id, kind, price, discount_type, discount_value, starts_at, ends_at, status, created_at, updated_at
+`
+
+func (p *productRepository) CreatePriceSchedule(ctx context.Context, req *entity.CreatePriceScheduleRequest) (entity.PriceSchedule, error) {
+	var res entity.PriceSchedule
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreatePriceSchedule failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// the product lock serializes the overlap check below
+	if _, err = p.getProductForUpdate(ctx, tx, req.ProductId); err != nil {
+		return res, err
+	}
+
+	var discountType *string
+	if req.Kind == entity.PriceScheduleKindSale {
+		discountType = &req.DiscountType
+
+		var overlaps bool
+
+		query := `
+			SELECT EXISTS (
+				SELECT 1
+				FROM product_price_schedules
+				WHERE
+					product_id = $1
+					AND kind = 'sale'
+					AND status IN ('scheduled', 'active')
+					AND starts_at < $3
+					AND ends_at > $2
+			)
+		`
+
+		err = tx.GetContext(ctx, &overlaps, query, req.ProductId, req.StartsAt, req.EndsAt)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: CreatePriceSchedule failed")
+			return res, err
+		}
+
+		if overlaps {
+			log.Warn().Any("payload", req).Msg("repository: Sale overlaps another sale")
+			return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Sale overlaps another sale of the product"))
+		}
+	}
+
+	var discountValue *types.Money
+	if req.DiscountValue > 0 {
+		discountValue = &req.DiscountValue
+	}
+
+	query := `
+		INSERT INTO product_price_schedules (
+			product_id, kind, price, discount_type, discount_value, starts_at, ends_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING` + priceScheduleColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		req.ProductId,
+		req.Kind,
+		req.Price,
+		discountType,
+		discountValue,
+		req.StartsAt,
+		req.EndsAt,
+	).StructScan(&res)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreatePriceSchedule failed")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreatePriceSchedule failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) GetPriceSchedules(ctx context.Context, productId string) ([]entity.PriceSchedule, error) {
+	res := make([]entity.PriceSchedule, 0)
+
+	query := `
+		SELECT` + priceScheduleColumns + `
+		FROM product_price_schedules
+		WHERE product_id = $1
+		ORDER BY starts_at DESC
+	`
+
+	if err := p.db.SelectContext(ctx, &res, query, productId); err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: GetPriceSchedules failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) CancelPriceSchedule(ctx context.Context, req *entity.PriceScheduleRequest) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CancelPriceSchedule failed")
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = p.getProductForUpdate(ctx, tx, req.ProductId); err != nil {
+		return err
+	}
+
+	var status string
+
+	query := `
+		SELECT status
+		FROM product_price_schedules
+		WHERE id = $1 AND product_id = $2
+		FOR UPDATE
+	`
+
+	if err = tx.GetContext(ctx, &status, query, req.ScheduleId, req.ProductId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Price schedule not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Price schedule not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: CancelPriceSchedule failed")
+		return err
+	}
+
+	if status != entity.PriceScheduleScheduled && status != entity.PriceScheduleActive {
+		log.Warn().Any("payload", req).Str("status", status).Msg("repository: Price schedule already finished")
+		return errmsg.NewCustomErrors(409, errmsg.WithMessage("Price schedule is already "+status))
+	}
+
+	if status == entity.PriceScheduleActive {
+		if err = p.clearSale(ctx, tx, req.ScheduleId); err != nil {
+			return err
+		}
+	}
+
+	query = `
+		UPDATE product_price_schedules
+		SET status = 'cancelled', updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, req.ScheduleId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CancelPriceSchedule failed")
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CancelPriceSchedule failed")
+		return err
+	}
+
+	return nil
+}
+
+// ApplyPriceSchedules applies every schedule due at now, each in its own
+// transaction, and returns when the next one is due.
+func (p *productRepository) ApplyPriceSchedules(ctx context.Context, now time.Time) (entity.ApplyPriceSchedulesResult, error) {
+	type dueDao struct {
+		Id     string `db:"id"`
+		Status string `db:"status"`
+	}
+
+	var (
+		res = entity.ApplyPriceSchedulesResult{}
+		due = make([]dueDao, 0)
+	)
+
+	// active sales first, so a sale ending when the next one starts is
+	// expired before the next one is activated
+	query := `
+		SELECT id, status FROM (
+			SELECT id, status, ends_at AS due_at, 0 AS seq
+			FROM product_price_schedules
+			WHERE status = 'active' AND ends_at <= $1
+			UNION ALL
+			SELECT id, status, starts_at AS due_at, 1 AS seq
+			FROM product_price_schedules
+			WHERE status = 'scheduled' AND starts_at <= $1
+		) due
+		ORDER BY seq, due_at
+	`
+
+	if err := p.db.SelectContext(ctx, &due, query, now); err != nil {
+		log.Error().Err(err).Msg("repository: ApplyPriceSchedules failed")
+		return res, err
+	}
+
+	for _, d := range due {
+		status, err := p.applyPriceSchedule(ctx, d.Id, now)
+		if err != nil {
+			return res, err
+		}
+
+		switch status {
+		case entity.PriceScheduleApplied:
+			res.Applied++
+		case entity.PriceScheduleActive:
+			res.Activated++
+		case entity.PriceScheduleExpired:
+			res.Expired++
+		}
+	}
+
+	var nextDueAt sql.NullTime
+
+	query = `
+		SELECT MIN(due_at) FROM (
+			SELECT MIN(starts_at) AS due_at FROM product_price_schedules WHERE status = 'scheduled'
+			UNION ALL
+			SELECT MIN(ends_at) AS due_at FROM product_price_schedules WHERE status = 'active'
+		) due
+	`
+
+	if err := p.db.GetContext(ctx, &nextDueAt, query); err != nil {
+		log.Error().Err(err).Msg("repository: ApplyPriceSchedules failed")
+		return res, err
+	}
+
+	if nextDueAt.Valid {
+		res.NextDueAt = &nextDueAt.Time
+	}
+
+	return res, nil
+}
+
+// applyPriceSchedule moves a single due schedule to its next status and
+// returns that status, or an empty string when another instance already
+// handled it. The product is locked before the schedule, in the same order as
+// CancelPriceSchedule.
+func (p *productRepository) applyPriceSchedule(ctx context.Context, id string, now time.Time) (string, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: applyPriceSchedule failed")
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var productId string
+
+	query := `SELECT product_id FROM product_price_schedules WHERE id = $1`
+
+	if err = tx.GetContext(ctx, &productId, query, id); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: applyPriceSchedule failed")
+		return "", err
+	}
+
+	before, err := p.getProductForUpdate(ctx, tx, productId)
+	if err != nil {
+		if customErr, ok := err.(*errmsg.CustomError); ok && customErr.Code == 404 {
+			// the product was deleted, its schedules will never apply
+			return p.finishPriceSchedule(ctx, tx, id, entity.PriceScheduleCancelled)
+		}
+		return "", err
+	}
+
+	var schedule entity.PriceSchedule
+
+	query = `SELECT` + priceScheduleColumns + `FROM product_price_schedules WHERE id = $1 FOR UPDATE`
+
+	if err = tx.QueryRowxContext(ctx, query, id).StructScan(&schedule); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: applyPriceSchedule failed")
+		return "", err
+	}
+
+	switch {
+	case schedule.Status == entity.PriceScheduleActive && !schedule.EndsAt.After(now):
+		if err = p.clearSale(ctx, tx, id); err != nil {
+			return "", err
+		}
+		return p.finishPriceSchedule(ctx, tx, id, entity.PriceScheduleExpired)
+
+	case schedule.Status != entity.PriceScheduleScheduled || schedule.StartsAt.After(now):
+		// handled by another instance, or cancelled meanwhile
+		return "", nil
+
+	case schedule.Kind == entity.PriceScheduleKindPrice:
+		var after entity.UpsertProductResponse
+
+		query = `
+			UPDATE products
+			SET price = $1, updated_at = NOW()
+			WHERE id = $2
+			RETURNING
+				id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+		`
+
+		if err = tx.QueryRowxContext(ctx, query, schedule.Price, productId).StructScan(&after); err != nil {
+			log.Error().Err(err).Str("id", id).Msg("repository: applyPriceSchedule failed")
+			return "", err
+		}
+
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   productId,
+			Before:     before,
+			After:      after,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if err = recordPriceChange(ctx, tx, productId, before.Price, after.Price, entity.PriceSourceSchedule); err != nil {
+			return "", err
+		}
+
+		if err = recordEvent(ctx, tx, entity.EventProductUpdated, productId, after); err != nil {
+			return "", err
+		}
+
+		return p.finishPriceSchedule(ctx, tx, id, entity.PriceScheduleApplied)
+
+	case !schedule.EndsAt.After(now):
+		// the whole sale window passed while the scheduler was down
+		return p.finishPriceSchedule(ctx, tx, id, entity.PriceScheduleExpired)
+
+	default:
+		query = `
+			UPDATE products
+			SET
+				sale_id = $1,
+				sale_discount_type = $2,
+				sale_discount_value = $3,
+				sale_ends_at = $4
+			WHERE id = $5
+		`
+
+		_, err = tx.ExecContext(ctx, query, id, schedule.DiscountType, schedule.DiscountValue, schedule.EndsAt, productId)
+		if err != nil {
+			log.Error().Err(err).Str("id", id).Msg("repository: applyPriceSchedule failed")
+			return "", err
+		}
+
+		if err = recordSaleStart(ctx, tx, id); err != nil {
+			return "", err
+		}
+
+		return p.finishPriceSchedule(ctx, tx, id, entity.PriceScheduleActive)
+	}
+}
+
+// finishPriceSchedule sets the status of the schedule and commits tx.
+func (p *productRepository) finishPriceSchedule(ctx context.Context, tx *sqlx.Tx, id, status string) (string, error) {
+	query := `
+		UPDATE product_price_schedules
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, status, id); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: finishPriceSchedule failed")
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository: finishPriceSchedule failed")
+		return "", err
+	}
+
+	return status, nil
+}
+
+// clearSale removes the sale terms from the product running the given sale.
+func (p *productRepository) clearSale(ctx context.Context, tx sqlx.ExecerContext, saleId string) error {
+	if err := recordSaleEnd(ctx, tx, saleId); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE products
+		SET
+			sale_id = NULL,
+			sale_discount_type = NULL,
+			sale_discount_value = NULL,
+			sale_ends_at = NULL
+		WHERE sale_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, saleId); err != nil {
+		log.Error().Err(err).Str("sale_id", saleId).Msg("repository: clearSale failed")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// lowestPrice30dSQL is the lowest price paid for the product aliased by table
+// over the last 30 days: the current effective price, and both sides of every
+// change within the window, since the old price was in effect until the
+// change. Sales are recorded when they start and end, so they are included.
+func lowestPrice30dSQL(table string) string {
+	return `LEAST(` + EffectivePriceSQL + `, (
+		SELECT MIN(LEAST(h.old_price, h.new_price))
+		FROM product_price_histories h
+		WHERE h.product_id = ` + table + `.id AND h.changed_at >= NOW() - INTERVAL '30 days'
+	))`
+}
+
+// recordPriceChange appends a base price change to the price history, in the
+// same transaction as the change. Unchanged prices are not recorded.
+func recordPriceChange(ctx context.Context, tx sqlx.ExecerContext, productId string, oldPrice, newPrice types.Money, source string) error {
+	if oldPrice == newPrice {
+		return nil
+	}
+
+	query := `
+		INSERT INTO product_price_histories (product_id, old_price, new_price, actor_id, source)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.ExecContext(ctx, query, productId, oldPrice, newPrice, auditRepo.ActorFromContext(ctx), source)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: recordPriceChange failed")
+		return err
+	}
+
+	return nil
+}
+
+// recordSaleStart appends the price drop of the product starting the given
+// sale to the price history.
+func recordSaleStart(ctx context.Context, tx sqlx.ExecerContext, saleId string) error {
+	query := `
+		INSERT INTO product_price_histories (product_id, old_price, new_price, source)
+		SELECT id, price, ` + salePriceSQL + `, $2
+		FROM products
+		WHERE sale_id = $1 AND ` + salePriceSQL + ` <> price
+	`
+
+	if _, err := tx.ExecContext(ctx, query, saleId, entity.PriceSourceSale); err != nil {
+		log.Error().Err(err).Str("sale_id", saleId).Msg("repository: recordSaleStart failed")
+		return err
+	}
+
+	return nil
+}
+
+// recordSaleEnd appends the price rise of the product ending the given sale to
+// the price history, at the time the sale stopped applying.
+func recordSaleEnd(ctx context.Context, tx sqlx.ExecerContext, saleId string) error {
+	query := `
+		INSERT INTO product_price_histories (product_id, old_price, new_price, actor_id, source, changed_at)
+		SELECT id, ` + salePriceSQL + `, price, $2, $3, LEAST(sale_ends_at, NOW())
+		FROM products
+		WHERE sale_id = $1 AND ` + salePriceSQL + ` <> price
+	`
+
+	_, err := tx.ExecContext(ctx, query, saleId, auditRepo.ActorFromContext(ctx), entity.PriceSourceSale)
+	if err != nil {
+		log.Error().Err(err).Str("sale_id", saleId).Msg("repository: recordSaleEnd failed")
+		return err
+	}
+
+	return nil
+}
+
+func (p *productRepository) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.PriceHistory
+	}
+
+	var (
+		res    entity.GetPriceHistoryResponse
+		data   = make([]dao, 0, req.Limit)
+		exists bool
+	)
+	res.Items = make([]entity.PriceHistory, 0, req.Limit)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
+
+	if err := p.db.GetContext(ctx, &exists, query, req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetPriceHistory failed")
+		return res, err
+	}
+
+	if !exists {
+		log.Warn().Any("payload", req).Msg("repository: Product not found")
+		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	query = `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			id, product_id, old_price, new_price, actor_id, source, changed_at
+		FROM product_price_histories
+		WHERE product_id = $1
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := p.db.SelectContext(ctx, &data, query, req.ProductId, req.Limit, (req.Page-1)*req.Limit); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetPriceHistory failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.PriceHistory)
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
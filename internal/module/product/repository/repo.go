@@ -1,14 +1,19 @@
 package repository
 
 import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
 	"codebase-app/internal/module/product/ports"
+	"codebase-app/pkg"
 	"codebase-app/pkg/errmsg"
 	"database/sql"
+	"time"
 
 	"codebase-app/internal/module/product/entity"
 	"context"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
@@ -27,40 +32,182 @@ func (p *productRepository) CreateProduct(ctx context.Context, req *entity.Creat
 		res entity.UpsertProductResponse
 	)
 
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO
 			products (
 				shop_id,
 				category_id,
 				name,
+				slug,
 				description,
 				image_url,
 				price,
-				stock
+				currency,
+				stock,
+				sku
 			)
-			VALUES ( $1, $2, $3, $4, $5, $6, $7 )
+			VALUES ( $1, $2, $3, $4, $5, $6, $7, COALESCE(NULLIF($9, ''), (SELECT currency FROM shops WHERE id = $1)), $8, $10 )
 			RETURNING
-				id, shop_id, name, description, image_url, price, stock, created_at, updated_at
+				id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
 	`
 
-	err := p.db.QueryRowxContext(ctx, query,
-		req.ShopId,
-		req.CategoryId,
-		req.Name,
-		req.Description,
-		req.ImageUrl,
-		req.Price,
-		req.Stock,
-	).StructScan(&res)
+	_, err = p.withProductSlug(ctx, tx, req.Name, "", func(slug string) error {
+		return tx.QueryRowxContext(ctx, query,
+			req.ShopId,
+			req.CategoryId,
+			req.Name,
+			slug,
+			req.Description,
+			req.ImageUrl,
+			req.Price,
+			req.Stock,
+			req.Currency,
+			req.Sku,
+		).StructScan(&res)
+	})
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed")
 		return res, err
 	}
 
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionCreate,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   res.Id,
+		After:      res,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = recordFeedEvent(ctx, tx, res.ShopId, res.Id, entity.FeedKindCreated); err != nil {
+		return res, err
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventProductCreated, res.Id, res); err != nil {
+		return res, err
+	}
+
+	// a product created sold out is out of stock right away, and alerts
+	if err = updateStockLevel(ctx, tx, res.Id); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CreateProduct failed")
+		return res, err
+	}
+
 	res.UserId = req.UserId
 	return res, nil
 }
 
+// getProductForUpdate locks and returns the current state of a product, used
+// as the "before" snapshot of the audit log.
+func (p *productRepository) getProductForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (entity.UpsertProductResponse, error) {
+	var res entity.UpsertProductResponse
+
+	query := `
+		SELECT
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+		FROM
+			products
+		WHERE
+			id = $1
+			AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.QueryRowxContext(ctx, query, id).StructScan(&res)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository: Product not found")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository: getProductForUpdate failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// newProductSlug returns a unique slug generated from name. Slugs currently or
+// previously used by productId are not considered taken, clashed are.
+func (p *productRepository) newProductSlug(ctx context.Context, tx *sqlx.Tx, name, productId string, clashed ...string) (string, error) {
+	var (
+		base  = pkg.Slugify(name)
+		taken = make([]string, 0)
+	)
+
+	if base == "" {
+		base = "product"
+	}
+
+	query := `
+		SELECT slug FROM products
+		WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id::TEXT <> $2
+		UNION
+		SELECT slug FROM product_slug_histories
+		WHERE (slug = $1 OR slug LIKE $1 || '-%') AND product_id::TEXT <> $2
+	`
+
+	err := tx.SelectContext(ctx, &taken, query, base, productId)
+	if err != nil {
+		log.Error().Err(err).Str("name", name).Msg("repository: newProductSlug failed")
+		return "", err
+	}
+
+	return pkg.UniqueSlug(base, append(taken, clashed...)), nil
+}
+
+// slugRetries is how many times a slug taken by a concurrent transaction is
+// replaced by the next one before giving up.
+const slugRetries = 5
+
+// withProductSlug generates a unique slug from name and writes it with write.
+// The write runs in a savepoint, so a slug committed meanwhile by a concurrent
+// transaction is retried with the next suffix instead of failing the request.
+func (p *productRepository) withProductSlug(ctx context.Context, tx *sqlx.Tx, name, productId string, write func(slug string) error) (string, error) {
+	clashed := make([]string, 0)
+
+	for {
+		slug, err := p.newProductSlug(ctx, tx, name, productId, clashed...)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err = tx.ExecContext(ctx, `SAVEPOINT product_slug`); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("repository: withProductSlug failed")
+			return "", err
+		}
+
+		err = write(slug)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT product_slug`)
+			return slug, err
+		}
+
+		pqErr, ok := err.(*pq.Error)
+		if !ok || pqErr.Code != "23505" || pqErr.Constraint != "uq_products_slug" || len(clashed) == slugRetries {
+			return "", err
+		}
+
+		log.Warn().Str("slug", slug).Msg("repository: Slug taken concurrently, retrying")
+		if _, err = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT product_slug`); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("repository: withProductSlug failed")
+			return "", err
+		}
+		clashed = append(clashed, slug)
+	}
+}
+
 func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProductsRequest) (entity.GetProductsResponse, error) {
 	type dao struct {
 		TotalData int `db:"total_data"`
@@ -74,6 +221,17 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 	res.Meta.Page = req.Page
 	res.Meta.Limit = req.Limit
 
+	distanceExpr := "CAST(NULL AS FLOAT8)"
+	if req.NearPoint != nil {
+		distanceExpr = `(
+			SELECT ST_Distance(s.origin_point, CAST(ST_SetSRID(ST_MakePoint(:near_lng, :near_lat), 4326) AS GEOGRAPHY)) / 1000
+			FROM shops s
+			WHERE s.id = products.shop_id
+		)`
+		arg["near_lng"] = req.NearPoint.Lng()
+		arg["near_lat"] = req.NearPoint.Lat()
+	}
+
 	query := `
 		SELECT
 			COUNT(*) OVER() AS total_data,
@@ -81,59 +239,32 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 			category_id,
 			shop_id,
 			name,
+			slug,
 			image_url,
-			price,
+			sku,
+			` + EffectivePriceSQL + ` AS price,
+			price AS original_price,
+			currency,
+			` + saleEndsAtSQL + ` AS sale_ends_at,
+			` + lowestPrice30dSQL("products") + ` AS lowest_price_30d,
 			stock,
+			rating_avg,
+			rating_count,
 			created_at,
-			updated_at
+			updated_at,
+			` + distanceExpr + ` AS distance_km
 		FROM
 			products
-		WHERE
-			deleted_at IS NULL
-	`
-
-	if len(req.ProductIds) > 0 {
-		var ids string
-		for i, id := range req.ProductIds {
-			ids += "'" + id + "'"
-			if i < len(req.ProductIds)-1 {
-				ids += ", "
-			}
-		}
-		query += " AND id IN (" + ids + ")"
-	}
-
-	if req.ShopId != "" {
-		query += " AND shop_id = :shop_id"
-		arg["shop_id"] = req.ShopId
-	}
+		WHERE` + productFiltersSQL(req, arg)
 
-	if req.CategoryId != "" {
-		query += " AND category_id = :category_id"
-		arg["category_id"] = req.CategoryId
-	}
-
-	if req.Name != "" {
-		query += " AND name ILIKE '%' || :name || '%'"
-		arg["name"] = req.Name
-	}
-
-	if req.PriceMinStr != "" {
-		query += " AND price >= :price_min"
-		arg["price_min"] = req.PriceMin
-	}
-
-	if req.PriceMaxStr != "" {
-		query += " AND price <= :price_max"
-		arg["price_max"] = req.PriceMax
-	}
-
-	if req.IsAvailable {
-		query += " AND stock > 0"
+	switch req.Sort {
+	case "rating":
+		query += " ORDER BY rating_avg DESC, rating_count DESC, created_at DESC"
+	default:
+		query += " ORDER BY created_at DESC"
 	}
 
 	query += `
-		ORDER BY created_at DESC
 		LIMIT :limit
 		OFFSET :offset
 	`
@@ -153,17 +284,52 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 		return res, err
 	}
 
+	shopIds := make([]string, 0, len(data))
+	for _, d := range data {
+		shopIds = append(shopIds, d.ShopId)
+	}
+
+	schedules, err := loadShopSchedules(ctx, p.db, shopIds)
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
 	for _, d := range data {
+		var (
+			isOpen      = true
+			isAvailable = d.Stock > 0
+		)
+
+		if schedule, ok := schedules[d.ShopId]; ok {
+			isOpen = schedule.IsOpen(now)
+			isAvailable = isAvailable && schedule.IsAvailable(now)
+		}
+
 		res.Items = append(res.Items, entity.Product{
 			Id:         d.Id,
 			CategoryId: d.CategoryId,
 			ShopId:     d.ShopId,
 			Name:       d.Name,
+			Slug:       d.Slug,
 			ImageUrl:   d.ImageUrl,
+			Sku:        d.Sku,
 			Price:      d.Price,
+			Currency:   d.Currency,
 			Stock:      d.Stock,
 			CreatedAt:  d.CreatedAt,
 			UpdatedAt:  d.UpdatedAt,
+			DistanceKm: d.DistanceKm,
+
+			OriginalPrice:  d.OriginalPrice,
+			SaleEndsAt:     d.SaleEndsAt,
+			LowestPrice30d: d.LowestPrice30d,
+
+			RatingAvg:   d.RatingAvg,
+			RatingCount: d.RatingCount,
+
+			IsOpen:      isOpen,
+			IsAvailable: isAvailable,
 		})
 
 		res.Meta.TotalData = d.TotalData
@@ -173,11 +339,159 @@ func (p *productRepository) GetProducts(ctx context.Context, req *entity.GetProd
 	return res, nil
 }
 
+// productFiltersSQL returns the conditions selecting the listed products that
+// match the filters of req, and sets their named arguments in arg.
+func productFiltersSQL(req *entity.GetProductsRequest, arg map[string]any) string {
+	query := `
+		deleted_at IS NULL
+		AND shop_id IN (
+			SELECT id FROM shops WHERE status = 'active' AND deleted_at IS NULL
+		)
+	`
+
+	if len(req.ProductIds) > 0 {
+		query += " AND id = ANY(CAST(:product_ids AS UUID[]))"
+		arg["product_ids"] = pq.Array(req.ProductIds)
+	}
+
+	if req.ShopId != "" {
+		query += " AND shop_id = :shop_id"
+		arg["shop_id"] = req.ShopId
+	}
+
+	if req.CategoryId != "" {
+		query += " AND category_id = :category_id"
+		arg["category_id"] = req.CategoryId
+	}
+
+	if req.Name != "" {
+		query += " AND name ILIKE '%' || :name || '%'"
+		arg["name"] = req.Name
+	}
+
+	if req.PriceMinStr != "" || req.PriceMaxStr != "" {
+		arg["currency"] = req.Currency
+	}
+
+	if req.PriceMinStr != "" {
+		query += " AND " + convertedPriceSQL + " >= :price_min"
+		arg["price_min"] = req.PriceMin
+	}
+
+	if req.PriceMaxStr != "" {
+		query += " AND " + convertedPriceSQL + " <= :price_max"
+		arg["price_max"] = req.PriceMax
+	}
+
+	if req.NearPoint != nil {
+		arg["near_lng"] = req.NearPoint.Lng()
+		arg["near_lat"] = req.NearPoint.Lat()
+		query += `
+			AND shop_id IN (
+				SELECT id
+				FROM shops
+				WHERE ST_DWithin(origin_point, CAST(ST_SetSRID(ST_MakePoint(:near_lng, :near_lat), 4326) AS GEOGRAPHY), :radius_m)
+			)
+		`
+		arg["radius_m"] = req.RadiusKm * 1000
+	}
+
+	if req.IsAvailable {
+		// products of a shop in holiday mode are unavailable when the owner asked for it
+		query += `
+			AND stock > 0
+			AND NOT EXISTS (
+				SELECT 1
+				FROM shop_holidays sh
+				JOIN shops s ON s.id = sh.shop_id
+				WHERE
+					sh.shop_id = products.shop_id
+					AND s.holiday_hides_products
+					AND sh.starts_at <= NOW()
+					AND sh.ends_at > NOW()
+			)
+		`
+	}
+
+	if req.RatingMin > 0 {
+		query += " AND rating_avg >= :rating_min"
+		arg["rating_min"] = req.RatingMin
+	}
+
+	return query
+}
+
+// renameProductSlug returns the slug of a product renamed to name. A changed
+// slug keeps the old one in the slug history so existing links redirect.
+func (p *productRepository) renameProductSlug(ctx context.Context, tx *sqlx.Tx, before entity.UpsertProductResponse, name string) (string, error) {
+	if name == before.Name {
+		return before.Slug, nil
+	}
+
+	// the slug is claimed right away, the caller writes it again with the rest
+	// of the product
+	slug, err := p.withProductSlug(ctx, tx, name, before.Id, func(slug string) error {
+		if slug == before.Slug {
+			return nil
+		}
+
+		_, err := tx.ExecContext(ctx, `UPDATE products SET slug = $1 WHERE id = $2`, slug, before.Id)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Str("id", before.Id).Msg("repository: renameProductSlug failed")
+		return "", err
+	}
+
+	if slug == before.Slug {
+		return slug, nil
+	}
+
+	query := `
+		INSERT INTO product_slug_histories (slug, product_id)
+		VALUES ($1, $2)
+		ON CONFLICT (slug) DO NOTHING
+	`
+
+	_, err = tx.ExecContext(ctx, query, before.Slug, before.Id)
+	if err != nil {
+		log.Error().Err(err).Str("id", before.Id).Msg("repository: renameProductSlug failed")
+		return "", err
+	}
+
+	query = `DELETE FROM product_slug_histories WHERE slug = $1 AND product_id = $2`
+
+	_, err = tx.ExecContext(ctx, query, slug, before.Id)
+	if err != nil {
+		log.Error().Err(err).Str("id", before.Id).Msg("repository: renameProductSlug failed")
+		return "", err
+	}
+
+	return slug, nil
+}
+
 func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error) {
 	var (
 		res entity.UpsertProductResponse
 	)
 
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	before, err := p.getProductForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return res, err
+	}
+
+	slug, err := p.renameProductSlug(ctx, tx, before, req.Name)
+	if err != nil {
+		return res, err
+	}
+
 	query := `
 		UPDATE
 			products
@@ -188,15 +502,17 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 			image_url = $4,
 			price = $5,
 			stock = $6,
+			slug = $8,
+			sku = COALESCE($9, sku),
 			updated_at = NOW()
 		WHERE
 			id = $7
 			AND deleted_at IS NULL
 		RETURNING
-			id, shop_id, name, description, image_url, price, stock, created_at, updated_at
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
 	`
 
-	err := p.db.QueryRowxContext(ctx, query,
+	err = tx.QueryRowxContext(ctx, query,
 		req.CategoryId,
 		req.Name,
 		req.Description,
@@ -204,12 +520,44 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 		req.Price,
 		req.Stock,
 		req.Id,
+		slug,
+		req.Sku,
 	).StructScan(&res)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			log.Warn().Any("payload", req).Msg("repository: Product not found")
-			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed")
+		return res, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   res.Id,
+		Before:     before,
+		After:      res,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = recordPriceChange(ctx, tx, res.Id, before.Price, res.Price, entity.PriceSourceUpdate); err != nil {
+		return res, err
+	}
+
+	if isRestock(int64(before.Stock), int64(res.Stock)) {
+		if err = recordFeedEvent(ctx, tx, res.ShopId, res.Id, entity.FeedKindRestocked); err != nil {
+			return res, err
 		}
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventProductUpdated, res.Id, res); err != nil {
+		return res, err
+	}
+
+	if err = recordStockChange(ctx, tx, res.Id, res.ShopId, int64(before.Stock), int64(res.Stock), entity.StockReasonUpdate); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProduct failed")
 		return res, err
 	}
@@ -217,7 +565,14 @@ func (p *productRepository) UpdateProduct(ctx context.Context, req *entity.Updat
 	res.UserId = req.UserId
 	return res, nil
 }
+
 func (p *productRepository) UpdateProductStock(ctx context.Context, req *entity.UpdateProductStockRequest) error {
+	type stockDao struct {
+		Id     string `db:"id" json:"-"`
+		ShopId string `db:"shop_id" json:"-"`
+		Stock  int64  `db:"stock" json:"stock"`
+	}
+
 	tx, err := p.db.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProductStock failed")
@@ -225,7 +580,32 @@ func (p *productRepository) UpdateProductStock(ctx context.Context, req *entity.
 	}
 	defer tx.Rollback()
 
+	var (
+		current = make([]stockDao, 0, len(req.Items))
+		before  = make(map[string]stockDao, len(req.Items))
+	)
+
 	query := `
+		SELECT
+			id, shop_id, stock
+		FROM
+			products
+		WHERE
+			id = ANY($1)
+		FOR UPDATE
+	`
+
+	err = tx.SelectContext(ctx, &current, query, pq.StringArray(req.ProductIds()))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProductStock failed")
+		return err
+	}
+
+	for _, c := range current {
+		before[c.Id] = c
+	}
+
+	query = `
 		UPDATE
 			products
 		SET
@@ -240,11 +620,39 @@ func (p *productRepository) UpdateProductStock(ctx context.Context, req *entity.
 			"stock": item.Stock,
 		}
 
-		_, err = tx.NamedExec(query, arg)
+		_, err = tx.NamedExecContext(ctx, query, arg)
 		if err != nil {
 			log.Error().Err(err).Any("payload", req).Msg("repository: UpdateProductStock failed")
 			return err
 		}
+
+		b, ok := before[item.ProductId]
+		if !ok {
+			continue
+		}
+
+		after := stockDao{Id: item.ProductId, ShopId: b.ShopId, Stock: item.Stock}
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   item.ProductId,
+			Before:     b,
+			After:      after,
+		})
+		if err != nil {
+			return err
+		}
+
+		if isRestock(b.Stock, after.Stock) {
+			if err = recordFeedEvent(ctx, tx, b.ShopId, item.ProductId, entity.FeedKindRestocked); err != nil {
+				return err
+			}
+		}
+
+		if err = recordStockChange(ctx, tx, item.ProductId, b.ShopId, b.Stock, after.Stock, entity.StockReasonUpdate); err != nil {
+			return err
+		}
+		before[item.ProductId] = after
 	}
 
 	err = tx.Commit()
@@ -257,6 +665,18 @@ func (p *productRepository) UpdateProductStock(ctx context.Context, req *entity.
 }
 
 func (p *productRepository) DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed")
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := p.getProductForUpdate(ctx, tx, req.ProductId)
+	if err != nil {
+		return err
+	}
+
 	query := `
 	UPDATE products
 		SET deleted_at = NOW()
@@ -264,8 +684,31 @@ func (p *productRepository) DeleteProduct(ctx context.Context, req *entity.Delet
 		id = $1
 	`
 
-	_, err := p.db.ExecContext(ctx, query, req.ProductId)
+	_, err = tx.ExecContext(ctx, query, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed")
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionDelete,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   req.ProductId,
+		Before:     before,
+	})
 	if err != nil {
+		return err
+	}
+
+	err = recordEvent(ctx, tx, entity.EventProductDeleted, req.ProductId, entity.ProductRef{
+		Id:     req.ProductId,
+		ShopId: before.ShopId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository: DeleteProduct failed")
 		return err
 	}
@@ -273,6 +716,34 @@ func (p *productRepository) DeleteProduct(ctx context.Context, req *entity.Delet
 	return nil
 }
 
+func (p *productRepository) ResolveProductSlug(ctx context.Context, slug string) (entity.SlugLookup, error) {
+	var res entity.SlugLookup
+
+	query := `
+		SELECT id, slug
+		FROM products
+		WHERE slug = $1 AND deleted_at IS NULL
+		UNION ALL
+		SELECT p.id, p.slug
+		FROM product_slug_histories h
+		JOIN products p ON p.id = h.product_id
+		WHERE h.slug = $1 AND p.deleted_at IS NULL
+		LIMIT 1
+	`
+
+	err := p.db.GetContext(ctx, &res, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("slug", slug).Msg("repository: Product not found")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Str("slug", slug).Msg("repository: ResolveProductSlug failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
 func (p *productRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
 	var (
 		isOwner bool
@@ -304,6 +775,46 @@ func (p *productRepository) IsShopOwner(ctx context.Context, userId, shopId stri
 	return isOwner, nil
 }
 
+func (p *productRepository) IsProductsOwner(ctx context.Context, userId, shopId string, productIds []string) (bool, error) {
+	var (
+		total   int
+		payload = struct {
+			UserId     string   `json:"user_id"`
+			ShopId     string   `json:"shop_id"`
+			ProductIds []string `json:"product_ids"`
+		}{userId, shopId, productIds}
+	)
+
+	// every distinct product id must belong to a shop of the user (and to the
+	// given shop when the caller is restricted to one)
+	query := `
+		SELECT
+			COUNT(DISTINCT products.id)
+		FROM
+			products
+		JOIN
+			shops ON products.shop_id = shops.id
+		WHERE
+			shops.user_id = $1
+			AND shops.deleted_at IS NULL
+			AND products.id = ANY($2)
+			AND ($3 = '' OR shops.id::text = $3)
+	`
+
+	err := p.db.GetContext(ctx, &total, query, userId, pq.StringArray(productIds), shopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", payload).Msg("repository: IsProductsOwner failed")
+		return false, err
+	}
+
+	unique := make(map[string]struct{}, len(productIds))
+	for _, id := range productIds {
+		unique[id] = struct{}{}
+	}
+
+	return total == len(unique), nil
+}
+
 func (p *productRepository) IsProductOwner(ctx context.Context, userId, productId string) (bool, error) {
 	var (
 		isOwner bool
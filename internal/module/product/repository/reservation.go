@@ -0,0 +1,329 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+type stockSnapshot struct {
+	Id     string `db:"id" json:"-"`
+	ShopId string `db:"shop_id" json:"-"`
+	Stock  int64  `db:"stock" json:"stock"`
+}
+
+func (p *productRepository) ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.ReservationResponse, error) {
+	type productDao struct {
+		Id     string `db:"id"`
+		ShopId string `db:"shop_id"`
+		Stock  int64  `db:"stock"`
+	}
+
+	var (
+		res      = entity.ReservationResponse{OrderId: req.OrderId, Items: make([]entity.Reservation, 0, len(req.Items))}
+		quantity = make(map[string]int, len(req.Items))
+		ids      = make([]string, 0, len(req.Items))
+		products = make([]productDao, 0, len(req.Items))
+	)
+
+	for _, item := range req.Items {
+		if _, ok := quantity[item.ProductId]; !ok {
+			ids = append(ids, item.ProductId)
+		}
+		quantity[item.ProductId] += item.Quantity
+	}
+	sort.Strings(ids)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// rows are locked in id order so concurrent reservations cannot deadlock
+	query := `
+		SELECT
+			p.id, p.shop_id, p.stock
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			p.id = ANY($1)
+			AND p.deleted_at IS NULL
+			AND s.deleted_at IS NULL
+			AND s.status = 'active'
+		ORDER BY p.id
+		FOR UPDATE OF p
+	`
+
+	err = tx.SelectContext(ctx, &products, query, pq.StringArray(ids))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+		return res, err
+	}
+
+	if len(products) != len(ids) {
+		log.Warn().Any("payload", req).Msg("repository: ReserveStock product not found")
+		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	shopIds := make([]string, 0, len(products))
+	for _, product := range products {
+		shopIds = append(shopIds, product.ShopId)
+	}
+
+	schedules, err := loadShopSchedules(ctx, tx, shopIds)
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	for _, product := range products {
+		if schedule, ok := schedules[product.ShopId]; ok && !schedule.IsAvailable(now) {
+			log.Warn().Any("payload", req).Str("shop_id", product.ShopId).Msg("repository: ReserveStock shop on holiday")
+			return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Toko sedang libur, produk tidak dapat dipesan"))
+		}
+
+		if product.Stock < int64(quantity[product.Id]) {
+			log.Warn().Any("payload", req).Str("product_id", product.Id).Msg("repository: ReserveStock insufficient stock")
+			return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Stok produk tidak mencukupi"))
+		}
+	}
+
+	for _, product := range products {
+		qty := quantity[product.Id]
+
+		query = `
+			UPDATE products
+			SET stock = stock - $1, updated_at = NOW()
+			WHERE id = $2
+		`
+
+		_, err = tx.ExecContext(ctx, query, qty, product.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+			return res, err
+		}
+
+		var reservation entity.Reservation
+
+		query = `
+			INSERT INTO stock_reservations (order_id, product_id, quantity)
+			VALUES ($1, $2, $3)
+			RETURNING id, order_id, product_id, quantity, status, created_at, updated_at
+		`
+
+		err = tx.QueryRowxContext(ctx, query, req.OrderId, product.Id, qty).StructScan(&reservation)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				log.Warn().Any("payload", req).Msg("repository: ReserveStock already reserved")
+				return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Order sudah memiliki reservasi untuk produk ini"))
+			}
+			log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+			return res, err
+		}
+
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   product.Id,
+			Before:     stockSnapshot{Id: product.Id, Stock: product.Stock},
+			After:      stockSnapshot{Id: product.Id, Stock: product.Stock - int64(qty)},
+		})
+		if err != nil {
+			return res, err
+		}
+
+		err = recordStockChange(ctx, tx, product.Id, product.ShopId, product.Stock, product.Stock-int64(qty), entity.StockReasonReservation)
+		if err != nil {
+			return res, err
+		}
+
+		res.Items = append(res.Items, reservation)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReserveStock failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) CommitReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	var res = entity.ReservationResponse{OrderId: req.OrderId, Items: make([]entity.Reservation, 0)}
+
+	query := `
+		UPDATE stock_reservations
+		SET status = 'committed', updated_at = NOW()
+		WHERE order_id = $1 AND status = 'open'
+		RETURNING id, order_id, product_id, quantity, status, created_at, updated_at
+	`
+
+	err := p.db.SelectContext(ctx, &res.Items, query, req.OrderId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: CommitReservation failed")
+		return res, err
+	}
+
+	if len(res.Items) == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Reservation not found")
+		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Reservation not found"))
+	}
+
+	return res, nil
+}
+
+func (p *productRepository) ReleaseReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	var res = entity.ReservationResponse{OrderId: req.OrderId, Items: make([]entity.Reservation, 0)}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseReservation failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	// the products are locked in id order before the reservations, like a
+	// stock reservation or a shop deletion does, so none of them can deadlock
+	query := `
+		SELECT id
+		FROM products
+		WHERE id IN (SELECT product_id FROM stock_reservations WHERE order_id = $1 AND status = 'open')
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	if _, err = tx.ExecContext(ctx, query, req.OrderId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseReservation failed")
+		return res, err
+	}
+
+	query = `
+		UPDATE stock_reservations
+		SET status = 'released', updated_at = NOW()
+		WHERE order_id = $1 AND status = 'open'
+		RETURNING id, order_id, product_id, quantity, status, created_at, updated_at
+	`
+
+	err = tx.SelectContext(ctx, &res.Items, query, req.OrderId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseReservation failed")
+		return res, err
+	}
+
+	if len(res.Items) == 0 {
+		log.Warn().Any("payload", req).Msg("repository: Reservation not found")
+		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Reservation not found"))
+	}
+
+	if err = restockReservations(ctx, tx, res.Items); err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: ReleaseReservation failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// ReleaseShopReservations releases the open reservations of the products of a
+// shop within tx, the same way ReleaseReservation does, and returns the ids of
+// the orders they belonged to. The products must be locked by tx already.
+func ReleaseShopReservations(ctx context.Context, tx *sqlx.Tx, shopId string) ([]string, error) {
+	var (
+		res   = make([]string, 0)
+		items = make([]entity.Reservation, 0)
+	)
+
+	query := `
+		UPDATE stock_reservations
+		SET status = 'released', updated_at = NOW()
+		WHERE
+			status = 'open'
+			AND product_id IN (SELECT id FROM products WHERE shop_id = $1)
+		RETURNING id, order_id, product_id, quantity, status, created_at, updated_at
+	`
+
+	err := tx.SelectContext(ctx, &items, query, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository: ReleaseShopReservations failed")
+		return res, err
+	}
+
+	if err = restockReservations(ctx, tx, items); err != nil {
+		return res, err
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if !seen[item.OrderId] {
+			seen[item.OrderId] = true
+			res = append(res, item.OrderId)
+		}
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// restockReservations puts the quantities of released reservations back to
+// the stock of their products, with the audit, feed and stock change records
+// of any other stock update. Products are updated in id order, the callers
+// lock them beforehand.
+func restockReservations(ctx context.Context, tx *sqlx.Tx, items []entity.Reservation) error {
+	sort.Slice(items, func(i, j int) bool { return items[i].ProductId < items[j].ProductId })
+
+	for _, item := range items {
+		var after stockSnapshot
+
+		query := `
+			UPDATE products
+			SET stock = stock + $1, updated_at = NOW()
+			WHERE id = $2
+			RETURNING id, shop_id, stock
+		`
+
+		err := tx.QueryRowxContext(ctx, query, item.Quantity, item.ProductId).StructScan(&after)
+		if err != nil {
+			log.Error().Err(err).Str("order_id", item.OrderId).Msg("repository: restockReservations failed")
+			return err
+		}
+
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   item.ProductId,
+			Before:     stockSnapshot{Id: after.Id, Stock: after.Stock - int64(item.Quantity)},
+			After:      after,
+		})
+		if err != nil {
+			return err
+		}
+
+		if isRestock(after.Stock-int64(item.Quantity), after.Stock) {
+			if err = recordFeedEvent(ctx, tx, after.ShopId, after.Id, entity.FeedKindRestocked); err != nil {
+				return err
+			}
+		}
+
+		err = recordStockChange(ctx, tx, after.Id, after.ShopId, after.Stock-int64(item.Quantity), after.Stock, entity.StockReasonRelease)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	shopEnt "codebase-app/internal/module/shop/entity"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// loadShopSchedules returns the schedule of every given shop keyed by shop id,
+// with holidays that have not ended yet, to decide whether their products are
+// open and available.
+func loadShopSchedules(ctx context.Context, db sqlx.QueryerContext, shopIds []string) (map[string]*shopEnt.Schedule, error) {
+	var (
+		schedules = make([]*shopEnt.Schedule, 0, len(shopIds))
+		hours     = make([]shopEnt.OpeningHour, 0)
+		holidays  = make([]shopEnt.Holiday, 0)
+		result    = make(map[string]*shopEnt.Schedule, len(shopIds))
+	)
+
+	if len(shopIds) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, timezone, holiday_hides_products
+		FROM shops
+		WHERE id = ANY($1)
+	`
+
+	err := sqlx.SelectContext(ctx, db, &schedules, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository: loadShopSchedules failed")
+		return nil, err
+	}
+
+	for _, s := range schedules {
+		s.OpeningHours = make([]shopEnt.OpeningHour, 0)
+		s.Holidays = make([]shopEnt.Holiday, 0)
+		result[s.ShopId] = s
+	}
+
+	query = `
+		SELECT shop_id, day_of_week, TO_CHAR(opens_at, 'HH24:MI') AS opens_at, TO_CHAR(closes_at, 'HH24:MI') AS closes_at
+		FROM shop_opening_hours
+		WHERE shop_id = ANY($1)
+		ORDER BY day_of_week ASC
+	`
+
+	err = sqlx.SelectContext(ctx, db, &hours, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository: loadShopSchedules failed")
+		return nil, err
+	}
+
+	for _, h := range hours {
+		if s, ok := result[h.ShopId]; ok {
+			s.OpeningHours = append(s.OpeningHours, h)
+		}
+	}
+
+	query = `
+		SELECT id, shop_id, starts_at, ends_at, message
+		FROM shop_holidays
+		WHERE shop_id = ANY($1) AND ends_at > NOW()
+		ORDER BY starts_at ASC
+	`
+
+	err = sqlx.SelectContext(ctx, db, &holidays, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository: loadShopSchedules failed")
+		return nil, err
+	}
+
+	for _, h := range holidays {
+		if s, ok := result[h.ShopId]; ok {
+			s.Holidays = append(s.Holidays, h)
+		}
+	}
+
+	return result, nil
+}
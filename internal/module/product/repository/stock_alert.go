@@ -0,0 +1,345 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	notifEnt "codebase-app/internal/module/notification/entity"
+	notifRepo "codebase-app/internal/module/notification/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// stockLevelRank orders the stock levels, an alert is recorded when a product
+// goes to a higher rank.
+var stockLevelRank = map[string]int{
+	entity.StockLevelOk:  0,
+	entity.StockLevelLow: 1,
+	entity.StockLevelOut: 2,
+}
+
+// updateStockLevel evaluates again the stock level of a product after its
+// stock or threshold changed. Going down a level records an alert, going up
+// rearms it, so each crossing alerts once. The product must be locked by tx.
+func updateStockLevel(ctx context.Context, tx sqlx.ExtContext, productId string) error {
+	var current struct {
+		ShopId    string `db:"shop_id"`
+		Stock     int64  `db:"stock"`
+		Threshold *int   `db:"low_stock_threshold"`
+		Level     string `db:"stock_level"`
+	}
+
+	query := `
+		SELECT shop_id, stock, low_stock_threshold, stock_level
+		FROM products
+		WHERE id = $1
+	`
+
+	if err := sqlx.GetContext(ctx, tx, &current, query, productId); err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: updateStockLevel failed")
+		return err
+	}
+
+	level := entity.StockLevel(current.Stock, current.Threshold)
+	if level == current.Level {
+		return nil
+	}
+
+	query = `UPDATE products SET stock_level = $2 WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, productId, level); err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: updateStockLevel failed")
+		return err
+	}
+
+	if stockLevelRank[level] < stockLevelRank[current.Level] {
+		return nil
+	}
+
+	query = `
+		INSERT INTO stock_alerts (product_id, shop_id, level, stock, threshold)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.ExecContext(ctx, query, productId, current.ShopId, level, current.Stock, current.Threshold)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository: updateStockLevel failed")
+		return err
+	}
+
+	return nil
+}
+
+func (p *productRepository) SetLowStockThreshold(ctx context.Context, req *entity.SetLowStockThresholdRequest) (entity.SetLowStockThresholdResponse, error) {
+	var res entity.SetLowStockThresholdResponse
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SetLowStockThreshold failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	var before *int
+
+	query := `
+		SELECT low_stock_threshold
+		FROM products
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	if err = tx.GetContext(ctx, &before, query, req.ProductId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Product not found")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: SetLowStockThreshold failed")
+		return res, err
+	}
+
+	query = `
+		UPDATE products
+		SET low_stock_threshold = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err = tx.ExecContext(ctx, query, req.ProductId, req.Threshold); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SetLowStockThreshold failed")
+		return res, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   req.ProductId,
+		Before:     map[string]any{"low_stock_threshold": before},
+		After:      map[string]any{"low_stock_threshold": req.Threshold},
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = updateStockLevel(ctx, tx, req.ProductId); err != nil {
+		return res, err
+	}
+
+	query = `SELECT id, stock, low_stock_threshold, stock_level FROM products WHERE id = $1`
+
+	if err = tx.GetContext(ctx, &res, query, req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SetLowStockThreshold failed")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: SetLowStockThreshold failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetLowStockProducts lists the live products of the live shops of the user
+// that are low or out of stock, the lowest stock first.
+func (p *productRepository) GetLowStockProducts(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.LowStockProduct
+	}
+
+	var (
+		res  entity.GetLowStockResponse
+		data = make([]dao, 0, req.Limit)
+		arg  = map[string]any{
+			"user_id": req.UserId,
+			"limit":   req.Limit,
+			"offset":  (req.Page - 1) * req.Limit,
+		}
+	)
+	res.Items = make([]entity.LowStockProduct, 0, req.Limit)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			p.id, p.shop_id, p.name, p.sku, p.image_url, p.stock, p.low_stock_threshold, p.stock_level, p.updated_at
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			s.user_id = :user_id
+			AND s.deleted_at IS NULL
+			AND p.deleted_at IS NULL
+			AND p.stock_level <> 'ok'
+	`
+
+	if req.ShopId != "" {
+		query += " AND p.shop_id = :shop_id"
+		arg["shop_id"] = req.ShopId
+	}
+
+	if req.Level != "" {
+		query += " AND p.stock_level = :level"
+		arg["level"] = req.Level
+	}
+
+	query += " ORDER BY p.stock, p.id LIMIT :limit OFFSET :offset"
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetLowStockProducts failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	if err = nstmt.SelectContext(ctx, &data, arg); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetLowStockProducts failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.LowStockProduct)
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// NotifyStockAlerts records up to limit alerts not sent yet as stock.low and
+// stock.out events of the outbox, emailed and sent to the webhooks once
+// committed, and as notifications of the owner of the shop, then marks them
+// sent. The alerts of products or shops deleted meanwhile are marked without
+// being sent.
+func (p *productRepository) NotifyStockAlerts(ctx context.Context, limit int) (int, error) {
+	type dao struct {
+		Live       bool `db:"live"`
+		OwnerFound bool `db:"owner_found"`
+		entity.StockAlert
+	}
+
+	var (
+		data = make([]dao, 0, limit)
+		ids  = make([]int64, 0, limit)
+		sent int
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: NotifyStockAlerts failed")
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			a.id, a.product_id, a.shop_id, a.level, a.stock, a.threshold, a.created_at,
+			p.name AS product_name,
+			s.name AS shop_name,
+			s.user_id AS owner_id,
+			u.id IS NOT NULL AS owner_found,
+			p.deleted_at IS NULL AND s.deleted_at IS NULL AS live
+		FROM
+			stock_alerts a
+		JOIN products p ON p.id = a.product_id
+		JOIN shops s ON s.id = a.shop_id
+		LEFT JOIN users u ON u.id = s.user_id AND u.deleted_at IS NULL
+		WHERE
+			a.notified_at IS NULL
+		ORDER BY a.id
+		LIMIT $1
+		FOR UPDATE OF a SKIP LOCKED
+	`
+
+	if err = tx.SelectContext(ctx, &data, query, limit); err != nil {
+		log.Error().Err(err).Msg("repository: NotifyStockAlerts failed")
+		return 0, err
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	for i := range data {
+		alert := &data[i].StockAlert
+		ids = append(ids, alert.Id)
+
+		if !data[i].Live {
+			continue
+		}
+
+		if err = recordEvent(ctx, tx, alert.EventType(), alert.ProductId, alert); err != nil {
+			return 0, err
+		}
+
+		// without the account of the owner, only the contact of the shop
+		// gets the alert, by email
+		if data[i].OwnerFound {
+			title, body := alert.Text()
+			if err = notifRepo.Record(ctx, tx, notifEnt.New(alert.OwnerId, alert.EventType(), title, body, alert)); err != nil {
+				return 0, err
+			}
+		}
+
+		sent++
+	}
+
+	query = `UPDATE stock_alerts SET notified_at = NOW() WHERE id = ANY($1)`
+
+	if _, err = tx.ExecContext(ctx, query, pq.Int64Array(ids)); err != nil {
+		log.Error().Err(err).Msg("repository: NotifyStockAlerts failed")
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository: NotifyStockAlerts failed")
+		return 0, err
+	}
+
+	return sent, nil
+}
+
+// GetStockAlertEmail returns where an alert is emailed: the owner of its
+// shop, or the contact of the shop once the account of the owner is gone. It
+// is empty when there is neither or the alert was already emailed.
+func (p *productRepository) GetStockAlertEmail(ctx context.Context, alertId int64) (string, error) {
+	var email string
+
+	query := `
+		SELECT COALESCE(u.email, s.contact_email, '')
+		FROM stock_alerts a
+		JOIN shops s ON s.id = a.shop_id
+		LEFT JOIN users u ON u.id = s.user_id AND u.deleted_at IS NULL
+		WHERE a.id = $1 AND a.emailed_at IS NULL
+	`
+
+	if err := p.db.GetContext(ctx, &email, query, alertId); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		log.Error().Err(err).Int64("alert_id", alertId).Msg("repository: GetStockAlertEmail failed")
+		return "", err
+	}
+
+	return email, nil
+}
+
+// MarkStockAlertEmailed keeps an alert from being emailed again when its
+// event is relayed again.
+func (p *productRepository) MarkStockAlertEmailed(ctx context.Context, alertId int64) error {
+	query := `UPDATE stock_alerts SET emailed_at = NOW() WHERE id = $1`
+
+	if _, err := p.db.ExecContext(ctx, query, alertId); err != nil {
+		log.Error().Err(err).Int64("alert_id", alertId).Msg("repository: MarkStockAlertEmailed failed")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// productDependents are the tables referencing products without ON DELETE
+// CASCADE, their rows are removed before the products on a purge.
+var productDependents = []string{
+	"product_reviews",
+	"product_questions",
+	"stock_reservations",
+	"product_feed_events",
+	"product_price_schedules",
+	"product_slug_histories",
+	"stock_alerts",
+}
+
+// GetProductTrash lists the products of the live shops of the user deleted
+// since the given time, the most recently deleted first.
+func (p *productRepository) GetProductTrash(ctx context.Context, req *entity.GetProductTrashRequest, since time.Time) (entity.GetProductTrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashedProduct
+	}
+
+	var (
+		res  entity.GetProductTrashResponse
+		data = make([]dao, 0, req.Limit)
+		arg  = map[string]any{
+			"user_id": req.UserId,
+			"since":   since,
+			"limit":   req.Limit,
+			"offset":  (req.Page - 1) * req.Limit,
+		}
+	)
+	res.Items = make([]entity.TrashedProduct, 0, req.Limit)
+	res.Meta.Page = req.Page
+	res.Meta.Limit = req.Limit
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			p.id, p.shop_id, p.name, p.slug, p.sku, p.image_url, p.price, p.currency, p.stock, p.deleted_at
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			s.user_id = :user_id
+			AND s.deleted_at IS NULL
+			AND p.deleted_at >= :since
+	`
+
+	if req.ShopId != "" {
+		query += " AND p.shop_id = :shop_id"
+		arg["shop_id"] = req.ShopId
+	}
+
+	query += " ORDER BY p.deleted_at DESC, p.id LIMIT :limit OFFSET :offset"
+
+	nstmt, err := p.db.PrepareNamedContext(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductTrash failed")
+		return res, err
+	}
+	defer nstmt.Close()
+
+	if err = nstmt.SelectContext(ctx, &data, arg); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: GetProductTrash failed")
+		return res, err
+	}
+
+	for _, d := range data {
+		res.Items = append(res.Items, d.TrashedProduct)
+		res.Meta.TotalData = d.TotalData
+	}
+
+	res.Meta.CountTotalPage()
+	return res, nil
+}
+
+// RestoreProduct brings back a product deleted since the given time. The shop
+// of the product must be live and its sku must not have been taken meanwhile.
+func (p *productRepository) RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest, since time.Time) (entity.UpsertProductResponse, error) {
+	var (
+		res     entity.UpsertProductResponse
+		trashed struct {
+			DeletedAt     time.Time  `db:"deleted_at"`
+			ShopDeletedAt *time.Time `db:"shop_deleted_at"`
+			ShopId        string     `db:"shop_id"`
+			Sku           *string    `db:"sku"`
+		}
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RestoreProduct failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			p.deleted_at, p.shop_id, p.sku, s.deleted_at AS shop_deleted_at
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			p.id = $1
+			AND p.deleted_at IS NOT NULL
+		FOR UPDATE OF p
+	`
+
+	if err = tx.GetContext(ctx, &trashed, query, req.ProductId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository: Product not found in the trash")
+			return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found in the trash"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository: RestoreProduct failed")
+		return res, err
+	}
+
+	switch {
+	case trashed.DeletedAt.Before(since):
+		log.Warn().Any("payload", req).Time("deleted_at", trashed.DeletedAt).Msg("repository: Product retention is over")
+		return res, errmsg.NewCustomErrors(410, errmsg.WithMessage("Product was deleted too long ago to be restored"))
+	case trashed.ShopDeletedAt != nil:
+		log.Warn().Any("payload", req).Msg("repository: Shop of the product is deleted")
+		return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Shop of the product is deleted, restore the shop first"))
+	}
+
+	if trashed.Sku != nil {
+		var taken bool
+
+		query = `SELECT EXISTS (SELECT 1 FROM products WHERE shop_id = $1 AND sku = $2 AND deleted_at IS NULL)`
+
+		if err = tx.GetContext(ctx, &taken, query, trashed.ShopId, *trashed.Sku); err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository: RestoreProduct failed")
+			return res, err
+		}
+
+		if taken {
+			log.Warn().Any("payload", req).Str("sku", *trashed.Sku).Msg("repository: Sku is taken")
+			return res, errmsg.NewCustomErrors(409, errmsg.WithErrors("sku", "sku is already used by another product of the shop."))
+		}
+	}
+
+	query = `
+		UPDATE products
+			SET deleted_at = NULL, updated_at = NOW()
+		WHERE
+			id = $1
+		RETURNING
+			id, shop_id, category_id, name, slug, description, image_url, sku, price, currency, stock, created_at, updated_at
+	`
+
+	if err = tx.QueryRowxContext(ctx, query, req.ProductId).StructScan(&res); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RestoreProduct failed")
+		return res, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionRestore,
+		EntityType: auditEnt.EntityProduct,
+		EntityId:   res.Id,
+		After:      res,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	err = recordEvent(ctx, tx, entity.EventProductRestored, res.Id, entity.ProductRef{
+		Id:     res.Id,
+		ShopId: res.ShopId,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository: RestoreProduct failed")
+		return res, err
+	}
+
+	return res, nil
+}
+
+// PurgeProducts hard deletes up to limit products deleted before the given
+// time, or whose shop was, with the rows depending on them. The rating of the
+// live shops is computed again as the reviews of the products are gone too.
+func (p *productRepository) PurgeProducts(ctx context.Context, before time.Time, limit int) (entity.PurgeResult, error) {
+	var (
+		res      = entity.PurgeResult{Images: make([]string, 0)}
+		products = make([]struct {
+			Id       string  `db:"id"`
+			ShopId   string  `db:"shop_id"`
+			ImageUrl *string `db:"image_url"`
+		}, 0, limit)
+	)
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			p.id, p.shop_id, p.image_url
+		FROM
+			products p
+		JOIN
+			shops s ON s.id = p.shop_id
+		WHERE
+			p.deleted_at < $1
+			OR s.deleted_at < $1
+		ORDER BY p.id
+		LIMIT $2
+		FOR UPDATE OF p SKIP LOCKED
+	`
+
+	if err = tx.SelectContext(ctx, &products, query, before, limit); err != nil {
+		log.Error().Err(err).Time("before", before).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+
+	if len(products) == 0 {
+		return res, nil
+	}
+
+	var (
+		ids     = make([]string, 0, len(products))
+		shopIds = make([]string, 0, len(products))
+		photos  = make([]string, 0)
+	)
+
+	for _, product := range products {
+		ids = append(ids, product.Id)
+		shopIds = append(shopIds, product.ShopId)
+		if product.ImageUrl != nil && *product.ImageUrl != "" {
+			res.Images = append(res.Images, *product.ImageUrl)
+		}
+	}
+
+	query = `SELECT UNNEST(photo_urls) FROM product_reviews WHERE product_id = ANY(CAST($1 AS UUID[]))`
+
+	if err = tx.SelectContext(ctx, &photos, query, pq.Array(ids)); err != nil {
+		log.Error().Err(err).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+	res.Images = append(res.Images, photos...)
+
+	for _, table := range productDependents {
+		query = `DELETE FROM ` + table + ` WHERE product_id = ANY(CAST($1 AS UUID[]))`
+
+		if _, err = tx.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+			log.Error().Err(err).Str("table", table).Msg("repository: PurgeProducts failed")
+			return res, err
+		}
+	}
+
+	query = `DELETE FROM products WHERE id = ANY(CAST($1 AS UUID[]))`
+
+	if _, err = tx.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+		log.Error().Err(err).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+
+	query = `
+		UPDATE shops s
+			SET (rating_avg, rating_count) = (
+				SELECT
+					COALESCE(ROUND(AVG(r.rating), 2), 0), COUNT(*)
+				FROM product_reviews r
+				JOIN products p ON p.id = r.product_id
+				WHERE p.shop_id = s.id AND r.status = 'published'
+			)
+		WHERE
+			s.id = ANY(CAST($1 AS UUID[]))
+			AND s.deleted_at IS NULL
+	`
+
+	if _, err = tx.ExecContext(ctx, query, pq.Array(shopIds)); err != nil {
+		log.Error().Err(err).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository: PurgeProducts failed")
+		return res, err
+	}
+
+	res.Products = len(products)
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// BulkUpdateProducts verifies that the user owns every listed product, or the
+// filtered shop, before applying the action.
+func (p *productService) BulkUpdateProducts(ctx context.Context, req *entity.BulkProductsRequest) (entity.BulkProductsResponse, error) {
+	var res entity.BulkProductsResponse
+
+	if len(req.ProductIds) > 0 {
+		isProductsOwner, err := p.repo.IsProductsOwner(ctx, req.UserId, "", req.ProductIds)
+		if err != nil {
+			return res, err
+		}
+
+		if !isProductsOwner {
+			log.Warn().Any("payload", req).Msg("service: User is not owner of all products")
+			return res, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+		}
+	}
+
+	if req.Filter != nil {
+		isShopOwner, err := p.repo.IsShopOwner(ctx, req.UserId, req.Filter.ShopId)
+		if err != nil {
+			return res, err
+		}
+
+		if !isShopOwner {
+			log.Warn().Any("payload", req).Msg("service: User is not shop owner")
+			return res, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+		}
+	}
+
+	return p.repo.BulkUpdateProducts(ctx, req)
+}
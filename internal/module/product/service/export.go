@@ -0,0 +1,65 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"io"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExportProducts checks that the user may export the requested products and
+// returns the function streaming them. Only admins export the full catalog.
+func (p *productService) ExportProducts(ctx context.Context, req *entity.ExportProductsRequest) (entity.ExportFunc, error) {
+	if req.Role != "admin" {
+		if req.ShopId == "" {
+			log.Warn().Str("user_id", req.UserId).Msg("service: Only admins can export the full catalog")
+			return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("Only admins can export the full catalog"))
+		}
+
+		isShopOwner, err := p.repo.IsShopOwner(ctx, req.UserId, req.ShopId)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isShopOwner {
+			log.Warn().Any("payload", req).Msg("service: User is not shop owner")
+			return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+		}
+	}
+
+	var (
+		baseURL = config.Envs.Frontend.ClientBaseURL
+		title   = config.Envs.App.Name + " catalog"
+		filter  = req.GetProductsRequest
+		format  = req.Format
+	)
+
+	return func(ctx context.Context, w io.Writer) error {
+		ew, err := entity.NewExportWriter(format, w, title, baseURL)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = p.repo.ExportProducts(ctx, &filter, func(product *entity.ExportProduct) error {
+			product.Link = baseURL + "/products/" + product.Slug
+			count++
+			return ew.Write(product)
+		})
+		if err != nil {
+			log.Error().Err(err).Str("format", format).Int("exported", count).Msg("service: Export failed")
+			return err
+		}
+
+		if err = ew.Close(); err != nil {
+			log.Error().Err(err).Str("format", format).Int("exported", count).Msg("service: Export failed")
+			return err
+		}
+
+		log.Info().Str("format", format).Str("shop_id", filter.ShopId).Int("exported", count).Msg("service: Export completed")
+		return nil
+	}, nil
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/product/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ImportScheduler runs pending import jobs. It is woken whenever this instance
+// creates one.
+var ImportScheduler = scheduler.NewTimer("product_imports", 30*time.Second)
+
+const (
+	// importProgressEvery is how many rows are validated between progress reports.
+	importProgressEvery = 100
+
+	// importHeartbeatEvery is how often a running job tells it is alive, well
+	// under the 10 minutes after which a silent job is claimed again.
+	importHeartbeatEvery = time.Minute
+)
+
+type importService struct {
+	repo      ports.ImportRepository
+	validator ports.Validator
+}
+
+func NewImportService(r ports.ImportRepository, v ports.Validator) ports.ImportService {
+	return &importService{
+		repo:      r,
+		validator: v,
+	}
+}
+
+func (s *importService) CreateImportJob(ctx context.Context, req *entity.CreateImportJobRequest) (entity.ImportJob, error) {
+	var res entity.ImportJob
+
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return res, err
+	}
+
+	res, err := s.repo.CreateImportJob(ctx, req, entity.ImportPending)
+	if err != nil {
+		return res, err
+	}
+
+	ImportScheduler.Wake()
+	return res, nil
+}
+
+func (s *importService) GetImportJob(ctx context.Context, req *entity.ImportJobRequest) (entity.ImportJob, error) {
+	res, err := s.repo.GetImportJob(ctx, req.JobId)
+	if err != nil {
+		return res, err
+	}
+
+	if err = s.checkShopOwner(ctx, req.UserId, res.ShopId); err != nil {
+		return entity.ImportJob{}, err
+	}
+
+	return res, nil
+}
+
+func (s *importService) GetImportReport(ctx context.Context, req *entity.ImportJobRequest) ([]entity.ImportRowError, error) {
+	if _, err := s.GetImportJob(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetImportErrors(ctx, req.JobId)
+}
+
+// Import runs an import right away instead of leaving it to the worker.
+func (s *importService) Import(ctx context.Context, req *entity.CreateImportJobRequest) (entity.ImportJob, error) {
+	var res entity.ImportJob
+
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return res, err
+	}
+
+	job, err := s.repo.CreateImportJob(ctx, req, entity.ImportValidating)
+	if err != nil {
+		return res, err
+	}
+
+	if err = s.processImportJob(ctx, &job); err != nil {
+		if errors.Is(err, entity.ErrImportJobLost) {
+			return res, errmsg.NewCustomErrors(409, errmsg.WithMessage("Import job was claimed by another worker"))
+		}
+		return res, err
+	}
+
+	return s.repo.GetImportJob(ctx, job.Id)
+}
+
+// RunImportJobs processes import jobs one at a time until none is pending and
+// returns how many were processed.
+func (s *importService) RunImportJobs(ctx context.Context) (int, error) {
+	var n int
+
+	for ctx.Err() == nil {
+		job, err := s.repo.ClaimImportJob(ctx)
+		if err != nil {
+			return n, err
+		}
+
+		if job == nil {
+			break
+		}
+
+		err = s.processImportJob(ctx, job)
+		if errors.Is(err, entity.ErrImportJobLost) {
+			log.Warn().Str("job_id", job.Id).Msg("service: Import job was claimed by another worker")
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// processImportJob validates every row of a claimed job and imports the rows
+// only when all of them are valid. It returns an error only when the outcome
+// could not be saved, entity.ErrImportJobLost when the job was claimed again.
+func (s *importService) processImportJob(ctx context.Context, job *entity.ImportJob) error {
+	stop := s.heartbeat(ctx, job)
+	defer stop()
+
+	rows, errs, err := entity.ParseImportRows(job.Format, job.Payload)
+	if err != nil {
+		log.Warn().Err(err).Str("job_id", job.Id).Msg("service: Import file is unreadable")
+		return s.failImportJob(ctx, job, nil, err)
+	}
+
+	job.TotalRows = len(rows)
+	errs = append(errs, s.validateImportRows(ctx, job, rows, errs)...)
+
+	if len(errs) == 0 {
+		errs, err = s.checkImportCategories(ctx, rows)
+		if err != nil {
+			return s.failImportJob(ctx, job, nil, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Row < errs[j].Row })
+		return s.failImportJob(ctx, job, errs, nil)
+	}
+
+	job.Status = entity.ImportImporting
+	if err = s.repo.UpdateImportProgress(ctx, job); err != nil {
+		return err
+	}
+
+	created, updated, err := s.repo.ImportProducts(ctx, job, rows, func(imported int) {
+		job.ImportedRows = imported
+		_ = s.repo.UpdateImportProgress(ctx, job)
+	})
+	if errors.Is(err, entity.ErrImportJobLost) {
+		return err
+	}
+	if err != nil {
+		return s.failImportJob(ctx, job, nil, err)
+	}
+
+	log.Info().Str("job_id", job.Id).Int("created", created).Int("updated", updated).Msg("service: Import completed")
+	return s.repo.FinishImportJob(ctx, job, &entity.ImportResult{
+		Status:       entity.ImportCompleted,
+		CreatedCount: created,
+		UpdatedCount: updated,
+	})
+}
+
+// validateImportRows validates the rows that could be read, reporting the
+// progress as it goes, and returns their errors.
+func (s *importService) validateImportRows(ctx context.Context, job *entity.ImportJob, rows []entity.ImportRow, parseErrs []entity.ImportRowError) []entity.ImportRowError {
+	var (
+		errs       = make([]entity.ImportRowError, 0)
+		unreadable = make(map[int]bool, len(parseErrs))
+		skus       = make(map[string]int, len(rows))
+	)
+
+	for _, e := range parseErrs {
+		unreadable[e.Row] = true
+	}
+
+	for i := range rows {
+		row := &rows[i]
+
+		if !unreadable[row.Row] {
+			if err := s.validator.Validate(row); err != nil {
+				errs = append(errs, importRowErrors(row, err)...)
+			}
+		}
+
+		if first, ok := skus[row.Sku]; ok && row.Sku != "" {
+			errs = append(errs, entity.ImportRowError{
+				Row:     row.Row,
+				Sku:     row.Sku,
+				Field:   "sku",
+				Message: "sku is already used by row " + strconv.Itoa(first) + ".",
+			})
+		} else {
+			skus[row.Sku] = row.Row
+		}
+
+		job.ValidatedRows = i + 1
+		if job.ValidatedRows%importProgressEvery == 0 {
+			_ = s.repo.UpdateImportProgress(ctx, job)
+		}
+	}
+
+	_ = s.repo.UpdateImportProgress(ctx, job)
+	return errs
+}
+
+// checkImportCategories reports the rows whose category does not exist.
+func (s *importService) checkImportCategories(ctx context.Context, rows []entity.ImportRow) ([]entity.ImportRowError, error) {
+	var (
+		errs = make([]entity.ImportRowError, 0)
+		ids  = make([]string, 0)
+		seen = make(map[string]bool)
+	)
+
+	for _, row := range rows {
+		if !seen[row.CategoryId] {
+			seen[row.CategoryId] = true
+			ids = append(ids, row.CategoryId)
+		}
+	}
+
+	missing, err := s.repo.MissingCategoryIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	isMissing := make(map[string]bool, len(missing))
+	for _, id := range missing {
+		isMissing[id] = true
+	}
+
+	for _, row := range rows {
+		if isMissing[row.CategoryId] {
+			errs = append(errs, entity.ImportRowError{Row: row.Row, Sku: row.Sku, Field: "category_id", Message: "category_id does not exist."})
+		}
+	}
+
+	return errs, nil
+}
+
+// failImportJob finishes a job without importing anything, either because of
+// row errors or of err.
+func (s *importService) failImportJob(ctx context.Context, job *entity.ImportJob, errs []entity.ImportRowError, err error) error {
+	res := &entity.ImportResult{
+		Status: entity.ImportFailed,
+		Errors: errs,
+	}
+
+	if err != nil {
+		msg := err.Error()
+		if errCustom, ok := err.(*errmsg.CustomError); ok {
+			msg = errCustom.Msg
+		}
+		res.Error = &msg
+	}
+
+	log.Warn().Str("job_id", job.Id).Int("row_errors", len(errs)).Any("error", res.Error).Msg("service: Import failed")
+	return s.repo.FinishImportJob(ctx, job, res)
+}
+
+// heartbeat keeps the job alive in the background until the returned func is
+// called or the lease is lost. The progress reports alone are not enough, the
+// import of a large file can run long without any.
+func (s *importService) heartbeat(ctx context.Context, job *entity.ImportJob) (stop func()) {
+	var (
+		done   = make(chan struct{})
+		ticker = time.NewTicker(importHeartbeatEvery)
+		hb     = *job
+	)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.repo.HeartbeatImportJob(ctx, &hb); errors.Is(err, entity.ErrImportJobLost) {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func (s *importService) checkShopOwner(ctx context.Context, userId, shopId string) error {
+	isShopOwner, err := s.repo.IsShopOwner(ctx, userId, shopId)
+	if err != nil {
+		return err
+	}
+
+	if !isShopOwner {
+		log.Warn().Str("user_id", userId).Str("shop_id", shopId).Msg("service: User is not shop owner")
+		return errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return nil
+}
+
+// importRowErrors maps the validation errors of a row to report entries, with
+// the same messages as the API.
+func importRowErrors(row *entity.ImportRow, err error) []entity.ImportRowError {
+	var errs = make([]entity.ImportRowError, 0)
+
+	_, res := errmsg.Errors(err, row)
+	fields, ok := res.(map[string][]string)
+	if !ok || len(fields) == 0 {
+		return append(errs, entity.ImportRowError{Row: row.Row, Sku: row.Sku, Field: "row", Message: err.Error()})
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		for _, msg := range fields[field] {
+			errs = append(errs, entity.ImportRowError{Row: row.Row, Sku: row.Sku, Field: field, Message: msg})
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"codebase-app/internal/module/product/entity"
+	mockPort "codebase-app/mock/module/product/ports"
+	"codebase-app/pkg/validator"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const importCategoryId = "6f1c1d7e-5a49-4a57-9a8c-0d0f7a6a1b11"
+
+type ImportServiceList struct {
+	suite.Suite
+	mockImportRepo *mockPort.MockImportRepo
+	service        *importService
+
+	result *entity.ImportResult
+}
+
+func (suite *ImportServiceList) SetupTest() {
+	suite.mockImportRepo = mockPort.NewMockImportRepo()
+	suite.service = &importService{repo: suite.mockImportRepo, validator: validator.NewValidator()}
+	suite.result = nil
+
+	suite.mockImportRepo.On("UpdateImportProgress", mock.Anything, mock.Anything).Return(nil).Maybe()
+	suite.mockImportRepo.On("HeartbeatImportJob", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+// onFinish records the result the job is finished with.
+func (suite *ImportServiceList) onFinish(err error) {
+	suite.mockImportRepo.On("FinishImportJob", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		suite.result = args.Get(2).(*entity.ImportResult)
+	}).Return(err)
+}
+
+// Testing processImportJob
+
+func (suite *ImportServiceList) TestProcessImportJob_Completed() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte(
+		"sku,category_id,name,price,stock\n" +
+			"A-1," + importCategoryId + ",Kopi Arabika,125000.50,10\n" +
+			"A-2," + importCategoryId + ",Kopi Robusta,99000,0\n",
+	)}
+
+	var imported []entity.ImportRow
+	suite.mockImportRepo.On("MissingCategoryIds", ctx, []string{importCategoryId}).Return([]string{}, nil)
+	suite.mockImportRepo.On("ImportProducts", ctx, job, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		imported = args.Get(2).([]entity.ImportRow)
+		args.Get(3).(func(int))(len(imported))
+	}).Return(1, 1, nil)
+	suite.onFinish(nil)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Nil(err)
+	suite.Equal(entity.ImportCompleted, suite.result.Status)
+	suite.Equal(1, suite.result.CreatedCount)
+	suite.Equal(1, suite.result.UpdatedCount)
+	suite.Len(imported, 2)
+	suite.Equal("125000.5", imported[0].Price.String())
+	suite.Equal(2, job.ImportedRows)
+}
+
+func (suite *ImportServiceList) TestProcessImportJob_RowErrorsImportNothing() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatJSONL, Payload: []byte(
+		`{"sku":"A-1","category_id":"` + importCategoryId + `","name":"Kopi Arabika","price":125000,"stock":10}` + "\n" +
+			`{"sku":"A-1","category_id":"` + importCategoryId + `","name":"Kopi Robusta","price":99000}` + "\n" +
+			`{"sku":"A-3","category_id":"not-a-uuid","name":"Te","price":0}` + "\n" +
+			`{"sku":"A-4","price":"abc"}` + "\n",
+	)}
+
+	suite.onFinish(nil)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Nil(err)
+	suite.Equal(entity.ImportFailed, suite.result.Status)
+	suite.Equal(4, job.ValidatedRows)
+	suite.mockImportRepo.AssertNotCalled(suite.T(), "ImportProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	fields := make(map[int][]string)
+	for _, e := range suite.result.Errors {
+		fields[e.Row] = append(fields[e.Row], e.Field)
+	}
+
+	suite.NotContains(fields, 1)
+	suite.Equal([]string{"sku"}, fields[2])
+	suite.ElementsMatch([]string{"category_id", "name", "price"}, fields[3])
+	suite.Equal([]string{"row"}, fields[4])
+}
+
+func (suite *ImportServiceList) TestProcessImportJob_MissingCategory() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte(
+		"sku,category_id,name,price\nA-1," + importCategoryId + ",Kopi Arabika,125000\n",
+	)}
+
+	suite.mockImportRepo.On("MissingCategoryIds", ctx, []string{importCategoryId}).Return([]string{importCategoryId}, nil)
+	suite.onFinish(nil)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Nil(err)
+	suite.Equal("category_id", suite.result.Errors[0].Field)
+	suite.mockImportRepo.AssertNotCalled(suite.T(), "ImportProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ImportServiceList) TestProcessImportJob_WriteFailure() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte(
+		"sku,category_id,name,price\nA-1," + importCategoryId + ",Kopi Arabika,125000\n",
+	)}
+
+	suite.mockImportRepo.On("MissingCategoryIds", ctx, []string{importCategoryId}).Return([]string{}, nil)
+	suite.mockImportRepo.On("ImportProducts", ctx, job, mock.Anything, mock.Anything).Return(0, 0, errors.New("connection reset"))
+	suite.onFinish(nil)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Nil(err)
+	suite.Equal(entity.ImportFailed, suite.result.Status)
+	suite.Equal("connection reset", *suite.result.Error)
+}
+
+func (suite *ImportServiceList) TestProcessImportJob_LeaseLost() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte(
+		"sku,category_id,name,price\nA-1," + importCategoryId + ",Kopi Arabika,125000\n",
+	)}
+
+	suite.mockImportRepo.On("MissingCategoryIds", ctx, []string{importCategoryId}).Return([]string{}, nil)
+	suite.mockImportRepo.On("ImportProducts", ctx, job, mock.Anything, mock.Anything).Return(0, 0, entity.ErrImportJobLost)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Equal(entity.ErrImportJobLost, err)
+	suite.mockImportRepo.AssertNotCalled(suite.T(), "FinishImportJob", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ImportServiceList) TestProcessImportJob_BadHeader() {
+	ctx := context.Background()
+	job := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte("name,price\nKopi,1000\n")}
+
+	suite.onFinish(nil)
+	err := suite.service.processImportJob(ctx, job)
+
+	suite.Nil(err)
+	suite.Equal(entity.ImportFailed, suite.result.Status)
+	suite.Contains(*suite.result.Error, "sku")
+}
+
+// Testing RunImportJobs
+
+func (suite *ImportServiceList) TestRunImportJobs_SkipsLostJob() {
+	ctx := context.Background()
+	lost := &entity.ImportJob{Id: "1", Format: entity.ImportFormatCSV, Payload: []byte("name,price\nKopi,1000\n")}
+
+	suite.mockImportRepo.On("ClaimImportJob", ctx).Return(lost, nil).Once()
+	suite.mockImportRepo.On("ClaimImportJob", ctx).Return(nil, nil).Once()
+	suite.onFinish(entity.ErrImportJobLost)
+	n, err := suite.service.RunImportJobs(ctx)
+
+	suite.Nil(err)
+	suite.Equal(0, n)
+	suite.mockImportRepo.AssertExpectations(suite.T())
+}
+
+func TestImportService(t *testing.T) {
+	suite.Run(t, new(ImportServiceList))
+}
@@ -4,11 +4,18 @@ import (
 	"codebase-app/internal/module/product/entity"
 	"codebase-app/internal/module/product/ports"
 	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/scheduler"
+	"codebase-app/pkg/types"
 	"context"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+// PriceScheduler applies price schedules when they are due. It is woken
+// whenever a schedule of this instance changes.
+var PriceScheduler = scheduler.NewTimer("price_schedules", time.Minute)
+
 type productService struct {
 	repo ports.ProductRepository
 }
@@ -51,9 +58,56 @@ func (p *productService) GetProducts(ctx context.Context, req *entity.GetProduct
 		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Products not found"))
 	}
 
+	if err = p.setDisplayPrices(ctx, res.Items, req.Currency); err != nil {
+		return res, err
+	}
+
 	return res, nil
 }
 
+// setDisplayPrices converts the prices of products into currency. A product
+// whose currency has no known rate keeps a nil display price.
+func (p *productService) setDisplayPrices(ctx context.Context, products []entity.Product, currency string) error {
+	if currency == "" || len(products) == 0 {
+		return nil
+	}
+
+	rates, err := p.repo.GetRatesTo(ctx, currency)
+	if err != nil {
+		return err
+	}
+	rates[currency] = types.RateOne()
+
+	for i := range products {
+		product := &products[i]
+
+		rate, ok := rates[product.Currency]
+		if !ok {
+			log.Warn().Str("from", product.Currency).Str("to", currency).Msg("service: Exchange rate not found")
+			continue
+		}
+
+		price, err := product.Price.Convert(rate)
+		if err != nil {
+			return err
+		}
+
+		originalPrice, err := product.OriginalPrice.Convert(rate)
+		if err != nil {
+			return err
+		}
+
+		product.Display = &entity.DisplayPrice{
+			Currency:      currency,
+			Price:         price,
+			OriginalPrice: originalPrice,
+			Rate:          rate,
+		}
+	}
+
+	return nil
+}
+
 func (p *productService) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (entity.UpsertProductResponse, error) {
 	var res entity.UpsertProductResponse
 
@@ -76,6 +130,18 @@ func (p *productService) UpdateProduct(ctx context.Context, req *entity.UpdatePr
 }
 
 func (p *productService) UpdateProductStock(ctx context.Context, req *entity.UpdateProductStockRequest) error {
+	if req.UserId != "" {
+		isProductsOwner, err := p.repo.IsProductsOwner(ctx, req.UserId, req.ShopId, req.ProductIds())
+		if err != nil {
+			return err
+		}
+
+		if !isProductsOwner {
+			log.Warn().Any("payload", req).Msg("service: User is not owner of all products")
+			return errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+		}
+	}
+
 	return p.repo.UpdateProductStock(ctx, req)
 }
 
@@ -92,3 +158,126 @@ func (p *productService) DeleteProduct(ctx context.Context, req *entity.DeletePr
 
 	return p.repo.DeleteProduct(ctx, req)
 }
+
+func (p *productService) GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (entity.GetProductBySlugResponse, error) {
+	var res entity.GetProductBySlugResponse
+
+	lookup, err := p.repo.ResolveProductSlug(ctx, req.Slug)
+	if err != nil {
+		return res, err
+	}
+
+	if lookup.Slug != req.Slug {
+		res.RedirectSlug = lookup.Slug
+		return res, nil
+	}
+
+	products, err := p.repo.GetProducts(ctx, &entity.GetProductsRequest{
+		ProductIds: []string{lookup.Id},
+		Page:       1,
+		Limit:      1,
+	})
+	if err != nil {
+		return res, err
+	}
+
+	// the product exists but its shop is not publicly visible
+	if len(products.Items) == 0 {
+		log.Warn().Any("payload", req).Msg("service: Product not found")
+		return res, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	if err = p.setDisplayPrices(ctx, products.Items, req.Currency); err != nil {
+		return res, err
+	}
+
+	res.Product = &products.Items[0]
+	return res, nil
+}
+
+func (p *productService) ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.ReservationResponse, error) {
+	return p.repo.ReserveStock(ctx, req)
+}
+
+func (p *productService) CommitReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	return p.repo.CommitReservation(ctx, req)
+}
+
+func (p *productService) ReleaseReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	return p.repo.ReleaseReservation(ctx, req)
+}
+
+func (p *productService) GetFeed(ctx context.Context, req *entity.GetFeedRequest) (entity.GetFeedResponse, error) {
+	return p.repo.GetFeed(ctx, req)
+}
+
+func (p *productService) CreatePriceSchedule(ctx context.Context, req *entity.CreatePriceScheduleRequest) (entity.PriceSchedule, error) {
+	var res entity.PriceSchedule
+
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	res, err = p.repo.CreatePriceSchedule(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	PriceScheduler.Wake()
+	return res, nil
+}
+
+func (p *productService) GetPriceSchedules(ctx context.Context, req *entity.PriceScheduleRequest) ([]entity.PriceSchedule, error) {
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	return p.repo.GetPriceSchedules(ctx, req.ProductId)
+}
+
+func (p *productService) CancelPriceSchedule(ctx context.Context, req *entity.PriceScheduleRequest) error {
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	if err = p.repo.CancelPriceSchedule(ctx, req); err != nil {
+		return err
+	}
+
+	PriceScheduler.Wake()
+	return nil
+}
+
+func (p *productService) ApplyPriceSchedules(ctx context.Context, now time.Time) (entity.ApplyPriceSchedulesResult, error) {
+	return p.repo.ApplyPriceSchedules(ctx, now)
+}
+
+func (p *productService) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error) {
+	return p.repo.GetPriceHistory(ctx, req)
+}
+
+func (p *productService) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	return p.repo.GetExchangeRates(ctx)
+}
+
+func (p *productService) UpsertExchangeRate(ctx context.Context, req *entity.UpsertExchangeRateRequest) (entity.ExchangeRate, error) {
+	return p.repo.UpsertExchangeRate(ctx, req)
+}
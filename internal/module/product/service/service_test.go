@@ -1,15 +1,23 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
+	"codebase-app/internal/infrastructure/config"
 	"codebase-app/internal/module/product/entity"
 	"codebase-app/internal/module/product/ports"
+	mockMailer "codebase-app/mock/integration/mailer"
 	mockPort "codebase-app/mock/module/product/ports"
 	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/event"
+	"codebase-app/pkg/types"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -40,7 +48,7 @@ func (suite *ServiceList) SetupTest() {
 		Name:        "Product 1",
 		Description: nil,
 		ImageUrl:    nil,
-		Price:       1000,
+		Price:       types.NewMoney(1000),
 		Stock:       10,
 	}
 
@@ -51,7 +59,7 @@ func (suite *ServiceList) SetupTest() {
 		Name:        "Product 1",
 		Description: nil,
 		ImageUrl:    nil,
-		Price:       1000,
+		Price:       types.NewMoney(1000),
 		Stock:       10,
 	}
 
@@ -67,7 +75,7 @@ func (suite *ServiceList) SetupTest() {
 				ShopId:     "1",
 				Name:       "Product 1",
 				ImageUrl:   nil,
-				Price:      1000,
+				Price:      types.NewMoney(1000),
 				Stock:      10,
 			},
 		},
@@ -156,6 +164,27 @@ func (suite *ServiceList) TestGetProducts_GetProductsRepoError() {
 	suite.Equal(errors.New("error"), err)
 }
 
+func (suite *ServiceList) TestGetProducts_DisplayCurrency() {
+	ctx := context.Background()
+	req := &entity.GetProductsRequest{Currency: "USD"}
+	res := entity.GetProductsResponse{Items: []entity.Product{
+		{Id: "1", Currency: "IDR", Price: types.NewMoney(150000), OriginalPrice: types.NewMoney(200000)},
+		{Id: "2", Currency: "USD", Price: types.MustParseMoney("9.99"), OriginalPrice: types.MustParseMoney("9.99")},
+		{Id: "3", Currency: "JPY", Price: types.NewMoney(1000), OriginalPrice: types.NewMoney(1000)},
+	}}
+	rate, _ := types.ParseRate("0.0000625")
+
+	suite.mockProductRepo.On("GetProducts", ctx, req).Return(res, nil)
+	suite.mockProductRepo.On("GetRatesTo", ctx, "USD").Return(map[string]types.Rate{"IDR": rate}, nil)
+	resp, err := suite.service.GetProducts(ctx, req)
+
+	suite.Nil(err)
+	suite.Equal("9.375", resp.Items[0].Display.Price.String())
+	suite.Equal("12.5", resp.Items[0].Display.OriginalPrice.String())
+	suite.Equal(types.MustParseMoney("9.99"), resp.Items[1].Display.Price)
+	suite.Nil(resp.Items[2].Display)
+}
+
 func (suite *ServiceList) TestGetProducts_ProductsEmpty() {
 	ctx := context.Background()
 	req := suite.mockGetProductsReq
@@ -244,6 +273,42 @@ func (suite *ServiceList) TestUpdateProductStock_Success() {
 	suite.Equal(nil, err)
 }
 
+func (suite *ServiceList) TestUpdateProductStock_UserIsNotTheProductsOwner() {
+	ctx := context.Background()
+	reqMock := &entity.UpdateProductStockRequest{
+		UserId: "1",
+		Items: []entity.UpdateStock{
+			{ProductId: "1", Stock: 5},
+			{ProductId: "2", Stock: 7},
+		},
+	}
+
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+
+	suite.mockProductRepo.On("IsProductsOwner", ctx, reqMock.UserId, reqMock.ShopId, []string{"1", "2"}).Return(false, nil)
+	err := suite.service.UpdateProductStock(ctx, reqMock)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "UpdateProductStock", ctx, reqMock)
+}
+
+func (suite *ServiceList) TestUpdateProductStock_OwnerSuccess() {
+	ctx := context.Background()
+	reqMock := &entity.UpdateProductStockRequest{
+		UserId: "1",
+		ShopId: "2",
+		Items: []entity.UpdateStock{
+			{ProductId: "1", Stock: 5},
+		},
+	}
+
+	suite.mockProductRepo.On("IsProductsOwner", ctx, reqMock.UserId, reqMock.ShopId, []string{"1"}).Return(true, nil)
+	suite.mockProductRepo.On("UpdateProductStock", ctx, reqMock).Return(nil)
+	err := suite.service.UpdateProductStock(ctx, reqMock)
+
+	suite.Equal(nil, err)
+}
+
 // Testing DeleteProduct
 func (suite *ServiceList) TestDeleteProduct_Success() {
 	ctx := context.Background()
@@ -287,6 +352,434 @@ func (suite *ServiceList) TestDeleteProduct_UserIsNotTheProductOwner() {
 	suite.Equal(errForbidden, err)
 }
 
+// Testing GetProductBySlug
+
+func (suite *ServiceList) TestGetProductBySlug_Success() {
+	ctx := context.Background()
+	req := &entity.GetProductBySlugRequest{Slug: "kopi-arabika"}
+	res := entity.GetProductsResponse{Items: []entity.Product{{Id: "1", Slug: "kopi-arabika"}}}
+
+	suite.mockProductRepo.On("ResolveProductSlug", ctx, req.Slug).Return(entity.SlugLookup{Id: "1", Slug: "kopi-arabika"}, nil)
+	suite.mockProductRepo.On("GetProducts", ctx, mock.Anything).Return(res, nil)
+	resp, err := suite.service.GetProductBySlug(ctx, req)
+
+	suite.Equal(nil, err)
+	suite.Equal("", resp.RedirectSlug)
+	suite.Equal("1", resp.Product.Id)
+}
+
+func (suite *ServiceList) TestGetProductBySlug_OldSlugRedirects() {
+	ctx := context.Background()
+	req := &entity.GetProductBySlugRequest{Slug: "kopi-lama"}
+
+	suite.mockProductRepo.On("ResolveProductSlug", ctx, req.Slug).Return(entity.SlugLookup{Id: "1", Slug: "kopi-arabika"}, nil)
+	resp, err := suite.service.GetProductBySlug(ctx, req)
+
+	suite.Equal(nil, err)
+	suite.Equal("kopi-arabika", resp.RedirectSlug)
+	suite.Nil(resp.Product)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "GetProducts", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestGetProductBySlug_ShopNotVisible() {
+	ctx := context.Background()
+	req := &entity.GetProductBySlugRequest{Slug: "kopi-arabika"}
+	errNotFound := errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+
+	suite.mockProductRepo.On("ResolveProductSlug", ctx, req.Slug).Return(entity.SlugLookup{Id: "1", Slug: "kopi-arabika"}, nil)
+	suite.mockProductRepo.On("GetProducts", ctx, mock.Anything).Return(suite.mockGetProductEmptyProductRes, nil)
+	_, err := suite.service.GetProductBySlug(ctx, req)
+
+	suite.Equal(errNotFound, err)
+}
+
+// Testing price schedules
+
+func (suite *ServiceList) TestCreatePriceSchedule_UserIsNotTheProductOwner() {
+	ctx := context.Background()
+	req := &entity.CreatePriceScheduleRequest{UserId: "1", ProductId: "2", Kind: entity.PriceScheduleKindSale}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+
+	suite.mockProductRepo.On("IsProductOwner", ctx, req.UserId, req.ProductId).Return(false, nil)
+	_, err := suite.service.CreatePriceSchedule(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "CreatePriceSchedule", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestCreatePriceSchedule_Success() {
+	ctx := context.Background()
+	req := &entity.CreatePriceScheduleRequest{UserId: "1", ProductId: "2", Kind: entity.PriceScheduleKindSale}
+
+	suite.mockProductRepo.On("IsProductOwner", ctx, req.UserId, req.ProductId).Return(true, nil)
+	suite.mockProductRepo.On("CreatePriceSchedule", ctx, req).Return(entity.PriceSchedule{Id: "3"}, nil)
+	resp, err := suite.service.CreatePriceSchedule(ctx, req)
+
+	suite.Equal(nil, err)
+	suite.Equal("3", resp.Id)
+}
+
+func TestCreatePriceScheduleRequest_CostumValidation(t *testing.T) {
+	var (
+		start  = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		end    = start.Add(24 * time.Hour)
+		before = start.Add(-time.Hour)
+	)
+
+	tests := []struct {
+		name  string
+		req   entity.CreatePriceScheduleRequest
+		field string
+	}{
+		{"valid sale", entity.CreatePriceScheduleRequest{Kind: "sale", DiscountType: "percentage", DiscountValue: types.NewMoney(20), StartsAt: start, EndsAt: &end}, ""},
+		{"full percentage", entity.CreatePriceScheduleRequest{Kind: "sale", DiscountType: "percentage", DiscountValue: types.NewMoney(100), StartsAt: start, EndsAt: &end}, "discount_value"},
+		{"fixed above 100", entity.CreatePriceScheduleRequest{Kind: "sale", DiscountType: "fixed", DiscountValue: types.NewMoney(5000), StartsAt: start, EndsAt: &end}, ""},
+		{"ends before start", entity.CreatePriceScheduleRequest{Kind: "sale", DiscountType: "fixed", DiscountValue: types.NewMoney(10), StartsAt: start, EndsAt: &before}, "ends_at"},
+		{"price with end", entity.CreatePriceScheduleRequest{Kind: "price", StartsAt: start, EndsAt: &end}, "ends_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, errs := tt.req.CostumValidation()
+			if tt.field == "" {
+				assert.Equal(t, 0, code)
+				return
+			}
+
+			assert.Equal(t, 400, code)
+			assert.Contains(t, errs, tt.field)
+		})
+	}
+}
+
+func (suite *ServiceList) TestExportProducts_FullCatalogNeedsAdmin() {
+	ctx := context.Background()
+	req := &entity.ExportProductsRequest{UserId: "1", Role: "end_user", Format: entity.ExportFormatCSV}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("Only admins can export the full catalog"))
+
+	_, err := suite.service.ExportProducts(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "ExportProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestExportProducts_UserIsNotTheShopOwner() {
+	ctx := context.Background()
+	req := &entity.ExportProductsRequest{UserId: "1", Role: "end_user", Format: entity.ExportFormatCSV}
+	req.ShopId = "2"
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+
+	suite.mockProductRepo.On("IsShopOwner", ctx, req.UserId, req.ShopId).Return(false, nil)
+	_, err := suite.service.ExportProducts(ctx, req)
+
+	suite.Equal(errForbidden, err)
+}
+
+func (suite *ServiceList) TestExportProducts_GoogleFeed() {
+	config.Envs = new(config.Config)
+	config.Envs.App.Name = "shopeefun"
+	config.Envs.Frontend.ClientBaseURL = "http://localhost:5000"
+
+	var (
+		ctx         = context.Background()
+		req         = &entity.ExportProductsRequest{UserId: "1", Role: "admin", Format: entity.ExportFormatXML}
+		description = "Biji kopi <arabika> & robusta"
+		buf         bytes.Buffer
+	)
+
+	suite.mockProductRepo.On("ExportProducts", ctx, &req.GetProductsRequest, mock.Anything).Return([]entity.ExportProduct{
+		{Id: "p1", ShopName: "Kopi Kita", Name: "Kopi", Slug: "kopi", Description: &description, Price: types.NewMoney(100), SalePrice: types.MustParseMoney("89.5"), Currency: "IDR", Stock: 3},
+		{Id: "p2", ShopName: "Kopi Kita", Name: "Teh", Slug: "teh", Price: types.NewMoney(50), SalePrice: types.NewMoney(50), Currency: "IDR"},
+	}, nil)
+
+	export, err := suite.service.ExportProducts(ctx, req)
+	suite.NoError(err)
+	suite.NoError(export(ctx, &buf))
+
+	feed := buf.String()
+	suite.Contains(feed, `<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0"><channel><title>shopeefun catalog</title>`)
+	suite.Contains(feed, `<item><g:id>p1</g:id><g:title>Kopi</g:title><g:description>Biji kopi &lt;arabika&gt; &amp; robusta</g:description><g:link>http://localhost:5000/products/kopi</g:link><g:availability>in_stock</g:availability><g:price>100 IDR</g:price><g:sale_price>89.5 IDR</g:sale_price>`)
+	suite.Contains(feed, `<g:description>Teh</g:description><g:link>http://localhost:5000/products/teh</g:link><g:availability>out_of_stock</g:availability><g:price>50 IDR</g:price><g:condition>`)
+	suite.True(bytes.HasSuffix(buf.Bytes(), []byte("</item></channel></rss>\n")))
+}
+
+func (suite *ServiceList) TestExportProducts_CSVCanBeImported() {
+	config.Envs = new(config.Config)
+
+	var (
+		ctx = context.Background()
+		req = &entity.ExportProductsRequest{UserId: "1", Role: "end_user", Format: entity.ExportFormatCSV}
+		sku = "KP-1"
+		buf bytes.Buffer
+	)
+	req.ShopId = "2"
+
+	suite.mockProductRepo.On("IsShopOwner", ctx, req.UserId, req.ShopId).Return(true, nil)
+	suite.mockProductRepo.On("ExportProducts", ctx, &req.GetProductsRequest, mock.Anything).Return([]entity.ExportProduct{
+		{Id: "p1", ShopId: "2", CategoryId: "c1", Sku: &sku, Name: "Kopi", Slug: "kopi", Price: types.NewMoney(100), SalePrice: types.NewMoney(100), Currency: "IDR", Stock: 3},
+	}, nil)
+
+	export, err := suite.service.ExportProducts(ctx, req)
+	suite.NoError(err)
+	suite.NoError(export(ctx, &buf))
+
+	rows, errs, err := entity.ParseImportRows(entity.ImportFormatCSV, buf.Bytes())
+	suite.NoError(err)
+	suite.Empty(errs)
+	suite.Equal([]entity.ImportRow{{Row: 1, Sku: "KP-1", CategoryId: "c1", Name: "Kopi", Price: types.NewMoney(100), Currency: "IDR", Stock: 3}}, rows)
+}
+
+func (suite *ServiceList) TestBulkUpdateProducts_UserIsNotTheProductsOwner() {
+	ctx := context.Background()
+	req := &entity.BulkProductsRequest{UserId: "1", ProductIds: []string{"2", "3"}, Action: entity.BulkActionDelete}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+
+	suite.mockProductRepo.On("IsProductsOwner", ctx, req.UserId, "", req.ProductIds).Return(false, nil)
+	_, err := suite.service.BulkUpdateProducts(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "BulkUpdateProducts", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestBulkUpdateProducts_FilterUserIsNotTheShopOwner() {
+	ctx := context.Background()
+	req := &entity.BulkProductsRequest{UserId: "1", Filter: &entity.BulkProductsFilter{ShopId: "2"}, Action: entity.BulkActionDelete}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+
+	suite.mockProductRepo.On("IsShopOwner", ctx, req.UserId, req.Filter.ShopId).Return(false, nil)
+	_, err := suite.service.BulkUpdateProducts(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "BulkUpdateProducts", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestBulkUpdateProducts_Success() {
+	ctx := context.Background()
+	req := &entity.BulkProductsRequest{UserId: "1", ProductIds: []string{"2"}, Action: entity.BulkActionDelete}
+
+	suite.mockProductRepo.On("IsProductsOwner", ctx, req.UserId, "", req.ProductIds).Return(true, nil)
+	suite.mockProductRepo.On("BulkUpdateProducts", ctx, req).Return(entity.BulkProductsResponse{Matched: 1, Changed: 1}, nil)
+	resp, err := suite.service.BulkUpdateProducts(ctx, req)
+
+	suite.Equal(nil, err)
+	suite.Equal(1, resp.Changed)
+}
+
+func TestBulkProductsRequest_Apply(t *testing.T) {
+	var (
+		before   = entity.BulkProductState{CategoryId: "c1", Price: types.NewMoney(100), Stock: 0}
+		price    = types.MustParseMoney("75.5")
+		discount = types.NewMoney(-15)
+		cheap    = types.NewMoney(-100)
+		stock    = int64(8)
+	)
+
+	tests := []struct {
+		name    string
+		req     entity.BulkProductsRequest
+		want    entity.BulkProductState
+		wantErr bool
+	}{
+		{"set price", entity.BulkProductsRequest{Action: entity.BulkActionSetPrice, Price: &price}, entity.BulkProductState{CategoryId: "c1", Price: price}, false},
+		{"adjust price", entity.BulkProductsRequest{Action: entity.BulkActionAdjustPrice, Percent: &discount}, entity.BulkProductState{CategoryId: "c1", Price: types.NewMoney(85)}, false},
+		{"adjust price to zero", entity.BulkProductsRequest{Action: entity.BulkActionAdjustPrice, Percent: &cheap}, entity.BulkProductState{}, true},
+		{"move category", entity.BulkProductsRequest{Action: entity.BulkActionMoveCategory, CategoryId: "c2"}, entity.BulkProductState{CategoryId: "c2", Price: types.NewMoney(100)}, false},
+		{"set stock", entity.BulkProductsRequest{Action: entity.BulkActionSetStock, Stock: &stock}, entity.BulkProductState{CategoryId: "c1", Price: types.NewMoney(100), Stock: 8}, false},
+		{"delete", entity.BulkProductsRequest{Action: entity.BulkActionDelete}, entity.BulkProductState{CategoryId: "c1", Price: types.NewMoney(100), Deleted: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.Apply(before)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBulkProductsRequest_CostumValidation(t *testing.T) {
+	var (
+		zero = types.Money(0)
+		big  = types.NewMoney(1001)
+	)
+
+	tests := []struct {
+		name   string
+		req    entity.BulkProductsRequest
+		fields []string
+	}{
+		{"no target", entity.BulkProductsRequest{Action: entity.BulkActionDelete}, []string{"product_ids"}},
+		{"ids and filter", entity.BulkProductsRequest{ProductIds: []string{"1"}, Filter: &entity.BulkProductsFilter{}, Action: entity.BulkActionDelete}, []string{"product_ids"}},
+		{"set price without price", entity.BulkProductsRequest{ProductIds: []string{"1"}, Action: entity.BulkActionSetPrice}, []string{"price"}},
+		{"zero percent", entity.BulkProductsRequest{ProductIds: []string{"1"}, Action: entity.BulkActionAdjustPrice, Percent: &zero}, []string{"percent"}},
+		{"percent too high", entity.BulkProductsRequest{ProductIds: []string{"1"}, Action: entity.BulkActionAdjustPrice, Percent: &big}, []string{"percent"}},
+		{"move without category", entity.BulkProductsRequest{Filter: &entity.BulkProductsFilter{}, Action: entity.BulkActionMoveCategory}, []string{"category_id"}},
+		{"valid", entity.BulkProductsRequest{Filter: &entity.BulkProductsFilter{}, Action: entity.BulkActionDelete}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := tt.req.CostumValidation()
+
+			fields := make([]string, 0)
+			for field := range errs {
+				fields = append(fields, field)
+			}
+
+			assert.ElementsMatch(t, tt.fields, fields)
+		})
+	}
+}
+
 func TestService(t *testing.T) {
 	suite.Run(t, new(ServiceList))
 }
+
+func (suite *ServiceList) TestGetProductTrash_PurgeAt() {
+	config.Envs = new(config.Config)
+	config.Envs.Trash.RetentionDays = 30
+
+	var (
+		ctx       = context.Background()
+		req       = &entity.GetProductTrashRequest{UserId: "1", Page: 1, Limit: 20}
+		deletedAt = time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)
+	)
+
+	suite.mockProductRepo.On("GetProductTrash", ctx, req, mock.AnythingOfType("time.Time")).Return(entity.GetProductTrashResponse{
+		Items: []entity.TrashedProduct{{Id: "2", DeletedAt: deletedAt}},
+	}, nil)
+	resp, err := suite.service.GetProductTrash(ctx, req)
+
+	suite.NoError(err)
+	suite.Equal(deletedAt.AddDate(0, 0, 30), resp.Items[0].PurgeAt)
+}
+
+func (suite *ServiceList) TestRestoreProduct_UserIsNotTheProductOwner() {
+	ctx := context.Background()
+	req := &entity.RestoreProductRequest{UserId: "1", ProductId: "2"}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+
+	suite.mockProductRepo.On("IsProductOwner", ctx, req.UserId, req.ProductId).Return(false, nil)
+	_, err := suite.service.RestoreProduct(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "RestoreProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestPurgeProducts_Batches() {
+	config.Envs = new(config.Config)
+	config.Envs.Trash.RetentionDays = 30
+
+	var (
+		ctx    = context.Background()
+		now    = time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)
+		before = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	suite.mockProductRepo.On("PurgeProducts", ctx, before, purgeBatchSize).Return(entity.PurgeResult{Products: purgeBatchSize, Images: []string{"a.png"}}, nil).Once()
+	suite.mockProductRepo.On("PurgeProducts", ctx, before, purgeBatchSize).Return(entity.PurgeResult{Products: 3, Images: []string{"b.png"}}, nil).Once()
+	resp, err := suite.service.PurgeProducts(ctx, now)
+
+	suite.NoError(err)
+	suite.Equal(entity.PurgeResult{Products: purgeBatchSize + 3, Images: []string{"a.png", "b.png"}}, resp)
+	suite.mockProductRepo.AssertNumberOfCalls(suite.T(), "PurgeProducts", 2)
+}
+
+// Testing stock alerts
+
+func (suite *ServiceList) TestSetLowStockThreshold_UserIsNotTheProductOwner() {
+	ctx := context.Background()
+	req := &entity.SetLowStockThresholdRequest{UserId: "1", ProductId: "2"}
+	errForbidden := errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+
+	suite.mockProductRepo.On("IsProductOwner", ctx, req.UserId, req.ProductId).Return(false, nil)
+	_, err := suite.service.SetLowStockThreshold(ctx, req)
+
+	suite.Equal(errForbidden, err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "SetLowStockThreshold", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestNotifyStockAlerts_RecordsUntilLastBatch() {
+	ctx := context.Background()
+	s := NewStockAlertService(suite.mockProductRepo)
+
+	suite.mockProductRepo.On("NotifyStockAlerts", ctx, stockAlertBatchSize).Return(stockAlertBatchSize, nil).Once()
+	suite.mockProductRepo.On("NotifyStockAlerts", ctx, stockAlertBatchSize).Return(3, nil).Once()
+	n, err := s.NotifyStockAlerts(ctx)
+
+	suite.Equal(nil, err)
+	suite.Equal(stockAlertBatchSize+3, n)
+	suite.mockProductRepo.AssertExpectations(suite.T())
+}
+
+// stockAlertEvent returns the event of an alert as the outbox relays it.
+func stockAlertEvent(alert entity.StockAlert) event.Event {
+	payload, _ := json.Marshal(alert)
+
+	return event.New(alert.EventType(), entity.AggregateProduct, alert.ProductId, json.RawMessage(payload))
+}
+
+func (suite *ServiceList) TestStockAlertMailer_SendsOnce() {
+	var (
+		ctx    = context.Background()
+		mailer = mockMailer.NewMockMailer()
+		hook   = NewStockAlertMailer(suite.mockProductRepo, mailer)
+		alert  = entity.StockAlert{Id: 1, ProductId: "p1", ProductName: "Kopi", ShopId: "s1", ShopName: "Toko", Level: entity.StockLevelOut}
+	)
+
+	suite.mockProductRepo.On("GetStockAlertEmail", ctx, int64(1)).Return("owner@mail.com", nil).Once()
+	suite.mockProductRepo.On("GetStockAlertEmail", ctx, int64(1)).Return("", nil).Once()
+	mailer.On("Send", ctx, "owner@mail.com", "Stok Kopi habis", mock.Anything).Return(nil).Once()
+	suite.mockProductRepo.On("MarkStockAlertEmailed", ctx, int64(1)).Return(nil).Once()
+
+	// the event is relayed again when the sink failed after the email
+	suite.NoError(hook.Publish(ctx, stockAlertEvent(alert), event.New(entity.EventStockChanged, entity.AggregateProduct, "p1", nil)))
+	suite.NoError(hook.Publish(ctx, stockAlertEvent(alert)))
+
+	mailer.AssertExpectations(suite.T())
+	suite.mockProductRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestStockAlertMailer_SendFailureRetriesEvent() {
+	var (
+		ctx    = context.Background()
+		mailer = mockMailer.NewMockMailer()
+		hook   = NewStockAlertMailer(suite.mockProductRepo, mailer)
+		alert  = entity.StockAlert{Id: 1, ProductId: "p1", ShopId: "s1", Level: entity.StockLevelLow}
+	)
+
+	suite.mockProductRepo.On("GetStockAlertEmail", ctx, int64(1)).Return("contact@mail.com", nil)
+	mailer.On("Send", ctx, "contact@mail.com", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+	err := hook.Publish(ctx, stockAlertEvent(alert))
+
+	suite.Equal(errors.New("smtp down"), err)
+	suite.mockProductRepo.AssertNotCalled(suite.T(), "MarkStockAlertEmailed", mock.Anything, mock.Anything)
+}
+
+func TestStockLevel(t *testing.T) {
+	threshold := 5
+
+	tests := []struct {
+		name      string
+		stock     int64
+		threshold *int
+		want      string
+	}{
+		{"no threshold", 3, nil, entity.StockLevelOk},
+		{"no threshold out", 0, nil, entity.StockLevelOut},
+		{"above threshold", 6, &threshold, entity.StockLevelOk},
+		{"at threshold", 5, &threshold, entity.StockLevelLow},
+		{"out", 0, &threshold, entity.StockLevelOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, entity.StockLevel(tt.stock, tt.threshold))
+		})
+	}
+}
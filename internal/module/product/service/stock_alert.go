@@ -0,0 +1,125 @@
+package service
+
+import (
+	integMailer "codebase-app/internal/integration/mailer"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/product/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/event"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// stockAlertBatchSize is how many stock alerts are recorded per transaction.
+const stockAlertBatchSize = 100
+
+// StockAlertScheduler records the pending stock alerts, every minute at most.
+var StockAlertScheduler = scheduler.NewTimer("stock_alerts", time.Minute)
+
+func (p *productService) SetLowStockThreshold(ctx context.Context, req *entity.SetLowStockThresholdRequest) (entity.SetLowStockThresholdResponse, error) {
+	var res entity.SetLowStockThresholdResponse
+
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	return p.repo.SetLowStockThreshold(ctx, req)
+}
+
+func (p *productService) GetLowStockProducts(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error) {
+	return p.repo.GetLowStockProducts(ctx, req)
+}
+
+type stockAlertService struct {
+	repo ports.ProductRepository
+}
+
+func NewStockAlertService(repo ports.ProductRepository) ports.StockAlertService {
+	return &stockAlertService{repo: repo}
+}
+
+// NotifyStockAlerts records the pending stock alerts as events and in-app
+// notifications and returns how many were sent.
+func (s *stockAlertService) NotifyStockAlerts(ctx context.Context) (int, error) {
+	var total int
+
+	for ctx.Err() == nil {
+		n, err := s.repo.NotifyStockAlerts(ctx, stockAlertBatchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += n
+		if n < stockAlertBatchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+type stockAlertMailer struct {
+	repo   ports.ProductRepository
+	mailer integMailer.MailerContract
+}
+
+// NewStockAlertMailer returns an outbox hook emailing the stock.low and
+// stock.out events to their shop once the alert committed. A failed email
+// fails the event, the outbox relays it again later, an alert emailed already
+// is skipped then.
+func NewStockAlertMailer(repo ports.ProductRepository, mailer integMailer.MailerContract) event.Publisher {
+	return &stockAlertMailer{
+		repo:   repo,
+		mailer: mailer,
+	}
+}
+
+func (m *stockAlertMailer) Publish(ctx context.Context, events ...event.Event) error {
+	for _, e := range events {
+		if e.Type != entity.EventStockLow && e.Type != entity.EventStockOut {
+			continue
+		}
+
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return err
+		}
+
+		var alert entity.StockAlert
+		if err = json.Unmarshal(payload, &alert); err != nil {
+			log.Error().Err(err).Str("event_id", e.Id).Msg("service: Invalid stock alert event")
+			return err
+		}
+
+		to, err := m.repo.GetStockAlertEmail(ctx, alert.Id)
+		if err != nil {
+			return err
+		}
+
+		if to == "" {
+			continue
+		}
+
+		title, body := alert.Text()
+		if err = m.mailer.Send(ctx, to, title, body); err != nil {
+			log.Error().Err(err).Int64("alert_id", alert.Id).Msg("service: Send stock alert failed")
+			return err
+		}
+
+		if err = m.repo.MarkStockAlertEmailed(ctx, alert.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// purgeBatchSize is how many products are purged per transaction.
+const purgeBatchSize = 100
+
+// trashRetention is how long deleted products can be restored.
+func trashRetention() time.Duration {
+	return time.Duration(config.Envs.Trash.RetentionDays) * 24 * time.Hour
+}
+
+func (p *productService) GetProductTrash(ctx context.Context, req *entity.GetProductTrashRequest) (entity.GetProductTrashResponse, error) {
+	retention := trashRetention()
+
+	res, err := p.repo.GetProductTrash(ctx, req, time.Now().Add(-retention))
+	if err != nil {
+		return res, err
+	}
+
+	for i := range res.Items {
+		res.Items[i].PurgeAt = res.Items[i].DeletedAt.Add(retention)
+	}
+
+	return res, nil
+}
+
+func (p *productService) RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest) (entity.UpsertProductResponse, error) {
+	var res entity.UpsertProductResponse
+
+	isProductOwner, err := p.repo.IsProductOwner(ctx, req.UserId, req.ProductId)
+	if err != nil {
+		return res, err
+	}
+
+	if !isProductOwner {
+		log.Warn().Any("payload", req).Msg("service: User is not product owner")
+		return res, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	return p.repo.RestoreProduct(ctx, req, time.Now().Add(-trashRetention()))
+}
+
+// PurgeProducts hard deletes, batch by batch, the products whose retention
+// ended before now.
+func (p *productService) PurgeProducts(ctx context.Context, now time.Time) (entity.PurgeResult, error) {
+	var res = entity.PurgeResult{Images: make([]string, 0)}
+
+	for ctx.Err() == nil {
+		batch, err := p.repo.PurgeProducts(ctx, now.Add(-trashRetention()), purgeBatchSize)
+		if err != nil {
+			return res, err
+		}
+
+		res.Products += batch.Products
+		res.Images = append(res.Images, batch.Images...)
+
+		if batch.Products < purgeBatchSize {
+			break
+		}
+	}
+
+	return res, ctx.Err()
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type CreateQuestionRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductId string `params:"id" validate:"required,uuid"`
+	Body      string `json:"body" validate:"required,min=5,max=1000"`
+}
+
+type CreateAnswerRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	QuestionId string `params:"id" validate:"required,uuid"`
+	Body       string `json:"body" validate:"required,min=1,max=2000"`
+}
+
+type MarkHelpfulRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	AnswerId string `params:"id" validate:"required,uuid"`
+}
+
+type MarkHelpfulResponse struct {
+	Id           string `json:"id" db:"id"`
+	HelpfulCount int    `json:"helpful_count" db:"helpful_count"`
+	Helpful      bool   `json:"helpful" db:"-"`
+}
+
+type GetQuestionsRequest struct {
+	ProductId  string `params:"id" validate:"required,uuid"`
+	Unanswered bool   `query:"unanswered"` // only questions the shop has not answered yet
+	Page       int    `query:"page" validate:"required"`
+	Paginate   int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetQuestionsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type Question struct {
+	Id        string    `json:"id" db:"id"`
+	ProductId string    `json:"product_id" db:"product_id"`
+	UserId    string    `json:"user_id" db:"user_id"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Answers   []Answer  `json:"answers" db:"-"`
+}
+
+type Answer struct {
+	Id           string    `json:"id" db:"id"`
+	QuestionId   string    `json:"question_id" db:"question_id"`
+	UserId       string    `json:"user_id" db:"user_id"`
+	Body         string    `json:"body" db:"body"`
+	HelpfulCount int       `json:"helpful_count" db:"helpful_count"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+type QuestionsResponse struct {
+	Items []Question `json:"items"`
+	Meta  types.Meta `json:"meta"`
+}
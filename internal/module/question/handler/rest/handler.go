@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	productRepo "codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/question/entity"
+	"codebase-app/internal/module/question/ports"
+	"codebase-app/internal/module/question/repository"
+	"codebase-app/internal/module/question/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type questionHandler struct {
+	service ports.QuestionService
+}
+
+func NewQuestionHandler() *questionHandler {
+	var (
+		handler = new(questionHandler)
+		db      = adapter.Adapters.ShopeefunPostgres
+		repo    = repository.NewQuestionRepository(db)
+		service = service.NewQuestionService(repo, productRepo.NewProductRepository(db))
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *questionHandler) Register(router fiber.Router) {
+	router.Get("/products/:id/questions", h.GetQuestions)
+	router.Post("/products/:id/questions", middleware.AuthBearer, h.CreateQuestion)
+	router.Post("/questions/:id/answers", middleware.AuthBearer, h.CreateAnswer)
+	router.Post("/answers/:id/helpful", middleware.AuthBearer, h.MarkHelpful)
+	router.Delete("/answers/:id/helpful", middleware.AuthBearer, h.UnmarkHelpful)
+}
+
+func (h *questionHandler) GetQuestions(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetQuestionsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetQuestions - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.ProductId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetQuestions - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetQuestions(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *questionHandler) CreateQuestion(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateQuestionRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::CreateQuestion - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateQuestion - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateQuestion(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *questionHandler) CreateAnswer(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateAnswerRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::CreateAnswer - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.QuestionId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateAnswer - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateAnswer(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *questionHandler) MarkHelpful(c *fiber.Ctx) error {
+	return h.vote(c, true)
+}
+
+func (h *questionHandler) UnmarkHelpful(c *fiber.Ctx) error {
+	return h.vote(c, false)
+}
+
+func (h *questionHandler) vote(c *fiber.Ctx, helpful bool) error {
+	var (
+		req = new(entity.MarkHelpfulRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.AnswerId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::MarkHelpful - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	var (
+		resp *entity.MarkHelpfulResponse
+		err  error
+	)
+	if helpful {
+		resp, err = h.service.MarkHelpful(ctx, req)
+	} else {
+		resp, err = h.service.UnmarkHelpful(ctx, req)
+	}
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"codebase-app/internal/module/question/entity"
+	"context"
+)
+
+type QuestionRepository interface {
+	CreateQuestion(ctx context.Context, req *entity.CreateQuestionRequest) (*entity.Question, error)
+	GetQuestion(ctx context.Context, id string) (*entity.Question, error)
+	GetQuestions(ctx context.Context, req *entity.GetQuestionsRequest) (*entity.QuestionsResponse, error)
+	CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (*entity.Answer, error)
+	MarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error)
+	UnmarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error)
+}
+
+// ProductOwnerChecker is the ownership check of the product module, shared so
+// that answering a question is allowed to exactly the users who may edit the
+// product.
+type ProductOwnerChecker interface {
+	IsProductOwner(ctx context.Context, userId, productId string) (bool, error)
+}
+
+type QuestionService interface {
+	CreateQuestion(ctx context.Context, req *entity.CreateQuestionRequest) (*entity.Question, error)
+	GetQuestions(ctx context.Context, req *entity.GetQuestionsRequest) (*entity.QuestionsResponse, error)
+	CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (*entity.Answer, error)
+	MarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error)
+	UnmarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error)
+}
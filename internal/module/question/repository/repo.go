@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"codebase-app/internal/module/question/entity"
+	"codebase-app/internal/module/question/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.QuestionRepository = &questionRepository{}
+
+type questionRepository struct {
+	db *sqlx.DB
+}
+
+func NewQuestionRepository(db *sqlx.DB) *questionRepository {
+	return &questionRepository{
+		db: db,
+	}
+}
+
+func (r *questionRepository) CreateQuestion(ctx context.Context, req *entity.CreateQuestionRequest) (*entity.Question, error) {
+	var resp = new(entity.Question)
+
+	query := `
+		INSERT INTO product_questions (product_id, user_id, body)
+		SELECT id, ?, ?
+		FROM products
+		WHERE id = ? AND deleted_at IS NULL
+		RETURNING id, product_id, user_id, body, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.UserId, req.Body, req.ProductId).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::CreateQuestion - Product not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateQuestion - Failed to insert question")
+		return nil, err
+	}
+
+	resp.Answers = make([]entity.Answer, 0)
+
+	return resp, nil
+}
+
+func (r *questionRepository) GetQuestion(ctx context.Context, id string) (*entity.Question, error) {
+	var resp = new(entity.Question)
+
+	query := `
+		SELECT id, product_id, user_id, body, created_at
+		FROM product_questions
+		WHERE id = ?
+	`
+
+	if err := r.db.GetContext(ctx, resp, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::GetQuestion - Question not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Question not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::GetQuestion - Failed to get question")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *questionRepository) GetQuestions(ctx context.Context, req *entity.GetQuestionsRequest) (*entity.QuestionsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Question
+	}
+
+	var (
+		resp = new(entity.QuestionsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.Question, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(q.id) OVER() as total_data,
+			q.id,
+			q.product_id,
+			q.user_id,
+			q.body,
+			q.created_at
+		FROM product_questions q
+		WHERE
+			q.product_id = ?
+			AND (NOT ? OR NOT EXISTS (
+				SELECT 1 FROM product_answers a WHERE a.question_id = q.id
+			))
+		ORDER BY q.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.ProductId,
+		req.Unanswered,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetQuestions - Failed to get questions")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	ids := make([]string, 0, len(data))
+	for _, d := range data {
+		ids = append(ids, d.Id)
+	}
+
+	answers, err := r.getAnswers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range data {
+		d.Question.Answers = answers[d.Id]
+		if d.Question.Answers == nil {
+			d.Question.Answers = make([]entity.Answer, 0)
+		}
+		resp.Items = append(resp.Items, d.Question)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// getAnswers returns the answers of the given questions keyed by question id,
+// the most helpful first.
+func (r *questionRepository) getAnswers(ctx context.Context, questionIds []string) (map[string][]entity.Answer, error) {
+	var (
+		result = make(map[string][]entity.Answer, len(questionIds))
+		data   = make([]entity.Answer, 0)
+	)
+
+	if len(questionIds) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, question_id, user_id, body, helpful_count, created_at
+		FROM product_answers
+		WHERE question_id = ANY(?)
+		ORDER BY helpful_count DESC, created_at ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), pq.Array(questionIds)); err != nil {
+		log.Error().Err(err).Strs("question_ids", questionIds).Msg("repository::getAnswers - Failed to get answers")
+		return nil, err
+	}
+
+	for _, a := range data {
+		result[a.QuestionId] = append(result[a.QuestionId], a)
+	}
+
+	return result, nil
+}
+
+func (r *questionRepository) CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (*entity.Answer, error) {
+	var resp = new(entity.Answer)
+
+	query := `
+		INSERT INTO product_answers (question_id, user_id, body)
+		VALUES (?, ?, ?)
+		RETURNING id, question_id, user_id, body, helpful_count, created_at
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.QuestionId, req.UserId, req.Body).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateAnswer - Failed to insert answer")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *questionRepository) MarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	return r.vote(ctx, req, true)
+}
+
+func (r *questionRepository) UnmarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	return r.vote(ctx, req, false)
+}
+
+// vote adds or removes the helpful vote of the user. The counter only moves
+// by the rows actually changed, so voting twice is idempotent.
+func (r *questionRepository) vote(ctx context.Context, req *entity.MarkHelpfulRequest, helpful bool) (resp *entity.MarkHelpfulResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkHelpful - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::MarkHelpful - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::MarkHelpful - Failed to commit transaction")
+		}
+	}()
+
+	var authorId string
+
+	query := `SELECT user_id FROM product_answers WHERE id = ?`
+
+	if err = tx.GetContext(ctx, &authorId, r.db.Rebind(query), req.AnswerId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::MarkHelpful - Answer not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Answer not found"))
+			return nil, err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkHelpful - Failed to get answer")
+		return nil, err
+	}
+
+	if authorId == req.UserId {
+		log.Warn().Any("payload", req).Msg("repository::MarkHelpful - User votes own answer")
+		err = errmsg.NewCustomErrors(400, errmsg.WithMessage("Tidak dapat menilai jawaban sendiri"))
+		return nil, err
+	}
+
+	if helpful {
+		query = `
+			INSERT INTO product_answer_votes (answer_id, user_id)
+			VALUES (?, ?)
+			ON CONFLICT (answer_id, user_id) DO NOTHING
+		`
+	} else {
+		query = `DELETE FROM product_answer_votes WHERE answer_id = ? AND user_id = ?`
+	}
+
+	result, err := tx.ExecContext(ctx, r.db.Rebind(query), req.AnswerId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkHelpful - Failed to update vote")
+		return nil, err
+	}
+
+	changed, _ := result.RowsAffected()
+	if !helpful {
+		changed = -changed
+	}
+
+	query = `
+		UPDATE product_answers
+		SET helpful_count = helpful_count + ?
+		WHERE id = ?
+		RETURNING id, helpful_count
+	`
+
+	resp = &entity.MarkHelpfulResponse{Helpful: helpful}
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), changed, req.AnswerId).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::MarkHelpful - Failed to update helpful count")
+		return nil, err
+	}
+
+	return resp, nil
+}
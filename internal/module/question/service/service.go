@@ -0,0 +1,59 @@
+package service
+
+import (
+	"codebase-app/internal/module/question/entity"
+	"codebase-app/internal/module/question/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.QuestionService = &questionService{}
+
+type questionService struct {
+	repo   ports.QuestionRepository
+	owners ports.ProductOwnerChecker
+}
+
+func NewQuestionService(repo ports.QuestionRepository, owners ports.ProductOwnerChecker) *questionService {
+	return &questionService{
+		repo:   repo,
+		owners: owners,
+	}
+}
+
+func (s *questionService) CreateQuestion(ctx context.Context, req *entity.CreateQuestionRequest) (*entity.Question, error) {
+	return s.repo.CreateQuestion(ctx, req)
+}
+
+func (s *questionService) GetQuestions(ctx context.Context, req *entity.GetQuestionsRequest) (*entity.QuestionsResponse, error) {
+	return s.repo.GetQuestions(ctx, req)
+}
+
+func (s *questionService) CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (*entity.Answer, error) {
+	question, err := s.repo.GetQuestion(ctx, req.QuestionId)
+	if err != nil {
+		return nil, err
+	}
+
+	isOwner, err := s.owners.IsProductOwner(ctx, req.UserId, question.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwner {
+		log.Warn().Any("payload", req).Msg("service::CreateAnswer - User is not product owner")
+		return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not product owner"))
+	}
+
+	return s.repo.CreateAnswer(ctx, req)
+}
+
+func (s *questionService) MarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	return s.repo.MarkHelpful(ctx, req)
+}
+
+func (s *questionService) UnmarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	return s.repo.UnmarkHelpful(ctx, req)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"codebase-app/internal/module/question/entity"
+	"codebase-app/internal/module/question/ports"
+	mockPort "codebase-app/mock/module/question/ports"
+	"codebase-app/pkg/errmsg"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceList struct {
+	suite.Suite
+	mockQuestionRepo *mockPort.MockQuestionRepo
+	mockOwners       *mockPort.MockProductOwnerChecker
+	service          ports.QuestionService
+
+	mockQuestion  *entity.Question
+	mockAnswerReq *entity.CreateAnswerRequest
+}
+
+func (suite *ServiceList) SetupTest() {
+	suite.mockQuestionRepo = mockPort.NewMockQuestionRepo()
+	suite.mockOwners = mockPort.NewMockProductOwnerChecker()
+	suite.service = NewQuestionService(suite.mockQuestionRepo, suite.mockOwners)
+
+	suite.mockQuestion = &entity.Question{Id: "q1", ProductId: "p1"}
+	suite.mockAnswerReq = &entity.CreateAnswerRequest{
+		UserId:     "u1",
+		QuestionId: "q1",
+		Body:       "Ready stock",
+	}
+}
+
+// Testing CreateAnswer
+
+func (suite *ServiceList) TestCreateAnswer_ByProductOwner() {
+	ctx := context.Background()
+	req := suite.mockAnswerReq
+
+	suite.mockQuestionRepo.On("GetQuestion", ctx, "q1").Return(suite.mockQuestion, nil)
+	suite.mockOwners.On("IsProductOwner", ctx, "u1", "p1").Return(true, nil)
+	suite.mockQuestionRepo.On("CreateAnswer", ctx, req).Return(&entity.Answer{QuestionId: "q1", Body: req.Body}, nil)
+	resp, err := suite.service.CreateAnswer(ctx, req)
+
+	suite.Nil(err)
+	suite.Equal("q1", resp.QuestionId)
+	suite.mockQuestionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestCreateAnswer_ByOtherUser() {
+	ctx := context.Background()
+	req := suite.mockAnswerReq
+
+	suite.mockQuestionRepo.On("GetQuestion", ctx, "q1").Return(suite.mockQuestion, nil)
+	suite.mockOwners.On("IsProductOwner", ctx, "u1", "p1").Return(false, nil)
+	_, err := suite.service.CreateAnswer(ctx, req)
+
+	customErr, ok := err.(*errmsg.CustomError)
+	suite.True(ok)
+	suite.Equal(403, customErr.Code)
+	suite.mockQuestionRepo.AssertNotCalled(suite.T(), "CreateAnswer", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestCreateAnswer_IsProductOwnerError() {
+	ctx := context.Background()
+	req := suite.mockAnswerReq
+
+	suite.mockQuestionRepo.On("GetQuestion", ctx, "q1").Return(suite.mockQuestion, nil)
+	suite.mockOwners.On("IsProductOwner", ctx, "u1", "p1").Return(false, errors.New("error"))
+	_, err := suite.service.CreateAnswer(ctx, req)
+
+	suite.Equal(errors.New("error"), err)
+	suite.mockQuestionRepo.AssertNotCalled(suite.T(), "CreateAnswer", mock.Anything, mock.Anything)
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceList))
+}
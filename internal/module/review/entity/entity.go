@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	StatusPublished = "published"
+	StatusHidden    = "hidden"
+)
+
+type CreateReviewRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductId string   `params:"id" validate:"required,uuid"`
+	Rating    int      `json:"rating" validate:"required,min=1,max=5"`
+	Body      *string  `json:"body" validate:"omitempty,max=2000"`
+	Photos    []string `json:"photos" validate:"omitempty,max=5,dive,required"` // base64 encoded jpeg or png, ex: "data:image/png;base64,..."
+
+	PhotoUrls []string `json:"-"`
+}
+
+type UpdateReviewRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id     string   `params:"id" validate:"required,uuid"`
+	Rating int      `json:"rating" validate:"required,min=1,max=5"`
+	Body   *string  `json:"body" validate:"omitempty,max=2000"`
+	Photos []string `json:"photos" validate:"omitempty,max=5,dive,required"` // replaces the current photos when set
+
+	PhotoUrls []string `json:"-"`
+}
+
+type DeleteReviewRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+type ReplyReviewRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id    string `params:"id" validate:"required,uuid"`
+	Reply string `json:"reply" validate:"required,min=1,max=2000"`
+}
+
+type ReportReviewRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Id     string `params:"id" validate:"required,uuid"`
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type ReportReviewResponse struct {
+	Id          string `json:"id" db:"id"`
+	ReportCount int    `json:"report_count" db:"report_count"`
+}
+
+type ModerateReviewRequest struct {
+	Id     string  `params:"id" validate:"required,uuid"`
+	Status string  `json:"status" validate:"required,oneof=published hidden"`
+	Reason *string `json:"reason" validate:"omitempty,max=255"`
+}
+
+type GetReviewsRequest struct {
+	ProductId string `params:"id" validate:"required,uuid"`
+	Rating    int    `query:"rating" validate:"omitempty,min=1,max=5"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetReviewsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type AdminReviewsRequest struct {
+	Status   string `query:"status" validate:"omitempty,oneof=published hidden"`
+	Reported bool   `query:"reported"` // only reviews with at least one report
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *AdminReviewsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type Review struct {
+	Id               string         `json:"id" db:"id"`
+	ProductId        string         `json:"product_id" db:"product_id"`
+	UserId           string         `json:"user_id" db:"user_id"`
+	Rating           int            `json:"rating" db:"rating"`
+	Body             *string        `json:"body" db:"body"`
+	PhotoUrls        pq.StringArray `json:"photo_urls" db:"photo_urls"`
+	Status           string         `json:"status" db:"status"`
+	ModerationReason *string        `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	ReportCount      int            `json:"report_count" db:"report_count"`
+	Reply            *string        `json:"reply" db:"reply"`
+	RepliedAt        *time.Time     `json:"replied_at" db:"replied_at"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// RatingSummary is the denormalized rating of a product, counting only
+// published reviews.
+type RatingSummary struct {
+	RatingAvg   float64 `json:"rating_avg" db:"rating_avg"`
+	RatingCount int     `json:"rating_count" db:"rating_count"`
+}
+
+type ReviewsResponse struct {
+	Summary *RatingSummary `json:"summary,omitempty"`
+	Items   []Review       `json:"items"`
+	Meta    types.Meta     `json:"meta"`
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"codebase-app/internal/module/review/entity"
+	"context"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.Review, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.Review, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.Review, error)
+	ReportReview(ctx context.Context, req *entity.ReportReviewRequest) (*entity.ReportReviewResponse, error)
+	ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) (*entity.Review, error)
+	GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.ReviewsResponse, error)
+	GetAdminReviews(ctx context.Context, req *entity.AdminReviewsRequest) (*entity.ReviewsResponse, error)
+}
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.Review, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.Review, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.Review, error)
+	ReportReview(ctx context.Context, req *entity.ReportReviewRequest) (*entity.ReportReviewResponse, error)
+	ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) (*entity.Review, error)
+	GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.ReviewsResponse, error)
+	GetAdminReviews(ctx context.Context, req *entity.AdminReviewsRequest) (*entity.ReviewsResponse, error)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	notifEnt "codebase-app/internal/module/notification/entity"
+	notifRepo "codebase-app/internal/module/notification/repository"
+	"codebase-app/internal/module/review/entity"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// reviewedProduct is the product of a review and the owner of its shop.
+type reviewedProduct struct {
+	Name       string `db:"name"`
+	ShopUserId string `db:"shop_user_id"`
+}
+
+func (r *reviewRepository) getReviewedProduct(ctx context.Context, tx *sqlx.Tx, productId string) (*reviewedProduct, error) {
+	var resp = new(reviewedProduct)
+
+	query := `
+		SELECT p.name, s.user_id AS shop_user_id
+		FROM products p
+		JOIN shops s ON s.id = p.shop_id
+		WHERE p.id = ?
+	`
+
+	if err := tx.GetContext(ctx, resp, r.db.Rebind(query), productId); err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::getReviewedProduct - Failed to get product")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// notifyReviewCreated tells the shop owner a product of theirs was reviewed.
+func (r *reviewRepository) notifyReviewCreated(ctx context.Context, tx *sqlx.Tx, review *entity.Review) error {
+	product, err := r.getReviewedProduct(ctx, tx, review.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if product.ShopUserId == review.UserId {
+		return nil
+	}
+
+	return notifRepo.Record(ctx, tx, notifEnt.New(product.ShopUserId, notifEnt.TypeReviewCreated,
+		"Ulasan baru",
+		fmt.Sprintf("%s mendapat ulasan bintang %d.", product.Name, review.Rating),
+		map[string]any{"review_id": review.Id, "product_id": review.ProductId, "rating": review.Rating},
+	))
+}
+
+// notifyReviewModerated tells the author the decision taken on their review.
+func (r *reviewRepository) notifyReviewModerated(ctx context.Context, tx *sqlx.Tx, review *entity.Review) error {
+	product, err := r.getReviewedProduct(ctx, tx, review.ProductId)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Ulasan Anda untuk %s ditampilkan kembali.", product.Name)
+	if review.Status == entity.StatusHidden {
+		body = fmt.Sprintf("Ulasan Anda untuk %s disembunyikan.", product.Name)
+	}
+	if review.ModerationReason != nil {
+		body += " Alasan: " + *review.ModerationReason
+	}
+
+	return notifRepo.Record(ctx, tx, notifEnt.New(review.UserId, notifEnt.TypeReviewModerated,
+		"Ulasan dimoderasi",
+		body,
+		map[string]any{"review_id": review.Id, "product_id": review.ProductId, "status": review.Status},
+	))
+}
@@ -0,0 +1,684 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/review/entity"
+	"codebase-app/internal/module/review/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.ReviewRepository = &reviewRepository{}
+
+type reviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepository(db *sqlx.DB) *reviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+const reviewColumns = `
+	r.id,
+	r.product_id,
+	r.user_id,
+	r.rating,
+	r.body,
+	r.photo_urls,
+	r.status,
+	r.moderation_reason,
+	r.report_count,
+	r.reply,
+	r.replied_at,
+	r.created_at,
+	r.updated_at
+`
+
+// reviewRow is a locked review together with the shop selling the product.
+type reviewRow struct {
+	entity.Review
+	ShopId     string `db:"shop_id"`
+	ShopUserId string `db:"shop_user_id"`
+}
+
+func (r *reviewRepository) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (resp *entity.Review, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::CreateReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::CreateReview - Failed to commit transaction")
+		}
+	}()
+
+	shopId, err := r.lockProduct(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	photoUrls := req.PhotoUrls
+	if photoUrls == nil {
+		photoUrls = make([]string, 0)
+	}
+
+	query := `
+		INSERT INTO product_reviews AS r (product_id, user_id, rating, body, photo_urls)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (product_id, user_id) DO NOTHING
+		RETURNING ` + reviewColumns
+
+	resp = new(entity.Review)
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.ProductId,
+		req.UserId,
+		req.Rating,
+		req.Body,
+		pq.StringArray(photoUrls),
+	).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::CreateReview - Product already reviewed by user")
+			err = errmsg.NewCustomErrors(409, errmsg.WithMessage("Produk ini sudah Anda ulas"))
+			return nil, err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to insert review")
+		return nil, err
+	}
+
+	if err = r.refreshRatings(ctx, tx, req.ProductId, shopId); err != nil {
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionCreate,
+		EntityType: auditEnt.EntityReview,
+		EntityId:   resp.Id,
+		After:      resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.notifyReviewCreated(ctx, tx, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (resp *entity.Review, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpdateReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpdateReview - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.lockReview(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.UserId != req.UserId {
+		log.Warn().Any("payload", req).Msg("repository::UpdateReview - User is not review author")
+		err = errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not review author"))
+		return nil, err
+	}
+
+	var photoUrls any
+	if req.PhotoUrls != nil {
+		photoUrls = pq.StringArray(req.PhotoUrls)
+	}
+
+	query := `
+		UPDATE product_reviews AS r
+		SET
+			rating = ?,
+			body = ?,
+			photo_urls = COALESCE(?, photo_urls),
+			updated_at = NOW()
+		WHERE id = ?
+		RETURNING ` + reviewColumns
+
+	resp = new(entity.Review)
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.Rating,
+		req.Body,
+		photoUrls,
+		req.Id,
+	).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateReview - Failed to update review")
+		return nil, err
+	}
+
+	if err = r.refreshRatings(ctx, tx, before.ProductId, before.ShopId); err != nil {
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityReview,
+		EntityId:   resp.Id,
+		Before:     before.Review,
+		After:      resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::DeleteReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::DeleteReview - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.lockReview(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if before.UserId != req.UserId {
+		log.Warn().Any("payload", req).Msg("repository::DeleteReview - User is not review author")
+		err = errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not review author"))
+		return err
+	}
+
+	query := `DELETE FROM product_reviews WHERE id = ?`
+
+	if _, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Id); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to delete review")
+		return err
+	}
+
+	if err = r.refreshRatings(ctx, tx, before.ProductId, before.ShopId); err != nil {
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionDelete,
+		EntityType: auditEnt.EntityReview,
+		EntityId:   req.Id,
+		Before:     before.Review,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *reviewRepository) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (resp *entity.Review, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReplyReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::ReplyReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::ReplyReview - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.lockReview(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.ShopUserId != req.UserId {
+		log.Warn().Any("payload", req).Msg("repository::ReplyReview - User is not shop owner")
+		err = errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+		return nil, err
+	}
+
+	query := `
+		UPDATE product_reviews AS r
+		SET
+			reply = ?,
+			replied_at = NOW()
+		WHERE id = ?
+		RETURNING ` + reviewColumns
+
+	resp = new(entity.Review)
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), req.Reply, req.Id).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReplyReview - Failed to reply review")
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityReview,
+		EntityId:   resp.Id,
+		Before:     before.Review,
+		After:      resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) ReportReview(ctx context.Context, req *entity.ReportReviewRequest) (resp *entity.ReportReviewResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReportReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::ReportReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::ReportReview - Failed to commit transaction")
+		}
+	}()
+
+	var authorId string
+
+	query := `SELECT user_id FROM product_reviews WHERE id = ? AND status = 'published'`
+
+	if err = tx.GetContext(ctx, &authorId, r.db.Rebind(query), req.Id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::ReportReview - Review not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Review not found"))
+			return nil, err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReportReview - Failed to get review")
+		return nil, err
+	}
+
+	if authorId == req.UserId {
+		log.Warn().Any("payload", req).Msg("repository::ReportReview - User reports own review")
+		err = errmsg.NewCustomErrors(400, errmsg.WithMessage("Tidak dapat melaporkan ulasan sendiri"))
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO product_review_reports (review_id, user_id, reason)
+		VALUES (?, ?, ?)
+		ON CONFLICT (review_id, user_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId, req.Reason)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReportReview - Failed to insert report")
+		return nil, err
+	}
+
+	// a user is only counted once however many times they report
+	inserted, _ := result.RowsAffected()
+
+	query = `
+		UPDATE product_reviews
+		SET report_count = report_count + ?
+		WHERE id = ?
+		RETURNING id, report_count
+	`
+
+	resp = new(entity.ReportReviewResponse)
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), inserted, req.Id).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReportReview - Failed to update report count")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) (resp *entity.Review, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ModerateReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::ModerateReview - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::ModerateReview - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.lockReview(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE product_reviews AS r
+		SET
+			status = ?,
+			moderation_reason = ?,
+			updated_at = NOW()
+		WHERE id = ?
+		RETURNING ` + reviewColumns
+
+	resp = new(entity.Review)
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), req.Status, req.Reason, req.Id).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ModerateReview - Failed to update review")
+		return nil, err
+	}
+
+	if err = r.refreshRatings(ctx, tx, before.ProductId, before.ShopId); err != nil {
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityReview,
+		EntityId:   resp.Id,
+		Before:     before.Review,
+		After:      resp,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if before.Status != resp.Status {
+		if err = r.notifyReviewModerated(ctx, tx, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *reviewRepository) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.ReviewsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Review
+	}
+
+	var (
+		resp = new(entity.ReviewsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.Review, 0, req.Paginate)
+	resp.Summary = new(entity.RatingSummary)
+
+	query := `
+		SELECT rating_avg, rating_count
+		FROM products
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	if err := r.db.GetContext(ctx, resp.Summary, r.db.Rebind(query), req.ProductId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::GetReviews - Product not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetReviews - Failed to get rating summary")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			COUNT(r.id) OVER() as total_data,
+			` + reviewColumns + `
+		FROM product_reviews r
+		WHERE
+			r.product_id = ?
+			AND r.status = 'published'
+			AND (? = 0 OR r.rating = ?)
+		ORDER BY r.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.ProductId,
+		req.Rating,
+		req.Rating,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetReviews - Failed to get reviews")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.Review)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *reviewRepository) GetAdminReviews(ctx context.Context, req *entity.AdminReviewsRequest) (*entity.ReviewsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Review
+	}
+
+	var (
+		resp = new(entity.ReviewsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.Review, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(r.id) OVER() as total_data,
+			` + reviewColumns + `
+		FROM product_reviews r
+		WHERE
+			(? = '' OR r.status = ?)
+			AND (NOT ? OR r.report_count > 0)
+		ORDER BY r.report_count DESC, r.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.Status,
+		req.Status,
+		req.Reported,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetAdminReviews - Failed to get reviews")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.Review)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// lockProduct locks the reviewed product and returns its shop id. Every write
+// to the reviews of a product takes this lock first, so the rating refresh at
+// the end of the transaction sees all committed reviews.
+func (r *reviewRepository) lockProduct(ctx context.Context, tx *sqlx.Tx, productId string) (string, error) {
+	var shopId string
+
+	query := `
+		SELECT shop_id
+		FROM products
+		WHERE id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	if err := tx.GetContext(ctx, &shopId, r.db.Rebind(query), productId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("product_id", productId).Msg("repository::lockProduct - Product not found")
+			return "", errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::lockProduct - Failed to lock product")
+		return "", err
+	}
+
+	return shopId, nil
+}
+
+// lockReview locks the product of the review before the review itself, in the
+// same order as CreateReview.
+func (r *reviewRepository) lockReview(ctx context.Context, tx *sqlx.Tx, id string) (*reviewRow, error) {
+	var productId string
+
+	query := `SELECT product_id FROM product_reviews WHERE id = ?`
+
+	if err := tx.GetContext(ctx, &productId, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::lockReview - Review not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Review not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::lockReview - Failed to get review")
+		return nil, err
+	}
+
+	if _, err := r.lockProduct(ctx, tx, productId); err != nil {
+		return nil, err
+	}
+
+	row := new(reviewRow)
+
+	query = `
+		SELECT
+			` + reviewColumns + `,
+			p.shop_id,
+			s.user_id AS shop_user_id
+		FROM product_reviews r
+		JOIN products p ON p.id = r.product_id
+		JOIN shops s ON s.id = p.shop_id
+		WHERE r.id = ?
+		FOR UPDATE OF r
+	`
+
+	if err := tx.GetContext(ctx, row, r.db.Rebind(query), id); err != nil {
+		if err == sql.ErrNoRows {
+			// deleted while waiting for the product lock
+			log.Warn().Str("id", id).Msg("repository::lockReview - Review not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Review not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::lockReview - Failed to lock review")
+		return nil, err
+	}
+
+	return row, nil
+}
+
+// refreshRatings recomputes the denormalized rating of the product and of its
+// shop from the published reviews. The product must already be locked by tx.
+func (r *reviewRepository) refreshRatings(ctx context.Context, tx *sqlx.Tx, productId, shopId string) error {
+	query := `
+		UPDATE products
+		SET
+			rating_avg = agg.rating_avg,
+			rating_count = agg.rating_count
+		FROM (
+			SELECT
+				COALESCE(ROUND(AVG(rating), 2), 0) AS rating_avg,
+				COUNT(*) AS rating_count
+			FROM product_reviews
+			WHERE product_id = ? AND status = 'published'
+		) agg
+		WHERE products.id = ?
+	`
+
+	if _, err := tx.ExecContext(ctx, r.db.Rebind(query), productId, productId); err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::refreshRatings - Failed to update product rating")
+		return err
+	}
+
+	// reviews of other products of the shop are not serialized by the product
+	// lock, so the shop row is locked before its aggregate is read
+	query = `SELECT id FROM shops WHERE id = ? FOR UPDATE`
+
+	if _, err := tx.ExecContext(ctx, r.db.Rebind(query), shopId); err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::refreshRatings - Failed to lock shop")
+		return err
+	}
+
+	query = `
+		UPDATE shops
+		SET
+			rating_avg = agg.rating_avg,
+			rating_count = agg.rating_count
+		FROM (
+			SELECT
+				COALESCE(ROUND(AVG(r.rating), 2), 0) AS rating_avg,
+				COUNT(*) AS rating_count
+			FROM product_reviews r
+			JOIN products p ON p.id = r.product_id
+			WHERE p.shop_id = ? AND r.status = 'published'
+		) agg
+		WHERE shops.id = ?
+	`
+
+	if _, err := tx.ExecContext(ctx, r.db.Rebind(query), shopId, shopId); err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::refreshRatings - Failed to update shop rating")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
+	"codebase-app/internal/module/review/entity"
+	"codebase-app/internal/module/review/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.ReviewService = &reviewService{}
+
+type reviewService struct {
+	repo    ports.ReviewRepository
+	storage integStorage.LocalStorageContract
+}
+
+func NewReviewService(repo ports.ReviewRepository, storage integStorage.LocalStorageContract) *reviewService {
+	return &reviewService{
+		repo:    repo,
+		storage: storage,
+	}
+}
+
+func (s *reviewService) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.Review, error) {
+	photoUrls, fullpaths, err := s.savePhotos(req.Photos)
+	if err != nil {
+		return nil, err
+	}
+	req.PhotoUrls = photoUrls
+
+	resp, err := s.repo.CreateReview(ctx, req)
+	if err != nil {
+		s.removePhotos(fullpaths)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *reviewService) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.Review, error) {
+	var fullpaths []string
+
+	if req.Photos != nil {
+		photoUrls, saved, err := s.savePhotos(req.Photos)
+		if err != nil {
+			return nil, err
+		}
+		req.PhotoUrls = photoUrls
+		fullpaths = saved
+	}
+
+	resp, err := s.repo.UpdateReview(ctx, req)
+	if err != nil {
+		s.removePhotos(fullpaths)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	return s.repo.DeleteReview(ctx, req)
+}
+
+func (s *reviewService) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.Review, error) {
+	return s.repo.ReplyReview(ctx, req)
+}
+
+func (s *reviewService) ReportReview(ctx context.Context, req *entity.ReportReviewRequest) (*entity.ReportReviewResponse, error) {
+	return s.repo.ReportReview(ctx, req)
+}
+
+func (s *reviewService) ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) (*entity.Review, error) {
+	return s.repo.ModerateReview(ctx, req)
+}
+
+func (s *reviewService) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.ReviewsResponse, error) {
+	return s.repo.GetReviews(ctx, req)
+}
+
+func (s *reviewService) GetAdminReviews(ctx context.Context, req *entity.AdminReviewsRequest) (*entity.ReviewsResponse, error) {
+	return s.repo.GetAdminReviews(ctx, req)
+}
+
+// savePhotos stores the base64 photos and returns their public urls, in the
+// same order, and the paths of the saved files. Nothing is kept when a photo
+// fails to save.
+func (s *reviewService) savePhotos(photos []string) ([]string, []string, error) {
+	var (
+		publicPath = config.Envs.App.LocalStoragePublicPath
+		urls       = make([]string, 0, len(photos))
+		fullpaths  = make([]string, 0, len(photos))
+	)
+
+	for i, photo := range photos {
+		field := fmt.Sprintf("photos[%d]", i)
+
+		fullpath, err := s.storage.Save(photo, publicPath+"/reviews")
+		if err != nil {
+			s.removePhotos(fullpaths)
+			if err == integStorage.ErrFileTypeNotSupported {
+				return nil, nil, errmsg.NewCustomErrors(400, errmsg.WithErrors(field, field+" harus berupa gambar jpeg atau png."))
+			}
+			log.Error().Err(err).Str("field", field).Msg("service::savePhotos - Failed to save review photo")
+			return nil, nil, errmsg.NewCustomErrors(400, errmsg.WithErrors(field, field+" tidak valid."))
+		}
+
+		fullpaths = append(fullpaths, fullpath)
+		urls = append(urls, config.Envs.App.BaseURL+"/products/storage"+strings.TrimPrefix(fullpath, publicPath))
+	}
+
+	return urls, fullpaths, nil
+}
+
+// removePhotos deletes the files saved for a review that was not stored, a
+// failure only leaves an orphan file behind.
+func (s *reviewService) removePhotos(fullpaths []string) {
+	for _, fullpath := range fullpaths {
+		if err := s.storage.Remove(fullpath); err != nil {
+			log.Warn().Err(err).Str("fullpath", fullpath).Msg("service::removePhotos - Failed to remove review photo")
+		}
+	}
+}
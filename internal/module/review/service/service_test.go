@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
+	"codebase-app/internal/module/review/entity"
+	"codebase-app/internal/module/review/ports"
+	mockStorage "codebase-app/mock/integration/localstorage"
+	mockPort "codebase-app/mock/module/review/ports"
+	"codebase-app/pkg/errmsg"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+func init() {
+	config.Envs = new(config.Config)
+	config.Envs.App.BaseURL = "http://localhost:3000"
+	config.Envs.App.LocalStoragePublicPath = "./storage/public"
+}
+
+type ServiceList struct {
+	suite.Suite
+	mockReviewRepo *mockPort.MockReviewRepo
+	mockStorage    *mockStorage.MockLocalStorage
+	service        ports.ReviewService
+}
+
+func (suite *ServiceList) SetupTest() {
+	suite.mockReviewRepo = mockPort.NewMockReviewRepo()
+	suite.mockStorage = mockStorage.NewMockLocalStorage()
+	suite.service = NewReviewService(suite.mockReviewRepo, suite.mockStorage)
+}
+
+// Testing CreateReview
+
+func (suite *ServiceList) TestCreateReview_SavesPhotos() {
+	ctx := context.Background()
+	req := &entity.CreateReviewRequest{Rating: 5, Photos: []string{"a", "b"}}
+
+	suite.mockStorage.On("Save", "a", "./storage/public/reviews").Return("./storage/public/reviews/a.png", nil)
+	suite.mockStorage.On("Save", "b", "./storage/public/reviews").Return("./storage/public/reviews/b.png", nil)
+	suite.mockReviewRepo.On("CreateReview", ctx, req).Return(&entity.Review{}, nil)
+	_, err := suite.service.CreateReview(ctx, req)
+
+	suite.Nil(err)
+	suite.Equal([]string{
+		"http://localhost:3000/products/storage/reviews/a.png",
+		"http://localhost:3000/products/storage/reviews/b.png",
+	}, req.PhotoUrls)
+	suite.mockStorage.AssertNotCalled(suite.T(), "Remove", mock.Anything)
+}
+
+func (suite *ServiceList) TestCreateReview_UnsupportedPhoto() {
+	ctx := context.Background()
+	req := &entity.CreateReviewRequest{Rating: 4, Photos: []string{"a", "b"}}
+
+	suite.mockStorage.On("Save", "a", "./storage/public/reviews").Return("./storage/public/reviews/a.png", nil)
+	suite.mockStorage.On("Save", "b", "./storage/public/reviews").Return("", integStorage.ErrFileTypeNotSupported)
+	suite.mockStorage.On("Remove", "./storage/public/reviews/a.png").Return(nil)
+	_, err := suite.service.CreateReview(ctx, req)
+
+	customErr, ok := err.(*errmsg.CustomError)
+	suite.True(ok)
+	suite.Equal(400, customErr.Code)
+	suite.mockReviewRepo.AssertNotCalled(suite.T(), "CreateReview", mock.Anything, mock.Anything)
+	suite.mockStorage.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestCreateReview_RemovesPhotosOnRepoError() {
+	ctx := context.Background()
+	req := &entity.CreateReviewRequest{Rating: 5, Photos: []string{"a"}}
+	errConflict := errmsg.NewCustomErrors(409, errmsg.WithMessage("Review sudah ada"))
+
+	suite.mockStorage.On("Save", "a", "./storage/public/reviews").Return("./storage/public/reviews/a.png", nil)
+	suite.mockStorage.On("Remove", "./storage/public/reviews/a.png").Return(nil)
+	suite.mockReviewRepo.On("CreateReview", ctx, req).Return(nil, errConflict)
+	_, err := suite.service.CreateReview(ctx, req)
+
+	suite.Equal(errConflict, err)
+	suite.mockStorage.AssertExpectations(suite.T())
+}
+
+// Testing UpdateReview
+
+func (suite *ServiceList) TestUpdateReview_KeepsPhotosWhenOmitted() {
+	ctx := context.Background()
+	req := &entity.UpdateReviewRequest{Rating: 3}
+
+	suite.mockReviewRepo.On("UpdateReview", ctx, req).Return(&entity.Review{}, nil)
+	_, err := suite.service.UpdateReview(ctx, req)
+
+	suite.Nil(err)
+	suite.Nil(req.PhotoUrls)
+	suite.mockStorage.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestUpdateReview_RemovesPhotosOnRepoError() {
+	ctx := context.Background()
+	req := &entity.UpdateReviewRequest{Rating: 3, Photos: []string{"a"}}
+
+	suite.mockStorage.On("Save", "a", "./storage/public/reviews").Return("./storage/public/reviews/a.png", nil)
+	suite.mockStorage.On("Remove", "./storage/public/reviews/a.png").Return(nil)
+	suite.mockReviewRepo.On("UpdateReview", ctx, req).Return(nil, errors.New("error"))
+	_, err := suite.service.UpdateReview(ctx, req)
+
+	suite.Equal(errors.New("error"), err)
+	suite.mockStorage.AssertExpectations(suite.T())
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceList))
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "codebase-app/pkg/types"
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
 
 type CreateShopRequest struct {
 	UserId string `validate:"uuid" db:"user_id"`
@@ -9,6 +12,9 @@ type CreateShopRequest struct {
 	Description string   `json:"description" validate:"required,max=255" db:"description"`
 	Terms       string   `json:"terms" validate:"required" db:"terms"`
 	CategoryIds []string `json:"category_ids" validate:"required"` // example: ["uuid1", "uuid2"]
+
+	// Currency is the default currency of the products, IDR when empty.
+	Currency string `json:"currency" validate:"omitempty,iso4217" db:"currency"`
 }
 
 type CreateShopResponse struct {
@@ -19,10 +25,38 @@ type GetShopRequest struct {
 	Id string `validate:"uuid" db:"id"`
 }
 
+type DeleteShopResponse struct {
+	Id               string   `json:"id"`
+	DeletedProducts  []string `json:"deleted_products"`   // ids of the products deleted with the shop
+	ReleasedOrderIds []string `json:"released_order_ids"` // orders whose open reservations were released
+}
+
 type GetShopResponse struct {
-	Name        string `json:"name" db:"name"`
-	Description string `json:"description" db:"description"`
-	Terms       string `json:"terms" db:"terms"`
+	Id                 string         `json:"id" db:"id"`
+	UserId             string         `json:"user_id" db:"user_id"`
+	Name               string         `json:"name" db:"name"`
+	Slug               string         `json:"slug" db:"slug"`
+	Description        string         `json:"description" db:"description"`
+	Terms              string         `json:"terms" db:"terms"`
+	Currency           string         `json:"currency" db:"currency"`
+	LogoUrl            *string        `json:"logo_url" db:"logo_url"`
+	BannerUrl          *string        `json:"banner_url" db:"banner_url"`
+	ActiveProductCount int            `json:"active_product_count" db:"active_product_count"`
+	RatingAvg          float64        `json:"rating_avg" db:"rating_avg"`
+	RatingCount        int            `json:"rating_count" db:"rating_count"`
+	FollowerCount      int            `json:"follower_count" db:"follower_count"`
+	Categories         []ShopCategory `json:"categories" db:"-"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	Timezone           string         `json:"timezone" db:"timezone"`
+	OpeningHours       []OpeningHour  `json:"opening_hours" db:"-"`
+	IsOpen             bool           `json:"is_open" db:"-"`
+	HolidayMessage     *string        `json:"holiday_message" db:"-"`
+	Origin             *ShopOrigin    `json:"origin" db:"-"`
+}
+
+type ShopCategory struct {
+	Id   string `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
 }
 
 type DeleteShopRequest struct {
@@ -34,14 +68,26 @@ type DeleteShopRequest struct {
 type UpdateShopRequest struct {
 	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
 
-	Id          string `params:"id" validate:"uuid" db:"id"`
-	Name        string `json:"name" validate:"required" db:"name"`
-	Description string `json:"description" validate:"required" db:"description"`
-	Terms       string `json:"terms" validate:"required" db:"terms"`
+	Id          string  `params:"id" validate:"uuid" db:"id"`
+	Name        string  `json:"name" validate:"required" db:"name"`
+	Description string  `json:"description" validate:"required" db:"description"`
+	Terms       string  `json:"terms" validate:"required" db:"terms"`
+	Logo        *string `json:"logo"`   // base64 encoded jpeg or png, ex: "data:image/png;base64,..."
+	Banner      *string `json:"banner"` // base64 encoded jpeg or png
+
+	// Currency applies to products created afterwards, existing products keep theirs.
+	Currency string `json:"currency" validate:"omitempty,iso4217" db:"currency"`
+
+	// ContactEmail receives the stock alerts when the account of the owner is gone.
+	ContactEmail *string `json:"contact_email" validate:"omitempty,email" db:"contact_email"`
+
+	LogoUrl   *string `db:"logo_url"`
+	BannerUrl *string `db:"banner_url"`
 }
 
 type UpdateShopResponse struct {
-	Id string `json:"id" db:"id"`
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type ShopsRequest struct {
@@ -61,11 +107,201 @@ func (r *ShopsRequest) SetDefault() {
 }
 
 type ShopItem struct {
-	Id   string `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	Id           string  `json:"id" db:"id"`
+	UserId       string  `json:"user_id" db:"user_id"`
+	Name         string  `json:"name" db:"name"`
+	Status       string  `json:"status" db:"status"`
+	StatusReason *string `json:"status_reason" db:"status_reason"`
 }
 
 type ShopsResponse struct {
 	Items []ShopItem `json:"items"`
 	Meta  types.Meta `json:"meta"`
 }
+
+const (
+	StatusDraft         = "draft"
+	StatusPendingReview = "pending_review"
+	StatusActive        = "active"
+	StatusSuspended     = "suspended"
+	StatusClosed        = "closed"
+
+	AggregateShop = "shop"
+
+	EventShopCreated       = "shop.created"
+	EventShopUpdated       = "shop.updated"
+	EventShopStatusChanged = "shop.status_changed"
+	EventShopDeleted       = "shop.deleted"
+	EventShopRestored      = "shop.restored"
+)
+
+// transition is an allowed status change and who may perform it.
+type transition struct {
+	owner bool
+	admin bool
+}
+
+var transitions = map[string]map[string]transition{
+	StatusDraft: {
+		StatusPendingReview: {owner: true},
+		StatusClosed:        {owner: true, admin: true},
+	},
+	StatusPendingReview: {
+		StatusActive: {admin: true},
+		StatusDraft:  {owner: true, admin: true}, // withdrawn by owner or rejected by admin
+	},
+	StatusActive: {
+		StatusSuspended: {admin: true},
+		StatusClosed:    {owner: true, admin: true},
+	},
+	StatusSuspended: {
+		StatusActive: {admin: true},
+		StatusClosed: {admin: true},
+	},
+}
+
+// CanTransition reports whether a shop may move from one status to another,
+// by the owner or by an admin.
+func CanTransition(from, to string, byAdmin bool) bool {
+	t, ok := transitions[from][to]
+	if !ok {
+		return false
+	}
+
+	if byAdmin {
+		return t.admin
+	}
+
+	return t.owner
+}
+
+type ShopStatus struct {
+	Id     string `db:"id"`
+	UserId string `db:"user_id"`
+	Status string `db:"status"`
+}
+
+type UpdateShopStatusRequest struct {
+	UserId  string `prop:"user_id" validate:"uuid"`
+	IsAdmin bool
+
+	Id     string  `params:"id" validate:"uuid"`
+	Status string  `json:"status" validate:"required,oneof=draft pending_review active suspended closed"`
+	Reason *string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+type UpdateShopStatusResponse struct {
+	Id         string  `json:"id"`
+	FromStatus string  `json:"from_status"`
+	Status     string  `json:"status"`
+	Reason     *string `json:"reason"`
+}
+
+type AdminShopsRequest struct {
+	Status   string `query:"status" validate:"omitempty,oneof=draft pending_review active suspended closed"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+}
+
+func (r *AdminShopsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type SearchShopsRequest struct {
+	Query      string  `query:"q" validate:"omitempty,max=255"`
+	CategoryId string  `query:"category_id" validate:"omitempty,uuid"`
+	Near       string  `query:"near"` // "lat,lng"
+	RadiusKm   float64 `query:"radius_km" validate:"omitempty,gt=0,max=500"`
+	Sort       string  `query:"sort" validate:"omitempty,oneof=newest rating followers products distance"`
+	Page       int     `query:"page" validate:"required"`
+	Paginate   int     `query:"paginate" validate:"required,max=100"`
+
+	NearPoint *types.Point
+}
+
+func (r *SearchShopsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+
+	if r.Sort == "" {
+		r.Sort = "newest"
+	}
+
+	if r.Near != "" && r.RadiusKm == 0 {
+		r.RadiusKm = 10
+	}
+}
+
+// CustomValidation parses the near filter, which the validator cannot check.
+func (r *SearchShopsRequest) CustomValidation() (int, map[string][]string) {
+	errors := make(map[string][]string)
+
+	if r.Near != "" {
+		point, err := types.ParseLatLng(r.Near)
+		if err != nil {
+			errors["near"] = append(errors["near"], "near "+err.Error()+".")
+		}
+		r.NearPoint = &point
+	}
+
+	if r.Sort == "distance" && r.Near == "" {
+		errors["sort"] = append(errors["sort"], "sort distance membutuhkan parameter near.")
+	}
+
+	if len(errors) > 0 {
+		return 400, errors
+	}
+
+	return 0, nil
+}
+
+type StorefrontItem struct {
+	Id                 string    `json:"id" db:"id"`
+	Name               string    `json:"name" db:"name"`
+	Slug               string    `json:"slug" db:"slug"`
+	Description        string    `json:"description" db:"description"`
+	LogoUrl            *string   `json:"logo_url" db:"logo_url"`
+	ActiveProductCount int       `json:"active_product_count" db:"active_product_count"`
+	RatingAvg          float64   `json:"rating_avg" db:"rating_avg"`
+	FollowerCount      int       `json:"follower_count" db:"follower_count"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	DistanceKm         *float64  `json:"distance_km" db:"distance_km"`
+	IsOpen             bool      `json:"is_open" db:"-"`
+}
+
+type SearchShopsResponse struct {
+	Items []StorefrontItem `json:"items"`
+	Meta  types.Meta       `json:"meta"`
+}
+
+type GetShopBySlugRequest struct {
+	Slug string `params:"slug" validate:"required,max=255"`
+}
+
+// GetShopBySlugResponse holds either the storefront, or the current slug
+// when the requested one is an old slug of a renamed shop.
+type GetShopBySlugResponse struct {
+	Shop         *GetShopResponse
+	RedirectSlug string
+}
+
+type SlugLookup struct {
+	Id   string `db:"id"`
+	Slug string `db:"slug"`
+}
+
+type SlugRedirectResponse struct {
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to string
+		byAdmin  bool
+		allowed  bool
+	}{
+		{StatusDraft, StatusPendingReview, false, true},
+		{StatusDraft, StatusActive, false, false},
+		{StatusDraft, StatusActive, true, false},
+		{StatusPendingReview, StatusActive, false, false},
+		{StatusPendingReview, StatusActive, true, true},
+		{StatusPendingReview, StatusDraft, true, true},
+		{StatusActive, StatusSuspended, false, false},
+		{StatusActive, StatusSuspended, true, true},
+		{StatusActive, StatusClosed, false, true},
+		{StatusSuspended, StatusActive, false, false},
+		{StatusSuspended, StatusActive, true, true},
+		{StatusClosed, StatusActive, true, false},
+		{StatusActive, StatusActive, true, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.allowed, CanTransition(c.from, c.to, c.byAdmin), "%s -> %s (admin: %v)", c.from, c.to, c.byAdmin)
+	}
+}
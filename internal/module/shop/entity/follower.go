@@ -0,0 +1,36 @@
+package entity
+
+import "codebase-app/pkg/types"
+
+type FollowShopRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId string `params:"id" validate:"uuid"`
+}
+
+type FollowShopResponse struct {
+	ShopId        string `json:"shop_id" db:"id"`
+	Following     bool   `json:"following" db:"-"`
+	FollowerCount int    `json:"follower_count" db:"follower_count"`
+}
+
+type FollowedShopsRequest struct {
+	UserId   string `prop:"user_id" validate:"uuid"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *FollowedShopsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type FollowedShopsResponse struct {
+	Items []StorefrontItem `json:"items"`
+	Meta  types.Meta       `json:"meta"`
+}
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"time"
+	_ "time/tzdata" // shop timezones must resolve on hosts without zoneinfo
+)
+
+const DefaultTimezone = "Asia/Jakarta"
+
+type OpeningHour struct {
+	ShopId    string `json:"-" db:"shop_id"`
+	DayOfWeek int    `json:"day_of_week" db:"day_of_week" validate:"min=0,max=6"`         // 0 = sunday
+	OpensAt   string `json:"opens_at" db:"opens_at" validate:"required,datetime=15:04"`   // ex: "08:00"
+	ClosesAt  string `json:"closes_at" db:"closes_at" validate:"required,datetime=15:04"` // before opens_at when open past midnight
+}
+
+type Holiday struct {
+	Id       string    `json:"id" db:"id"`
+	ShopId   string    `json:"-" db:"shop_id"`
+	StartsAt time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt   time.Time `json:"ends_at" db:"ends_at"`
+	Message  *string   `json:"message" db:"message"`
+}
+
+// Schedule is everything needed to decide whether a shop is open.
+type Schedule struct {
+	ShopId               string        `json:"-" db:"id"`
+	Timezone             string        `json:"timezone" db:"timezone"`
+	HolidayHidesProducts bool          `json:"holiday_hides_products" db:"holiday_hides_products"`
+	OpeningHours         []OpeningHour `json:"opening_hours" db:"-"`
+	Holidays             []Holiday     `json:"holidays" db:"-"`
+}
+
+// ActiveHoliday returns the holiday period covering at, if any.
+func (s *Schedule) ActiveHoliday(at time.Time) *Holiday {
+	for i := range s.Holidays {
+		h := &s.Holidays[i]
+		if !at.Before(h.StartsAt) && at.Before(h.EndsAt) {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// IsOpen reports whether the shop is open at the given instant. A shop
+// without opening hours is open around the clock unless on holiday.
+func (s *Schedule) IsOpen(at time.Time) bool {
+	if s.ActiveHoliday(at) != nil {
+		return false
+	}
+
+	if len(s.OpeningHours) == 0 {
+		return true
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var (
+		local   = at.In(loc)
+		today   = int(local.Weekday())
+		minutes = local.Hour()*60 + local.Minute()
+	)
+
+	for _, h := range s.OpeningHours {
+		opens, ok := clockMinutes(h.OpensAt)
+		if !ok {
+			continue
+		}
+		closes, ok := clockMinutes(h.ClosesAt)
+		if !ok {
+			continue
+		}
+
+		if opens < closes {
+			if h.DayOfWeek == today && minutes >= opens && minutes < closes {
+				return true
+			}
+			continue
+		}
+
+		// open past midnight, the tail belongs to the previous day's slot
+		if h.DayOfWeek == today && minutes >= opens {
+			return true
+		}
+		if h.DayOfWeek == (today+6)%7 && minutes < closes {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAvailable reports whether products of the shop can be bought at the
+// given instant, which is not the case during a holiday when the owner
+// chose to hide products.
+func (s *Schedule) IsAvailable(at time.Time) bool {
+	return !s.HolidayHidesProducts || s.ActiveHoliday(at) == nil
+}
+
+// clockMinutes parses "15:04" or "15:04:05" into minutes since midnight.
+func clockMinutes(clock string) (int, bool) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		t, err := time.Parse(layout, clock)
+		if err == nil {
+			return t.Hour()*60 + t.Minute(), true
+		}
+	}
+
+	return 0, false
+}
+
+type GetShopScheduleRequest struct {
+	Id string `validate:"uuid"`
+}
+
+type GetShopScheduleResponse struct {
+	Schedule
+	IsOpen         bool    `json:"is_open"`
+	HolidayMessage *string `json:"holiday_message"`
+}
+
+type UpdateShopScheduleRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	Id                   string        `params:"id" validate:"uuid"`
+	Timezone             string        `json:"timezone" validate:"required,timezone"`
+	HolidayHidesProducts bool          `json:"holiday_hides_products"`
+	OpeningHours         []OpeningHour `json:"opening_hours" validate:"max=7,dive"`
+}
+
+type CreateShopHolidayRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId   string    `params:"id" validate:"uuid"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+	Message  *string   `json:"message" validate:"omitempty,max=1000"`
+}
+
+type DeleteShopHolidayRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId string `params:"id" validate:"uuid"`
+	Id     string `params:"holiday_id" validate:"uuid"`
+}
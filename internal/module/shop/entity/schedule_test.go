@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func jakarta(t *testing.T, value string) time.Time {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	assert.NoError(t, err)
+
+	at, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	assert.NoError(t, err)
+
+	return at
+}
+
+func TestScheduleIsOpen(t *testing.T) {
+	s := &Schedule{
+		Timezone: "Asia/Jakarta",
+		OpeningHours: []OpeningHour{
+			{DayOfWeek: 1, OpensAt: "08:00", ClosesAt: "17:00"},       // monday
+			{DayOfWeek: 5, OpensAt: "20:00:00", ClosesAt: "02:00:00"}, // friday night
+		},
+	}
+
+	// 2024-09-23 is a monday
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-23 08:00")))
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-23 16:59")))
+	assert.False(t, s.IsOpen(jakarta(t, "2024-09-23 17:00")))
+	assert.False(t, s.IsOpen(jakarta(t, "2024-09-24 10:00")))
+
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-27 23:00")))
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-28 01:59")))
+	assert.False(t, s.IsOpen(jakarta(t, "2024-09-28 02:00")))
+
+	// the same instant in another zone gives the same answer
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-23 09:00").UTC()))
+}
+
+func TestScheduleWithoutHoursIsAlwaysOpen(t *testing.T) {
+	s := &Schedule{Timezone: "Asia/Jakarta"}
+
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-24 03:00")))
+}
+
+func TestScheduleHoliday(t *testing.T) {
+	msg := "Sedang libur"
+	s := &Schedule{
+		Timezone: "Asia/Jakarta",
+		Holidays: []Holiday{
+			{StartsAt: jakarta(t, "2024-09-23 00:00"), EndsAt: jakarta(t, "2024-09-25 00:00"), Message: &msg},
+		},
+	}
+
+	at := jakarta(t, "2024-09-24 12:00")
+	assert.False(t, s.IsOpen(at))
+	assert.Equal(t, &msg, s.ActiveHoliday(at).Message)
+	assert.True(t, s.IsAvailable(at))
+
+	s.HolidayHidesProducts = true
+	assert.False(t, s.IsAvailable(at))
+	assert.True(t, s.IsAvailable(jakarta(t, "2024-09-25 00:00")))
+	assert.True(t, s.IsOpen(jakarta(t, "2024-09-25 00:00")))
+}
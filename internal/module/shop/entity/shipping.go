@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ShopOrigin struct {
+	Address    string       `json:"address" db:"origin_address"`
+	City       string       `json:"city" db:"origin_city"`
+	PostalCode *string      `json:"postal_code" db:"origin_postal_code"`
+	Latitude   float64      `json:"latitude" db:"-"`
+	Longitude  float64      `json:"longitude" db:"-"`
+	Point      *types.Point `json:"-" db:"origin_point"`
+}
+
+type UpdateShopOriginRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	Id         string  `params:"id" validate:"uuid"`
+	Address    string  `json:"address" validate:"required,max=500"`
+	City       string  `json:"city" validate:"required,max=100"`
+	PostalCode *string `json:"postal_code" validate:"omitempty,numeric,max=10"`
+	Latitude   float64 `json:"latitude" validate:"min=-90,max=90"`
+	Longitude  float64 `json:"longitude" validate:"min=-180,max=180"`
+}
+
+type ShippingProfile struct {
+	Id                    string         `json:"id" db:"id"`
+	ShopId                string         `json:"shop_id" db:"shop_id"`
+	Name                  string         `json:"name" db:"name"`
+	Couriers              pq.StringArray `json:"couriers" db:"couriers"`
+	HandlingDays          int            `json:"handling_days" db:"handling_days"`
+	FreeShippingThreshold *types.Money   `json:"free_shipping_threshold" db:"free_shipping_threshold"` // in the currency of the shop
+	IsDefault             bool           `json:"is_default" db:"is_default"`
+	CreatedAt             time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type GetShippingProfilesRequest struct {
+	ShopId string `validate:"uuid"`
+}
+
+type UpsertShippingProfileRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId                string       `params:"id" validate:"uuid"`
+	Id                    string       `params:"profile_id" validate:"omitempty,uuid"` // empty on create
+	Name                  string       `json:"name" validate:"required,max=100"`
+	Couriers              []string     `json:"couriers" validate:"required,min=1,unique,dive,oneof=jne jnt sicepat anteraja pos tiki gosend grabexpress ninja lion wahana"`
+	HandlingDays          int          `json:"handling_days" validate:"min=0,max=30"`
+	FreeShippingThreshold *types.Money `json:"free_shipping_threshold" validate:"omitempty,gt=0"`
+	IsDefault             bool         `json:"is_default"`
+}
+
+type DeleteShippingProfileRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+
+	ShopId string `params:"id" validate:"uuid"`
+	Id     string `params:"profile_id" validate:"uuid"`
+}
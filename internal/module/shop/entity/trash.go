@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type ShopTrashRequest struct {
+	UserId   string `prop:"user_id" validate:"uuid"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *ShopTrashRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type TrashedShop struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Slug      string    `json:"slug" db:"slug"`
+	LogoUrl   *string   `json:"logo_url" db:"logo_url"`
+	DeletedAt time.Time `json:"deleted_at" db:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at" db:"-"`
+	// ProductCount is how many products restoring the shop with its
+	// products brings back, the ones deleted with or after the shop.
+	ProductCount int `json:"product_count" db:"product_count"`
+}
+
+type ShopTrashResponse struct {
+	Items []TrashedShop `json:"items"`
+	Meta  types.Meta    `json:"meta"`
+}
+
+type RestoreShopRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+	Id     string `validate:"uuid" db:"id"`
+
+	// RestoreProducts also restores the products deleted with or after the
+	// shop, products deleted before it stay in the trash.
+	RestoreProducts bool `json:"restore_products"`
+}
+
+type RestoreShopResponse struct {
+	Id               string `json:"id" db:"id"`
+	Name             string `json:"name" db:"name"`
+	Slug             string `json:"slug" db:"slug"`
+	RestoredProducts int    `json:"restored_products" db:"-"`
+}
+
+// PurgeResult tells what a purge removed. Images are the urls of the stored
+// images of the removed shops, to be deleted once the rows are gone.
+type PurgeResult struct {
+	Shops  int      `json:"shops"`
+	Images []string `json:"images"`
+}
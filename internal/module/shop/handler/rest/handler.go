@@ -2,6 +2,7 @@ package handler
 
 import (
 	"codebase-app/internal/adapter"
+	integStorage "codebase-app/internal/integration/localstorage"
 	"codebase-app/internal/middleware"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
@@ -22,7 +23,7 @@ func NewShopHandler() *shopHandler {
 	var (
 		handler = new(shopHandler)
 		repo    = repository.NewShopRepository(adapter.Adapters.ShopeefunPostgres)
-		service = service.NewShopService(repo)
+		service = service.NewShopService(repo, integStorage.NewLocalStorageIntegration())
 	)
 	handler.service = service
 
@@ -32,9 +33,29 @@ func NewShopHandler() *shopHandler {
 func (h *shopHandler) Register(router fiber.Router) {
 	router.Get("/shops", middleware.UserIdHeader, h.GetShops)
 	router.Post("/shops", middleware.UserIdHeader, h.CreateShop)
+	router.Get("/shops/search", h.SearchShops)
+	router.Get("/shops/following", middleware.AuthBearer, h.GetFollowedShops)
+	router.Get("/shops/trash", middleware.UserIdHeader, h.GetShopTrash)
 	router.Get("/shops/:id", h.GetShop)
+	router.Get("/shops/slug/:slug", h.GetShopBySlug)
 	router.Delete("/shops/:id", middleware.UserIdHeader, h.DeleteShop)
+	router.Post("/shops/:id/restore", middleware.UserIdHeader, h.RestoreShop)
 	router.Patch("/shops/:id", middleware.UserIdHeader, h.UpdateShop)
+	router.Patch("/shops/:id/status", middleware.UserIdHeader, h.UpdateShopStatus)
+	router.Get("/shops/:id/schedule", h.GetShopSchedule)
+	router.Put("/shops/:id/schedule", middleware.UserIdHeader, h.UpdateShopSchedule)
+	router.Post("/shops/:id/holidays", middleware.UserIdHeader, h.CreateShopHoliday)
+	router.Delete("/shops/:id/holidays/:holiday_id", middleware.UserIdHeader, h.DeleteShopHoliday)
+	router.Put("/shops/:id/origin", middleware.UserIdHeader, h.UpdateShopOrigin)
+	router.Post("/shops/:id/follow", middleware.AuthBearer, h.FollowShop)
+	router.Delete("/shops/:id/follow", middleware.AuthBearer, h.UnfollowShop)
+	router.Get("/shops/:id/shipping-profiles", h.GetShippingProfiles)
+	router.Post("/shops/:id/shipping-profiles", middleware.UserIdHeader, h.UpsertShippingProfile)
+	router.Put("/shops/:id/shipping-profiles/:profile_id", middleware.UserIdHeader, h.UpsertShippingProfile)
+	router.Delete("/shops/:id/shipping-profiles/:profile_id", middleware.UserIdHeader, h.DeleteShippingProfile)
+
+	router.Get("/admin/shops", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.GetAdminShops)
+	router.Patch("/admin/shops/:id/status", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.ModerateShop)
 }
 
 func (h *shopHandler) CreateShop(c *fiber.Ctx) error {
@@ -108,13 +129,13 @@ func (h *shopHandler) DeleteShop(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	err := h.service.DeleteShop(ctx, req)
+	resp, err := h.service.DeleteShop(ctx, req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
 func (h *shopHandler) UpdateShop(c *fiber.Ctx) error {
@@ -179,3 +200,506 @@ func (h *shopHandler) GetShops(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 
 }
+
+func (h *shopHandler) UpdateShopStatus(c *fiber.Ctx) error {
+	return h.updateShopStatus(c, false)
+}
+
+func (h *shopHandler) ModerateShop(c *fiber.Ctx) error {
+	return h.updateShopStatus(c, true)
+}
+
+func (h *shopHandler) updateShopStatus(c *fiber.Ctx, isAdmin bool) error {
+	var (
+		req = new(entity.UpdateShopStatusRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::UpdateShopStatus - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.IsAdmin = isAdmin
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateShopStatus - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateShopStatus(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetAdminShops(c *fiber.Ctx) error {
+	var (
+		req = new(entity.AdminShopsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetAdminShops - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetAdminShops - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetAdminShops(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) SearchShops(c *fiber.Ctx) error {
+	var (
+		req = new(entity.SearchShopsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::SearchShops - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if code, errs := req.CustomValidation(); code != 0 {
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::SearchShops - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.SearchShops(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopBySlug(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopBySlugRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Slug = c.Params("slug")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShopBySlug - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopBySlug(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if resp.RedirectSlug != "" {
+		location := "/products/shops/slug/" + resp.RedirectSlug
+		c.Location(location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(response.Success(entity.SlugRedirectResponse{
+			Slug:     resp.RedirectSlug,
+			Location: location,
+		}, "Shop telah dipindahkan"))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp.Shop, ""))
+}
+
+func (h *shopHandler) GetShopSchedule(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopScheduleRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShopSchedule - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopSchedule(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) UpdateShopSchedule(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateShopScheduleRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::UpdateShopSchedule - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateShopSchedule - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateShopSchedule(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) CreateShopHoliday(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateShopHolidayRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::CreateShopHoliday - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateShopHoliday - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateShopHoliday(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) DeleteShopHoliday(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteShopHolidayRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("holiday_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteShopHoliday - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteShopHoliday(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) UpdateShopOrigin(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateShopOriginRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::UpdateShopOrigin - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateShopOrigin - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateShopOrigin(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShippingProfiles(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShippingProfilesRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShippingProfiles - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShippingProfiles(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) UpsertShippingProfile(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpsertShippingProfileRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::UpsertShippingProfile - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("profile_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::UpsertShippingProfile - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpsertShippingProfile(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	status := fiber.StatusOK
+	if req.Id == "" {
+		status = fiber.StatusCreated
+	}
+
+	return c.Status(status).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) DeleteShippingProfile(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteShippingProfileRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("profile_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteShippingProfile - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteShippingProfile(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *shopHandler) FollowShop(c *fiber.Ctx) error {
+	return h.follow(c, true)
+}
+
+func (h *shopHandler) UnfollowShop(c *fiber.Ctx) error {
+	return h.follow(c, false)
+}
+
+func (h *shopHandler) follow(c *fiber.Ctx, follow bool) error {
+	var (
+		req = new(entity.FollowShopRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::FollowShop - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	var (
+		resp *entity.FollowShopResponse
+		err  error
+	)
+	if follow {
+		resp, err = h.service.FollowShop(ctx, req)
+	} else {
+		resp, err = h.service.UnfollowShop(ctx, req)
+	}
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetFollowedShops(c *fiber.Ctx) error {
+	var (
+		req = new(entity.FollowedShopsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetFollowedShops - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetFollowedShops - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetFollowedShops(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetShopTrash(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ShopTrashRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetShopTrash - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShopTrash - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopTrash(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) RestoreShop(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RestoreShopRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	// the body is optional, a shop is restored without its products by default
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			log.Warn().Err(err).Msg("handler::RestoreShop - Parse request body")
+			return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+		}
+	}
+
+	req.UserId = l.UserId
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::RestoreShop - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.RestoreShop(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
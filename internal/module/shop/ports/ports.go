@@ -3,20 +3,59 @@ package ports
 import (
 	"codebase-app/internal/module/shop/entity"
 	"context"
+	"time"
 )
 
 type ShopRepository interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error)
 	GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error)
-	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
+	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) (*entity.DeleteShopResponse, error)
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error)
 	GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error)
+	GetShopStatus(ctx context.Context, id string) (*entity.ShopStatus, error)
+	UpdateShopStatus(ctx context.Context, req *entity.UpdateShopStatusRequest, fromStatus string) error
+	GetAdminShops(ctx context.Context, req *entity.AdminShopsRequest) (*entity.ShopsResponse, error)
+	SearchShops(ctx context.Context, req *entity.SearchShopsRequest) (*entity.SearchShopsResponse, error)
+	ResolveShopSlug(ctx context.Context, slug string) (*entity.SlugLookup, error)
+	GetShopSchedules(ctx context.Context, ids []string) (map[string]*entity.Schedule, error)
+	UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) error
+	CreateShopHoliday(ctx context.Context, req *entity.CreateShopHolidayRequest) (*entity.Holiday, error)
+	DeleteShopHoliday(ctx context.Context, req *entity.DeleteShopHolidayRequest) error
+	GetShopOrigin(ctx context.Context, id string) (*entity.ShopOrigin, error)
+	UpdateShopOrigin(ctx context.Context, req *entity.UpdateShopOriginRequest) error
+	GetShippingProfiles(ctx context.Context, req *entity.GetShippingProfilesRequest) ([]entity.ShippingProfile, error)
+	UpsertShippingProfile(ctx context.Context, req *entity.UpsertShippingProfileRequest) (*entity.ShippingProfile, error)
+	DeleteShippingProfile(ctx context.Context, req *entity.DeleteShippingProfileRequest) error
+	FollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error)
+	UnfollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error)
+	GetFollowedShops(ctx context.Context, req *entity.FollowedShopsRequest) (*entity.FollowedShopsResponse, error)
+	GetShopTrash(ctx context.Context, req *entity.ShopTrashRequest, since time.Time) (*entity.ShopTrashResponse, error)
+	RestoreShop(ctx context.Context, req *entity.RestoreShopRequest, since time.Time) (*entity.RestoreShopResponse, error)
+	PurgeShops(ctx context.Context, before time.Time, limit int) (*entity.PurgeResult, error)
 }
 
 type ShopService interface {
 	CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error)
 	GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error)
-	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error
+	DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) (*entity.DeleteShopResponse, error)
 	UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error)
 	GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error)
+	UpdateShopStatus(ctx context.Context, req *entity.UpdateShopStatusRequest) (*entity.UpdateShopStatusResponse, error)
+	GetAdminShops(ctx context.Context, req *entity.AdminShopsRequest) (*entity.ShopsResponse, error)
+	SearchShops(ctx context.Context, req *entity.SearchShopsRequest) (*entity.SearchShopsResponse, error)
+	GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error)
+	GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.GetShopScheduleResponse, error)
+	UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) (*entity.GetShopScheduleResponse, error)
+	CreateShopHoliday(ctx context.Context, req *entity.CreateShopHolidayRequest) (*entity.Holiday, error)
+	DeleteShopHoliday(ctx context.Context, req *entity.DeleteShopHolidayRequest) error
+	UpdateShopOrigin(ctx context.Context, req *entity.UpdateShopOriginRequest) (*entity.ShopOrigin, error)
+	GetShippingProfiles(ctx context.Context, req *entity.GetShippingProfilesRequest) ([]entity.ShippingProfile, error)
+	UpsertShippingProfile(ctx context.Context, req *entity.UpsertShippingProfileRequest) (*entity.ShippingProfile, error)
+	DeleteShippingProfile(ctx context.Context, req *entity.DeleteShippingProfileRequest) error
+	FollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error)
+	UnfollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error)
+	GetFollowedShops(ctx context.Context, req *entity.FollowedShopsRequest) (*entity.FollowedShopsResponse, error)
+	GetShopTrash(ctx context.Context, req *entity.ShopTrashRequest) (*entity.ShopTrashResponse, error)
+	RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) (*entity.RestoreShopResponse, error)
+	PurgeShops(ctx context.Context, now time.Time) (*entity.PurgeResult, error)
 }
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	outboxRepo "codebase-app/internal/module/outbox/repository"
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/event"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// recordEvent writes a shop event to the outbox in the transaction of the
+// change it describes.
+func recordEvent(ctx context.Context, tx sqlx.ExecerContext, eventType, shopId string, payload any) error {
+	return outboxRepo.Record(ctx, tx, event.New(eventType, entity.AggregateShop, shopId, payload))
+}
+
+// recordProductEvents writes a product event for each product deleted or
+// restored together with their shop.
+func recordProductEvents(ctx context.Context, tx sqlx.ExecerContext, eventType, shopId string, productIds []string) error {
+	events := make([]event.Event, 0, len(productIds))
+	for _, id := range productIds {
+		events = append(events, event.New(eventType, productEnt.AggregateProduct, id, productEnt.ProductRef{
+			Id:     id,
+			ShopId: shopId,
+		}))
+	}
+
+	return outboxRepo.Record(ctx, tx, events...)
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) FollowShop(ctx context.Context, req *entity.FollowShopRequest) (resp *entity.FollowShopResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::FollowShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::FollowShop - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::FollowShop - Failed to commit transaction")
+		}
+	}()
+
+	var exists bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM shops
+			WHERE id = ? AND status = 'active' AND deleted_at IS NULL
+		)
+	`
+
+	if err = tx.GetContext(ctx, &exists, r.db.Rebind(query), req.ShopId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::FollowShop - Failed to check shop")
+		return nil, err
+	}
+
+	if !exists {
+		log.Warn().Any("payload", req).Msg("repository::FollowShop - Shop not found")
+		err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO shop_followers (user_id, shop_id)
+		VALUES (?, ?)
+		ON CONFLICT (user_id, shop_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, r.db.Rebind(query), req.UserId, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::FollowShop - Failed to follow shop")
+		return nil, err
+	}
+
+	// the counter only moves when a row was actually inserted, so following
+	// twice is idempotent
+	inserted, _ := result.RowsAffected()
+
+	resp = &entity.FollowShopResponse{Following: true}
+
+	query = `
+		UPDATE shops
+		SET follower_count = follower_count + ?
+		WHERE id = ?
+		RETURNING id, follower_count
+	`
+
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), inserted, req.ShopId).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::FollowShop - Failed to update follower count")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UnfollowShop(ctx context.Context, req *entity.FollowShopRequest) (resp *entity.FollowShopResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UnfollowShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UnfollowShop - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UnfollowShop - Failed to commit transaction")
+		}
+	}()
+
+	query := `DELETE FROM shop_followers WHERE user_id = ? AND shop_id = ?`
+
+	result, err := tx.ExecContext(ctx, r.db.Rebind(query), req.UserId, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UnfollowShop - Failed to unfollow shop")
+		return nil, err
+	}
+
+	deleted, _ := result.RowsAffected()
+
+	resp = &entity.FollowShopResponse{Following: false}
+
+	query = `
+		UPDATE shops
+		SET follower_count = GREATEST(follower_count - ?, 0)
+		WHERE id = ?
+		RETURNING id, follower_count
+	`
+
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), deleted, req.ShopId).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UnfollowShop - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+			return nil, err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UnfollowShop - Failed to update follower count")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetFollowedShops(ctx context.Context, req *entity.FollowedShopsRequest) (*entity.FollowedShopsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.StorefrontItem
+	}
+
+	var (
+		resp = new(entity.FollowedShopsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.StorefrontItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(s.id) OVER() as total_data,
+			s.id,
+			s.name,
+			s.slug,
+			s.description,
+			s.logo_url,
+			s.rating_avg,
+			s.follower_count,
+			s.created_at,
+			(
+				SELECT COUNT(*)
+				FROM products p
+				WHERE p.shop_id = s.id AND p.deleted_at IS NULL
+			) AS active_product_count
+		FROM shop_followers f
+		JOIN shops s ON s.id = f.shop_id
+		WHERE
+			f.user_id = ?
+			AND s.deleted_at IS NULL
+			AND s.status = 'active'
+		ORDER BY f.created_at DESC, s.id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetFollowedShops - Failed to get followed shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.StorefrontItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
@@ -1,11 +1,22 @@
 package repository
 
 import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	notifEnt "codebase-app/internal/module/notification/entity"
+	notifRepo "codebase-app/internal/module/notification/repository"
+	productEnt "codebase-app/internal/module/product/entity"
+	productRepo "codebase-app/internal/module/product/repository"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,6 +32,116 @@ func NewShopRepository(db *sqlx.DB) *shopRepository {
 	}
 }
 
+// shopSnapshot is the state of a shop recorded in the audit log.
+type shopSnapshot struct {
+	Id           string    `json:"id" db:"id"`
+	UserId       string    `json:"user_id" db:"user_id"`
+	Name         string    `json:"name" db:"name"`
+	Slug         string    `json:"slug" db:"slug"`
+	Description  string    `json:"description" db:"description"`
+	Terms        string    `json:"terms" db:"terms"`
+	Currency     string    `json:"currency" db:"currency"`
+	LogoUrl      *string   `json:"logo_url" db:"logo_url"`
+	BannerUrl    *string   `json:"banner_url" db:"banner_url"`
+	ContactEmail *string   `json:"contact_email" db:"contact_email"`
+	CategoryIds  []string  `json:"category_ids,omitempty" db:"-"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (r *shopRepository) getShopForUpdate(ctx context.Context, tx *sqlx.Tx, id, userId string) (*shopSnapshot, error) {
+	var snapshot = new(shopSnapshot)
+
+	query := `
+		SELECT id, user_id, name, slug, description, terms, currency, logo_url, banner_url, contact_email, created_at, updated_at
+		FROM shops
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.QueryRowxContext(ctx, r.db.Rebind(query), id, userId).StructScan(snapshot)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::getShopForUpdate - Failed to get shop")
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// newShopSlug returns a unique slug generated from name. Slugs currently or
+// previously used by shopId are not considered taken, so a shop renamed back
+// gets its old slug again. Slugs in clashed are considered taken.
+func (r *shopRepository) newShopSlug(ctx context.Context, tx *sqlx.Tx, name, shopId string, clashed ...string) (string, error) {
+	var (
+		base  = pkg.Slugify(name)
+		taken = make([]string, 0)
+	)
+
+	if base == "" {
+		base = "shop"
+	}
+
+	query := `
+		SELECT slug FROM shops
+		WHERE (slug = ? OR slug LIKE ? || '-%') AND id::TEXT <> ?
+		UNION
+		SELECT slug FROM shop_slug_histories
+		WHERE (slug = ? OR slug LIKE ? || '-%') AND shop_id::TEXT <> ?
+	`
+
+	err := tx.SelectContext(ctx, &taken, r.db.Rebind(query), base, base, shopId, base, base, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("name", name).Msg("repository::newShopSlug - Failed to get taken slugs")
+		return "", err
+	}
+
+	return pkg.UniqueSlug(base, append(taken, clashed...)), nil
+}
+
+// slugRetries is how many times a slug taken by a concurrent transaction is
+// replaced by the next one before giving up.
+const slugRetries = 5
+
+// withShopSlug generates a unique slug from name and writes it with write. The
+// write runs in a savepoint, so a slug committed meanwhile by a concurrent
+// transaction is retried with the next suffix instead of failing the request.
+func (r *shopRepository) withShopSlug(ctx context.Context, tx *sqlx.Tx, name, shopId string, write func(slug string) error) (string, error) {
+	clashed := make([]string, 0)
+
+	for {
+		slug, err := r.newShopSlug(ctx, tx, name, shopId, clashed...)
+		if err != nil {
+			return "", err
+		}
+
+		if _, err = tx.ExecContext(ctx, `SAVEPOINT shop_slug`); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("repository::withShopSlug - Failed to create savepoint")
+			return "", err
+		}
+
+		err = write(slug)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT shop_slug`)
+			return slug, err
+		}
+
+		pqErr, ok := err.(*pq.Error)
+		if !ok || pqErr.Code != "23505" || pqErr.Constraint != "uq_shops_slug" || len(clashed) == slugRetries {
+			return "", err
+		}
+
+		log.Warn().Str("slug", slug).Msg("repository::withShopSlug - Slug taken concurrently, retrying")
+		if _, err = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT shop_slug`); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("repository::withShopSlug - Failed to rollback to savepoint")
+			return "", err
+		}
+		clashed = append(clashed, slug)
+	}
+}
+
 func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error) {
 	var resp = new(entity.CreateShopResponse)
 	// shop_categories -> shop_id, category_id -> categories dan shops
@@ -41,16 +162,23 @@ func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopR
 		}
 	}()
 
+	var after = new(shopSnapshot)
+
 	query := `
-		INSERT INTO shops (user_id, name, description, terms)
-		VALUES (?, ?, ?, ?) RETURNING id
+		INSERT INTO shops (user_id, name, slug, description, terms, currency)
+		VALUES (?, ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'IDR'))
+		RETURNING id, user_id, name, slug, description, terms, currency, logo_url, banner_url, contact_email, created_at, updated_at
 	`
 
-	err = tx.QueryRowContext(ctx, r.db.Rebind(query),
-		req.UserId,
-		req.Name,
-		req.Description,
-		req.Terms).Scan(&resp.Id)
+	_, err = r.withShopSlug(ctx, tx, req.Name, "", func(slug string) error {
+		return tx.QueryRowxContext(ctx, r.db.Rebind(query),
+			req.UserId,
+			req.Name,
+			slug,
+			req.Description,
+			req.Terms,
+			req.Currency).StructScan(after)
+	})
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShop - Failed to create shop")
 		return nil, err
@@ -61,6 +189,8 @@ func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopR
 		VALUES (?, ?)
 	`
 
+	resp.Id = after.Id
+
 	for _, categoryId := range req.CategoryIds {
 		_, err = tx.ExecContext(ctx, r.db.Rebind(query), resp.Id, categoryId)
 		if err != nil {
@@ -69,65 +199,365 @@ func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopR
 		}
 	}
 
+	after.CategoryIds = req.CategoryIds
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionCreate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   resp.Id,
+		After:      after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventShopCreated, resp.Id, after); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
 func (r *shopRepository) GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error) {
 	var resp = new(entity.GetShopResponse)
-	// Your code here
+
+	// only active shops have a public storefront
 	query := `
-		SELECT name, description, terms
-		FROM shops
-		WHERE id = ?
+		SELECT
+			s.id,
+			s.user_id,
+			s.name,
+			s.slug,
+			s.description,
+			s.terms,
+			s.currency,
+			s.logo_url,
+			s.banner_url,
+			s.rating_avg,
+			s.rating_count,
+			s.follower_count,
+			s.created_at,
+			s.timezone,
+			(
+				SELECT COUNT(*)
+				FROM products p
+				WHERE p.shop_id = s.id AND p.deleted_at IS NULL
+			) AS active_product_count
+		FROM shops s
+		WHERE
+			s.id = ?
+			AND s.deleted_at IS NULL
+			AND s.status = 'active'
 	`
 
 	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::GetShop - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
 		log.Error().Err(err).Any("payload", req).Msg("repository::GetShop - Failed to get shop")
 		return nil, err
 	}
 
+	resp.Categories = make([]entity.ShopCategory, 0)
+
+	query = `
+		SELECT pc.id, pc.name
+		FROM shop_categories sc
+		JOIN product_categories pc ON pc.id = sc.category_id
+		WHERE sc.shop_id = ? AND pc.deleted_at IS NULL
+		ORDER BY pc.name ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Categories, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetShop - Failed to get shop categories")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// DeleteShop soft deletes a shop of the user with its live products, which
+// get the same deleted_at so a restore can bring them back together. The open
+// stock reservations of its products are released.
+func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) (resp *entity.DeleteShopResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::DeleteShop - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::DeleteShop - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.getShopForUpdate(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::DeleteShop - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return nil, err
+	}
+
+	resp, err = deleteShop(ctx, tx, before)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to delete shop")
+		return nil, err
+	}
+
 	return resp, nil
 }
 
-func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error {
+// DeleteUserShops deletes every live shop of a user within tx, the way
+// DeleteShop does, ex: when their account is deleted.
+func DeleteUserShops(ctx context.Context, tx *sqlx.Tx, userId string) ([]*entity.DeleteShopResponse, error) {
+	var (
+		shops = make([]*shopSnapshot, 0)
+		res   = make([]*entity.DeleteShopResponse, 0)
+	)
+
+	query := `
+		SELECT id, user_id, name, slug, description, terms, currency, logo_url, banner_url, contact_email, created_at, updated_at
+		FROM shops
+		WHERE user_id = ? AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	if err := tx.SelectContext(ctx, &shops, tx.Rebind(query), userId); err != nil {
+		log.Error().Err(err).Str("user_id", userId).Msg("repository::DeleteUserShops - Failed to get shops")
+		return nil, err
+	}
+
+	for _, before := range shops {
+		resp, err := deleteShop(ctx, tx, before)
+		if err != nil {
+			log.Error().Err(err).Str("shop_id", before.Id).Msg("repository::DeleteUserShops - Failed to delete shop")
+			return nil, err
+		}
+
+		res = append(res, resp)
+	}
+
+	return res, nil
+}
+
+// deleteShop soft deletes a shop locked by tx with its live products,
+// releases the open stock reservations of its products and records their
+// events.
+func deleteShop(ctx context.Context, tx *sqlx.Tx, before *shopSnapshot) (*entity.DeleteShopResponse, error) {
+	var (
+		err  error
+		resp = &entity.DeleteShopResponse{
+			Id:               before.Id,
+			DeletedProducts:  make([]string, 0),
+			ReleasedOrderIds: make([]string, 0),
+		}
+	)
+
 	query := `
 		UPDATE shops
 		SET deleted_at = NOW()
-		WHERE id = ? AND user_id = ?
+		WHERE id = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	if _, err = tx.ExecContext(ctx, tx.Rebind(query), before.Id); err != nil {
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionDelete,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   before.Id,
+		Before:     before,
+	})
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShop - Failed to delete shop")
-		return err
+		return nil, err
 	}
 
-	return nil
+	// products are locked in id order before their reservations, like a stock
+	// reservation or release does, so none of them can deadlock. NOW() is the
+	// same for the whole transaction.
+	query = `
+		UPDATE products
+		SET deleted_at = NOW()
+		WHERE id IN (
+			SELECT id FROM products
+			WHERE shop_id = ? AND deleted_at IS NULL
+			ORDER BY id
+			FOR UPDATE
+		)
+		RETURNING id
+	`
+
+	if err = tx.SelectContext(ctx, &resp.DeletedProducts, tx.Rebind(query), before.Id); err != nil {
+		return nil, err
+	}
+
+	for _, id := range resp.DeletedProducts {
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionDelete,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the reserved quantities go back to the stock, so a restored product
+	// comes back with the stock it would have had
+	resp.ReleasedOrderIds, err = productRepo.ReleaseShopReservations(ctx, tx, before.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordProductEvents(ctx, tx, productEnt.EventProductDeleted, before.Id, resp.DeletedProducts); err != nil {
+		return nil, err
+	}
+
+	// downstream services learn from it which products and reservations went
+	// away with the shop
+	err = recordEvent(ctx, tx, entity.EventShopDeleted, before.Id, map[string]any{
+		"shop_id":            before.Id,
+		"owner_id":           before.UserId,
+		"product_ids":        resp.DeletedProducts,
+		"released_order_ids": resp.ReleasedOrderIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
-func (r *shopRepository) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error) {
-	var resp = new(entity.UpdateShopResponse)
+func (r *shopRepository) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (resp *entity.UpdateShopResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpdateShop - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpdateShop - Failed to commit transaction")
+		}
+	}()
+
+	before, err := r.getShopForUpdate(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpdateShop - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return nil, err
+	}
+
+	slug := before.Slug
+	if req.Name != before.Name {
+		// the slug is claimed right away, it is written again with the rest of
+		// the shop below
+		slug, err = r.withShopSlug(ctx, tx, req.Name, req.Id, func(slug string) error {
+			if slug == before.Slug {
+				return nil
+			}
+
+			_, err := tx.ExecContext(ctx, r.db.Rebind(`UPDATE shops SET slug = ? WHERE id = ?`), slug, req.Id)
+			return err
+		})
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to update slug")
+			return nil, err
+		}
+	}
+
+	if slug != before.Slug {
+		// keep the old slug resolvable so existing links redirect
+		query := `
+			INSERT INTO shop_slug_histories (slug, shop_id)
+			VALUES (?, ?)
+			ON CONFLICT (slug) DO NOTHING
+		`
+
+		_, err = tx.ExecContext(ctx, r.db.Rebind(query), before.Slug, req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to insert slug history")
+			return nil, err
+		}
+
+		query = `DELETE FROM shop_slug_histories WHERE slug = ? AND shop_id = ?`
+
+		_, err = tx.ExecContext(ctx, r.db.Rebind(query), slug, req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to delete slug history")
+			return nil, err
+		}
+	}
+
+	var after = new(shopSnapshot)
 
 	query := `
 		UPDATE shops
-		SET name = ?, description = ?, terms = ?, updated_at = NOW()
+		SET
+			name = ?,
+			slug = ?,
+			description = ?,
+			terms = ?,
+			currency = COALESCE(NULLIF(?, ''), currency),
+			logo_url = COALESCE(?, logo_url),
+			banner_url = COALESCE(?, banner_url),
+			contact_email = COALESCE(?, contact_email),
+			updated_at = NOW()
 		WHERE id = ? AND user_id = ?
-		RETURNING id
+		RETURNING id, user_id, name, slug, description, terms, currency, logo_url, banner_url, contact_email, created_at, updated_at
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query),
 		req.Name,
+		slug,
 		req.Description,
 		req.Terms,
+		req.Currency,
+		req.LogoUrl,
+		req.BannerUrl,
+		req.ContactEmail,
 		req.Id,
-		req.UserId).Scan(&resp.Id)
+		req.UserId).StructScan(after)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to update shop")
 		return nil, err
 	}
 
-	return resp, nil
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   after.Id,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventShopUpdated, after.Id, after); err != nil {
+		return nil, err
+	}
+
+	return &entity.UpdateShopResponse{Id: after.Id, Slug: after.Slug}, nil
 }
 
 func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error) {
@@ -146,7 +576,10 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 		SELECT
 			COUNT(id) OVER() as total_data,
 			id,
-			name
+			user_id,
+			name,
+			status,
+			status_reason
 		FROM shops
 		WHERE
 			deleted_at IS NULL
@@ -176,3 +609,305 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 
 	return resp, nil
 }
+
+func (r *shopRepository) GetShopStatus(ctx context.Context, id string) (*entity.ShopStatus, error) {
+	var resp = new(entity.ShopStatus)
+
+	query := `
+		SELECT id, user_id, status
+		FROM shops
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::GetShopStatus - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::GetShopStatus - Failed to get shop status")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateShopStatus(ctx context.Context, req *entity.UpdateShopStatusRequest, fromStatus string) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopStatus - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpdateShopStatus - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpdateShopStatus - Failed to commit transaction")
+		}
+	}()
+
+	// the status guard makes concurrent transitions from the same state fail
+	query := `
+		UPDATE shops
+		SET status = ?, status_reason = ?, updated_at = NOW()
+		WHERE id = ? AND status = ? AND deleted_at IS NULL
+		RETURNING user_id, name
+	`
+
+	var shop struct {
+		OwnerId string `db:"user_id"`
+		Name    string `db:"name"`
+	}
+
+	err = tx.GetContext(ctx, &shop, r.db.Rebind(query), req.Status, req.Reason, req.Id, fromStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpdateShopStatus - Shop status changed concurrently")
+			err = errmsg.NewCustomErrors(409, errmsg.WithMessage("Status shop telah berubah, silakan coba lagi"))
+			return err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopStatus - Failed to update shop status")
+		return err
+	}
+
+	query = `
+		INSERT INTO shop_status_histories (shop_id, from_status, to_status, reason, actor_id)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Id, fromStatus, req.Status, req.Reason, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopStatus - Failed to insert status history")
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.Id,
+		Before:     map[string]any{"status": fromStatus},
+		After:      map[string]any{"status": req.Status, "status_reason": req.Reason},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = recordEvent(ctx, tx, entity.EventShopStatusChanged, req.Id, map[string]any{
+		"shop_id":     req.Id,
+		"owner_id":    shop.OwnerId,
+		"from_status": fromStatus,
+		"to_status":   req.Status,
+		"reason":      req.Reason,
+		"by_admin":    req.IsAdmin,
+	})
+	if err != nil {
+		return err
+	}
+
+	// a moderation decision is also pushed to the owner in-app
+	if req.IsAdmin {
+		body := "Toko " + shop.Name + " kini berstatus " + req.Status + "."
+		if req.Reason != nil {
+			body += " Alasan: " + *req.Reason
+		}
+
+		err = notifRepo.Record(ctx, tx, notifEnt.New(shop.OwnerId, notifEnt.TypeShopStatusChanged, "Status toko diperbarui", body, map[string]any{
+			"shop_id":     req.Id,
+			"from_status": fromStatus,
+			"to_status":   req.Status,
+			"reason":      req.Reason,
+		}))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *shopRepository) GetAdminShops(ctx context.Context, req *entity.AdminShopsRequest) (*entity.ShopsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ShopItem
+	}
+
+	var (
+		resp = new(entity.ShopsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.ShopItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			user_id,
+			name,
+			status,
+			status_reason
+		FROM shops
+		WHERE
+			deleted_at IS NULL
+			AND (? = '' OR status = ?)
+		ORDER BY updated_at ASC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.Status,
+		req.Status,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetAdminShops - Failed to get shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.ShopItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) SearchShops(ctx context.Context, req *entity.SearchShopsRequest) (*entity.SearchShopsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.StorefrontItem
+	}
+
+	var (
+		resp = new(entity.SearchShopsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.StorefrontItem, 0, req.Paginate)
+
+	var (
+		arg          = make(map[string]any)
+		distanceExpr = "CAST(NULL AS FLOAT8)"
+		filters      string
+	)
+
+	if req.Query != "" {
+		filters += ` AND LOWER(s.name) LIKE '%' || LOWER(:query) || '%' ESCAPE '\'`
+		arg["query"] = pkg.EscapeLike(req.Query)
+	}
+
+	if req.CategoryId != "" {
+		filters += " AND EXISTS (SELECT 1 FROM shop_categories sc WHERE sc.shop_id = s.id AND sc.category_id = :category_id)"
+		arg["category_id"] = req.CategoryId
+	}
+
+	if req.NearPoint != nil {
+		// ST_DWithin on geography uses the gist index and meters
+		origin := "CAST(ST_SetSRID(ST_MakePoint(:near_lng, :near_lat), 4326) AS GEOGRAPHY)"
+		distanceExpr = "ST_Distance(s.origin_point, " + origin + ") / 1000"
+		filters += " AND ST_DWithin(s.origin_point, " + origin + ", :radius_m)"
+		arg["near_lng"] = req.NearPoint.Lng()
+		arg["near_lat"] = req.NearPoint.Lat()
+		arg["radius_m"] = req.RadiusKm * 1000
+	}
+
+	orderBy := "s.created_at DESC"
+	switch req.Sort {
+	case "rating":
+		orderBy = "s.rating_avg DESC, s.rating_count DESC"
+	case "followers":
+		orderBy = "s.follower_count DESC"
+	case "products":
+		orderBy = "active_product_count DESC"
+	case "distance":
+		orderBy = "distance_km ASC"
+	}
+
+	query := `
+		SELECT
+			COUNT(s.id) OVER() as total_data,
+			s.id,
+			s.name,
+			s.slug,
+			s.description,
+			s.logo_url,
+			s.rating_avg,
+			s.follower_count,
+			s.created_at,
+			` + distanceExpr + ` AS distance_km,
+			(
+				SELECT COUNT(*)
+				FROM products p
+				WHERE p.shop_id = s.id AND p.deleted_at IS NULL
+			) AS active_product_count
+		FROM shops s
+		WHERE
+			s.deleted_at IS NULL
+			AND s.status = 'active'
+			` + filters + `
+		ORDER BY ` + orderBy + `, s.id ASC
+		LIMIT :limit OFFSET :offset
+	`
+	arg["limit"] = req.Paginate
+	arg["offset"] = req.Paginate * (req.Page - 1)
+
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SearchShops - Failed to bind query")
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SearchShops - Failed to search shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.StorefrontItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) ResolveShopSlug(ctx context.Context, slug string) (*entity.SlugLookup, error) {
+	var resp = new(entity.SlugLookup)
+
+	query := `
+		SELECT id, slug
+		FROM shops
+		WHERE slug = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT s.id, s.slug
+		FROM shop_slug_histories h
+		JOIN shops s ON s.id = h.shop_id
+		WHERE h.slug = ? AND s.deleted_at IS NULL
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), slug, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("slug", slug).Msg("repository::ResolveShopSlug - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		log.Error().Err(err).Str("slug", slug).Msg("repository::ResolveShopSlug - Failed to resolve slug")
+		return nil, err
+	}
+
+	return resp, nil
+}
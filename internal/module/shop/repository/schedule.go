@@ -0,0 +1,283 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// loadSchedules returns the schedule of every given shop keyed by shop id,
+// with holidays that have not ended yet.
+func loadSchedules(ctx context.Context, db sqlx.QueryerContext, shopIds []string) (map[string]*entity.Schedule, error) {
+	var (
+		schedules = make([]*entity.Schedule, 0, len(shopIds))
+		hours     = make([]entity.OpeningHour, 0)
+		holidays  = make([]entity.Holiday, 0)
+		result    = make(map[string]*entity.Schedule, len(shopIds))
+	)
+
+	if len(shopIds) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT id, timezone, holiday_hides_products
+		FROM shops
+		WHERE id = ANY($1)
+	`
+
+	err := sqlx.SelectContext(ctx, db, &schedules, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository::loadSchedules - Failed to get shops")
+		return nil, err
+	}
+
+	for _, s := range schedules {
+		s.OpeningHours = make([]entity.OpeningHour, 0)
+		s.Holidays = make([]entity.Holiday, 0)
+		result[s.ShopId] = s
+	}
+
+	query = `
+		SELECT shop_id, day_of_week, TO_CHAR(opens_at, 'HH24:MI') AS opens_at, TO_CHAR(closes_at, 'HH24:MI') AS closes_at
+		FROM shop_opening_hours
+		WHERE shop_id = ANY($1)
+		ORDER BY day_of_week ASC
+	`
+
+	err = sqlx.SelectContext(ctx, db, &hours, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository::loadSchedules - Failed to get opening hours")
+		return nil, err
+	}
+
+	for _, h := range hours {
+		if s, ok := result[h.ShopId]; ok {
+			s.OpeningHours = append(s.OpeningHours, h)
+		}
+	}
+
+	query = `
+		SELECT id, shop_id, starts_at, ends_at, message
+		FROM shop_holidays
+		WHERE shop_id = ANY($1) AND ends_at > NOW()
+		ORDER BY starts_at ASC
+	`
+
+	err = sqlx.SelectContext(ctx, db, &holidays, query, pq.StringArray(shopIds))
+	if err != nil {
+		log.Error().Err(err).Strs("shop_ids", shopIds).Msg("repository::loadSchedules - Failed to get holidays")
+		return nil, err
+	}
+
+	for _, h := range holidays {
+		if s, ok := result[h.ShopId]; ok {
+			s.Holidays = append(s.Holidays, h)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *shopRepository) GetShopSchedules(ctx context.Context, ids []string) (map[string]*entity.Schedule, error) {
+	return loadSchedules(ctx, r.db, ids)
+}
+
+func (r *shopRepository) UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpdateShopSchedule - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpdateShopSchedule - Failed to commit transaction")
+		}
+	}()
+
+	if _, err = r.getShopForUpdate(ctx, tx, req.Id, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpdateShopSchedule - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return err
+	}
+
+	schedules, err := loadSchedules(ctx, tx, []string{req.Id})
+	if err != nil {
+		return err
+	}
+	before := schedules[req.Id]
+
+	query := `
+		UPDATE shops
+		SET timezone = ?, holiday_hides_products = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Timezone, req.HolidayHidesProducts, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to update shop")
+		return err
+	}
+
+	query = `DELETE FROM shop_opening_hours WHERE shop_id = ?`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to delete opening hours")
+		return err
+	}
+
+	query = `
+		INSERT INTO shop_opening_hours (shop_id, day_of_week, opens_at, closes_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	for _, h := range req.OpeningHours {
+		_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Id, h.DayOfWeek, h.OpensAt, h.ClosesAt)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				err = errmsg.NewCustomErrors(400, errmsg.WithErrors("opening_hours", "day_of_week tidak boleh duplikat."))
+				return err
+			}
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopSchedule - Failed to insert opening hour")
+			return err
+		}
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.Id,
+		Before:     map[string]any{"timezone": before.Timezone, "holiday_hides_products": before.HolidayHidesProducts, "opening_hours": before.OpeningHours},
+		After:      map[string]any{"timezone": req.Timezone, "holiday_hides_products": req.HolidayHidesProducts, "opening_hours": req.OpeningHours},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) CreateShopHoliday(ctx context.Context, req *entity.CreateShopHolidayRequest) (resp *entity.Holiday, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShopHoliday - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::CreateShopHoliday - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::CreateShopHoliday - Failed to commit transaction")
+		}
+	}()
+
+	if _, err = r.getShopForUpdate(ctx, tx, req.ShopId, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::CreateShopHoliday - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return nil, err
+	}
+
+	resp = new(entity.Holiday)
+
+	query := `
+		INSERT INTO shop_holidays (shop_id, starts_at, ends_at, message)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, shop_id, starts_at, ends_at, message
+	`
+
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), req.ShopId, req.StartsAt, req.EndsAt, req.Message).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShopHoliday - Failed to insert holiday")
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.ShopId,
+		After:      map[string]any{"holiday": resp},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) DeleteShopHoliday(ctx context.Context, req *entity.DeleteShopHolidayRequest) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShopHoliday - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::DeleteShopHoliday - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::DeleteShopHoliday - Failed to commit transaction")
+		}
+	}()
+
+	var before = new(entity.Holiday)
+
+	query := `
+		DELETE FROM shop_holidays h
+		USING shops s
+		WHERE
+			h.id = ?
+			AND h.shop_id = ?
+			AND s.id = h.shop_id
+			AND s.user_id = ?
+			AND s.deleted_at IS NULL
+		RETURNING h.id, h.shop_id, h.starts_at, h.ends_at, h.message
+	`
+
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), req.Id, req.ShopId, req.UserId).StructScan(before)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::DeleteShopHoliday - Holiday not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Holiday not found"))
+			return err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShopHoliday - Failed to delete holiday")
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.ShopId,
+		Before:     map[string]any{"holiday": before},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
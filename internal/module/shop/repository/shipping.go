@@ -0,0 +1,334 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// GetShopOrigin returns the origin address of a shop, or nil when the owner
+// has not set one yet.
+func (r *shopRepository) GetShopOrigin(ctx context.Context, id string) (*entity.ShopOrigin, error) {
+	type dao struct {
+		Address    *string      `db:"origin_address"`
+		City       *string      `db:"origin_city"`
+		PostalCode *string      `db:"origin_postal_code"`
+		Point      *types.Point `db:"origin_point"`
+	}
+
+	var data dao
+
+	query := `
+		SELECT origin_address, origin_city, origin_postal_code, origin_point
+		FROM shops
+		WHERE id = ? AND deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &data, r.db.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::GetShopOrigin - Shop not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::GetShopOrigin - Failed to get shop origin")
+		return nil, err
+	}
+
+	if data.Address == nil || data.Point == nil {
+		return nil, nil
+	}
+
+	origin := &entity.ShopOrigin{
+		Address:    *data.Address,
+		PostalCode: data.PostalCode,
+		Latitude:   data.Point.Lat(),
+		Longitude:  data.Point.Lng(),
+		Point:      data.Point,
+	}
+	if data.City != nil {
+		origin.City = *data.City
+	}
+
+	return origin, nil
+}
+
+func (r *shopRepository) UpdateShopOrigin(ctx context.Context, req *entity.UpdateShopOriginRequest) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopOrigin - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpdateShopOrigin - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpdateShopOrigin - Failed to commit transaction")
+		}
+	}()
+
+	if _, err = r.getShopForUpdate(ctx, tx, req.Id, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpdateShopOrigin - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return err
+	}
+
+	before, err := r.GetShopOrigin(ctx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE shops
+		SET
+			origin_address = ?,
+			origin_city = ?,
+			origin_postal_code = ?,
+			origin_point = ?,
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query),
+		req.Address,
+		req.City,
+		req.PostalCode,
+		types.NewPoint(req.Latitude, req.Longitude),
+		req.Id,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShopOrigin - Failed to update shop origin")
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.Id,
+		Before:     map[string]any{"origin": before},
+		After: map[string]any{"origin": entity.ShopOrigin{
+			Address:    req.Address,
+			City:       req.City,
+			PostalCode: req.PostalCode,
+			Latitude:   req.Latitude,
+			Longitude:  req.Longitude,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) GetShippingProfiles(ctx context.Context, req *entity.GetShippingProfilesRequest) ([]entity.ShippingProfile, error) {
+	var resp = make([]entity.ShippingProfile, 0)
+
+	query := `
+		SELECT
+			sp.id,
+			sp.shop_id,
+			sp.name,
+			sp.couriers,
+			sp.handling_days,
+			sp.free_shipping_threshold,
+			sp.is_default,
+			sp.created_at,
+			sp.updated_at
+		FROM shop_shipping_profiles sp
+		JOIN shops s ON s.id = sp.shop_id
+		WHERE sp.shop_id = ? AND s.deleted_at IS NULL
+		ORDER BY sp.is_default DESC, sp.created_at ASC
+	`
+
+	err := r.db.SelectContext(ctx, &resp, r.db.Rebind(query), req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetShippingProfiles - Failed to get shipping profiles")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpsertShippingProfile(ctx context.Context, req *entity.UpsertShippingProfileRequest) (resp *entity.ShippingProfile, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpsertShippingProfile - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::UpsertShippingProfile - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::UpsertShippingProfile - Failed to commit transaction")
+		}
+	}()
+
+	if _, err = r.getShopForUpdate(ctx, tx, req.ShopId, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpsertShippingProfile - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return nil, err
+	}
+
+	var before *entity.ShippingProfile
+	if req.Id != "" {
+		before, err = r.getShippingProfileForUpdate(ctx, tx, req.ShopId, req.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// only one default profile per shop
+	if req.IsDefault {
+		query := `
+			UPDATE shop_shipping_profiles
+			SET is_default = false, updated_at = NOW()
+			WHERE shop_id = ? AND is_default AND id::TEXT <> ?
+		`
+
+		_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.ShopId, req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpsertShippingProfile - Failed to unset default profile")
+			return nil, err
+		}
+	}
+
+	resp = new(entity.ShippingProfile)
+
+	query := `
+		INSERT INTO shop_shipping_profiles (shop_id, name, couriers, handling_days, free_shipping_threshold, is_default)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, shop_id, name, couriers, handling_days, free_shipping_threshold, is_default, created_at, updated_at
+	`
+	args := []any{req.ShopId, req.Name, pq.StringArray(req.Couriers), req.HandlingDays, req.FreeShippingThreshold, req.IsDefault}
+
+	if req.Id != "" {
+		query = `
+			UPDATE shop_shipping_profiles
+			SET
+				name = ?,
+				couriers = ?,
+				handling_days = ?,
+				free_shipping_threshold = ?,
+				is_default = ?,
+				updated_at = NOW()
+			WHERE id = ? AND shop_id = ?
+			RETURNING id, shop_id, name, couriers, handling_days, free_shipping_threshold, is_default, created_at, updated_at
+		`
+		args = []any{req.Name, pq.StringArray(req.Couriers), req.HandlingDays, req.FreeShippingThreshold, req.IsDefault, req.Id, req.ShopId}
+	}
+
+	err = tx.QueryRowxContext(ctx, r.db.Rebind(query), args...).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpsertShippingProfile - Failed to save shipping profile")
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.ShopId,
+		Before:     map[string]any{"shipping_profile": before},
+		After:      map[string]any{"shipping_profile": resp},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) DeleteShippingProfile(ctx context.Context, req *entity.DeleteShippingProfileRequest) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShippingProfile - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::DeleteShippingProfile - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::DeleteShippingProfile - Failed to commit transaction")
+		}
+	}()
+
+	if _, err = r.getShopForUpdate(ctx, tx, req.ShopId, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::DeleteShippingProfile - Shop not found")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+		}
+		return err
+	}
+
+	before, err := r.getShippingProfileForUpdate(ctx, tx, req.ShopId, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM shop_shipping_profiles WHERE id = ? AND shop_id = ?`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), req.Id, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteShippingProfile - Failed to delete shipping profile")
+		return err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionUpdate,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.ShopId,
+		Before:     map[string]any{"shipping_profile": before},
+		After:      map[string]any{"shipping_profile": nil},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) getShippingProfileForUpdate(ctx context.Context, tx *sqlx.Tx, shopId, id string) (*entity.ShippingProfile, error) {
+	var profile = new(entity.ShippingProfile)
+
+	query := `
+		SELECT id, shop_id, name, couriers, handling_days, free_shipping_threshold, is_default, created_at, updated_at
+		FROM shop_shipping_profiles
+		WHERE id = ? AND shop_id = ?
+		FOR UPDATE
+	`
+
+	err := tx.QueryRowxContext(ctx, r.db.Rebind(query), id, shopId).StructScan(profile)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repository::getShippingProfileForUpdate - Shipping profile not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shipping profile not found"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::getShippingProfileForUpdate - Failed to get shipping profile")
+		return nil, err
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// shopDependents are the tables referencing shops without ON DELETE CASCADE,
+// their rows are removed before the shops on a purge.
+var shopDependents = []string{
+	"shop_status_histories",
+	"shop_categories",
+	"shop_slug_histories",
+	"shop_opening_hours",
+	"shop_holidays",
+	"shop_shipping_profiles",
+	"shop_followers",
+	"product_feed_events",
+	"api_keys",
+	"webhook_subscriptions",
+}
+
+func (r *shopRepository) GetShopTrash(ctx context.Context, req *entity.ShopTrashRequest, since time.Time) (*entity.ShopTrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashedShop
+	}
+
+	var (
+		resp = new(entity.ShopTrashResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.TrashedShop, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(s.id) OVER() as total_data,
+			s.id,
+			s.name,
+			s.slug,
+			s.logo_url,
+			s.deleted_at,
+			(
+				SELECT COUNT(*)
+				FROM products p
+				WHERE p.shop_id = s.id AND p.deleted_at >= s.deleted_at
+			) AS product_count
+		FROM shops s
+		WHERE
+			s.user_id = ?
+			AND s.deleted_at >= ?
+		ORDER BY s.deleted_at DESC, s.id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		since,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetShopTrash - Failed to get deleted shops")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.TrashedShop)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// RestoreShop brings back a shop of the user deleted since the given time
+// and, when asked, the products deleted with or after it. A product whose
+// sku was taken meanwhile stays in the trash.
+func (r *shopRepository) RestoreShop(ctx context.Context, req *entity.RestoreShopRequest, since time.Time) (resp *entity.RestoreShopResponse, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::RestoreShop - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::RestoreShop - Failed to commit transaction")
+		}
+	}()
+
+	var deletedAt time.Time
+
+	query := `
+		SELECT deleted_at
+		FROM shops
+		WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`
+
+	if err = tx.GetContext(ctx, &deletedAt, r.db.Rebind(query), req.Id, req.UserId); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::RestoreShop - Shop not found in the trash")
+			err = errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found in the trash"))
+			return nil, err
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to get shop")
+		return nil, err
+	}
+
+	if deletedAt.Before(since) {
+		log.Warn().Any("payload", req).Time("deleted_at", deletedAt).Msg("repository::RestoreShop - Shop retention is over")
+		err = errmsg.NewCustomErrors(410, errmsg.WithMessage("Shop was deleted too long ago to be restored"))
+		return nil, err
+	}
+
+	resp = new(entity.RestoreShopResponse)
+
+	query = `
+		UPDATE shops
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = ?
+		RETURNING id, name, slug
+	`
+
+	if err = tx.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to restore shop")
+		return nil, err
+	}
+
+	after, err := r.getShopForUpdate(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+		Action:     auditEnt.ActionRestore,
+		EntityType: auditEnt.EntityShop,
+		EntityId:   req.Id,
+		After:      after,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = recordEvent(ctx, tx, entity.EventShopRestored, req.Id, after); err != nil {
+		return nil, err
+	}
+
+	if !req.RestoreProducts {
+		return resp, nil
+	}
+
+	productIds := make([]string, 0)
+
+	// skus are unique among the live products of a shop only, the first
+	// restored product keeps a sku shared by several deleted ones
+	query = `
+		UPDATE products p
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE
+			p.shop_id = ?
+			AND p.deleted_at >= ?
+			AND (
+				p.sku IS NULL
+				OR NOT EXISTS (
+					SELECT 1 FROM products o
+					WHERE o.shop_id = p.shop_id AND o.sku = p.sku AND o.id <> p.id
+						AND (o.deleted_at IS NULL OR (o.deleted_at >= ? AND o.id < p.id))
+				)
+			)
+		RETURNING p.id
+	`
+
+	err = tx.SelectContext(ctx, &productIds, r.db.Rebind(query), req.Id, deletedAt, deletedAt)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreShop - Failed to restore products")
+		return nil, err
+	}
+
+	for _, id := range productIds {
+		err = auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionRestore,
+			EntityType: auditEnt.EntityProduct,
+			EntityId:   id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = recordProductEvents(ctx, tx, productEnt.EventProductRestored, req.Id, productIds); err != nil {
+		return nil, err
+	}
+
+	resp.RestoredProducts = len(productIds)
+
+	return resp, nil
+}
+
+// PurgeShops hard deletes up to limit shops deleted before the given time,
+// with the rows depending on them. Shops still having products are left for
+// a later run, their products are purged first.
+func (r *shopRepository) PurgeShops(ctx context.Context, before time.Time, limit int) (resp *entity.PurgeResult, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::PurgeShops - Failed to begin transaction")
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::PurgeShops - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::PurgeShops - Failed to commit transaction")
+		}
+	}()
+
+	shops := make([]struct {
+		Id        string  `db:"id"`
+		LogoUrl   *string `db:"logo_url"`
+		BannerUrl *string `db:"banner_url"`
+	}, 0, limit)
+
+	query := `
+		SELECT id, logo_url, banner_url
+		FROM shops s
+		WHERE
+			s.deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM products p WHERE p.shop_id = s.id)
+		ORDER BY s.id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	if err = tx.SelectContext(ctx, &shops, r.db.Rebind(query), before, limit); err != nil {
+		log.Error().Err(err).Time("before", before).Msg("repository::PurgeShops - Failed to get shops")
+		return nil, err
+	}
+
+	resp = &entity.PurgeResult{Images: make([]string, 0)}
+
+	if len(shops) == 0 {
+		return resp, nil
+	}
+
+	ids := make([]string, 0, len(shops))
+	for _, s := range shops {
+		ids = append(ids, s.Id)
+		for _, url := range []*string{s.LogoUrl, s.BannerUrl} {
+			if url != nil && *url != "" {
+				resp.Images = append(resp.Images, *url)
+			}
+		}
+	}
+
+	for _, table := range shopDependents {
+		query = `DELETE FROM ` + table + ` WHERE shop_id = ANY(?)`
+
+		if _, err = tx.ExecContext(ctx, r.db.Rebind(query), pq.StringArray(ids)); err != nil {
+			log.Error().Err(err).Str("table", table).Msg("repository::PurgeShops - Failed to delete shop rows")
+			return nil, err
+		}
+	}
+
+	query = `DELETE FROM shops WHERE id = ANY(?)`
+
+	if _, err = tx.ExecContext(ctx, r.db.Rebind(query), pq.StringArray(ids)); err != nil {
+		log.Error().Err(err).Msg("repository::PurgeShops - Failed to delete shops")
+		return nil, err
+	}
+
+	resp.Shops = len(shops)
+
+	return resp, nil
+}
@@ -1,20 +1,29 @@
 package service
 
 import (
+	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
+	"codebase-app/pkg/errmsg"
 	"context"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
 )
 
 var _ ports.ShopService = &shopService{}
 
 type shopService struct {
-	repo ports.ShopRepository
+	repo    ports.ShopRepository
+	storage integStorage.LocalStorageContract
 }
 
-func NewShopService(repo ports.ShopRepository) *shopService {
+func NewShopService(repo ports.ShopRepository, storage integStorage.LocalStorageContract) *shopService {
 	return &shopService{
-		repo: repo,
+		repo:    repo,
+		storage: storage,
 	}
 }
 
@@ -23,17 +32,255 @@ func (s *shopService) CreateShop(ctx context.Context, req *entity.CreateShopRequ
 }
 
 func (s *shopService) GetShop(ctx context.Context, req *entity.GetShopRequest) (*entity.GetShopResponse, error) {
-	return s.repo.GetShop(ctx, req)
+	resp, err := s.repo.GetShop(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetShopSchedules(ctx, []string{resp.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Origin, err = s.repo.GetShopOrigin(ctx, resp.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.OpeningHours = make([]entity.OpeningHour, 0)
+	if schedule, ok := schedules[resp.Id]; ok {
+		now := time.Now()
+		resp.OpeningHours = schedule.OpeningHours
+		resp.IsOpen = schedule.IsOpen(now)
+		if holiday := schedule.ActiveHoliday(now); holiday != nil {
+			resp.HolidayMessage = holiday.Message
+		}
+	}
+
+	return resp, nil
 }
 
-func (s *shopService) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) error {
+func (s *shopService) DeleteShop(ctx context.Context, req *entity.DeleteShopRequest) (*entity.DeleteShopResponse, error) {
 	return s.repo.DeleteShop(ctx, req)
 }
 
 func (s *shopService) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error) {
+	if req.Logo != nil {
+		url, err := s.saveImage(*req.Logo, "logo")
+		if err != nil {
+			return nil, err
+		}
+		req.LogoUrl = &url
+	}
+
+	if req.Banner != nil {
+		url, err := s.saveImage(*req.Banner, "banner")
+		if err != nil {
+			return nil, err
+		}
+		req.BannerUrl = &url
+	}
+
 	return s.repo.UpdateShop(ctx, req)
 }
 
+// saveImage stores a base64 encoded shop image and returns its public url.
+func (s *shopService) saveImage(base64String, field string) (string, error) {
+	publicPath := config.Envs.App.LocalStoragePublicPath
+
+	fullpath, err := s.storage.Save(base64String, publicPath+"/shops")
+	if err != nil {
+		if err == integStorage.ErrFileTypeNotSupported {
+			return "", errmsg.NewCustomErrors(400, errmsg.WithErrors(field, field+" harus berupa gambar jpeg atau png."))
+		}
+		log.Error().Err(err).Str("field", field).Msg("service::saveImage - Failed to save shop image")
+		return "", errmsg.NewCustomErrors(400, errmsg.WithErrors(field, field+" tidak valid."))
+	}
+
+	return config.Envs.App.BaseURL + "/products/storage" + strings.TrimPrefix(fullpath, publicPath), nil
+}
+
 func (s *shopService) GetShops(ctx context.Context, req *entity.ShopsRequest) (*entity.ShopsResponse, error) {
 	return s.repo.GetShops(ctx, req)
 }
+
+func (s *shopService) UpdateShopStatus(ctx context.Context, req *entity.UpdateShopStatusRequest) (*entity.UpdateShopStatusResponse, error) {
+	shop, err := s.repo.GetShopStatus(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.IsAdmin && shop.UserId != req.UserId {
+		log.Warn().Any("payload", req).Msg("service::UpdateShopStatus - User is not shop owner")
+		return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	if !entity.CanTransition(shop.Status, req.Status, req.IsAdmin) {
+		log.Warn().Any("payload", req).Str("from", shop.Status).Msg("service::UpdateShopStatus - Transition not allowed")
+		return nil, errmsg.NewCustomErrors(422, errmsg.WithMessage("Perubahan status dari "+shop.Status+" ke "+req.Status+" tidak diizinkan"))
+	}
+
+	if req.IsAdmin && (req.Reason == nil || *req.Reason == "") {
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("reason", "reason harus diisi."))
+	}
+
+	if err := s.repo.UpdateShopStatus(ctx, req, shop.Status); err != nil {
+		return nil, err
+	}
+
+	resp := &entity.UpdateShopStatusResponse{
+		Id:         req.Id,
+		FromStatus: shop.Status,
+		Status:     req.Status,
+		Reason:     req.Reason,
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) GetAdminShops(ctx context.Context, req *entity.AdminShopsRequest) (*entity.ShopsResponse, error) {
+	return s.repo.GetAdminShops(ctx, req)
+}
+
+func (s *shopService) SearchShops(ctx context.Context, req *entity.SearchShopsRequest) (*entity.SearchShopsResponse, error) {
+	resp, err := s.repo.SearchShops(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		ids = append(ids, item.Id)
+	}
+
+	schedules, err := s.repo.GetShopSchedules(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range resp.Items {
+		if schedule, ok := schedules[resp.Items[i].Id]; ok {
+			resp.Items[i].IsOpen = schedule.IsOpen(now)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error) {
+	lookup, err := s.repo.ResolveShopSlug(ctx, req.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if lookup.Slug != req.Slug {
+		return &entity.GetShopBySlugResponse{RedirectSlug: lookup.Slug}, nil
+	}
+
+	shop, err := s.repo.GetShop(ctx, &entity.GetShopRequest{Id: lookup.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.GetShopBySlugResponse{Shop: shop}, nil
+}
+
+func (s *shopService) GetShopSchedule(ctx context.Context, req *entity.GetShopScheduleRequest) (*entity.GetShopScheduleResponse, error) {
+	schedules, err := s.repo.GetShopSchedules(ctx, []string{req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, ok := schedules[req.Id]
+	if !ok {
+		log.Warn().Any("payload", req).Msg("service::GetShopSchedule - Shop not found")
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Shop not found"))
+	}
+
+	now := time.Now()
+	resp := &entity.GetShopScheduleResponse{
+		Schedule: *schedule,
+		IsOpen:   schedule.IsOpen(now),
+	}
+
+	if holiday := schedule.ActiveHoliday(now); holiday != nil {
+		resp.HolidayMessage = holiday.Message
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) UpdateShopSchedule(ctx context.Context, req *entity.UpdateShopScheduleRequest) (*entity.GetShopScheduleResponse, error) {
+	if err := s.repo.UpdateShopSchedule(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return s.GetShopSchedule(ctx, &entity.GetShopScheduleRequest{Id: req.Id})
+}
+
+func (s *shopService) CreateShopHoliday(ctx context.Context, req *entity.CreateShopHolidayRequest) (*entity.Holiday, error) {
+	if !req.EndsAt.After(time.Now()) {
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("ends_at", "ends_at harus di masa depan."))
+	}
+
+	return s.repo.CreateShopHoliday(ctx, req)
+}
+
+func (s *shopService) DeleteShopHoliday(ctx context.Context, req *entity.DeleteShopHolidayRequest) error {
+	return s.repo.DeleteShopHoliday(ctx, req)
+}
+
+func (s *shopService) UpdateShopOrigin(ctx context.Context, req *entity.UpdateShopOriginRequest) (*entity.ShopOrigin, error) {
+	if err := s.repo.UpdateShopOrigin(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetShopOrigin(ctx, req.Id)
+}
+
+func (s *shopService) GetShippingProfiles(ctx context.Context, req *entity.GetShippingProfilesRequest) ([]entity.ShippingProfile, error) {
+	return s.repo.GetShippingProfiles(ctx, req)
+}
+
+func (s *shopService) UpsertShippingProfile(ctx context.Context, req *entity.UpsertShippingProfileRequest) (*entity.ShippingProfile, error) {
+	return s.repo.UpsertShippingProfile(ctx, req)
+}
+
+func (s *shopService) DeleteShippingProfile(ctx context.Context, req *entity.DeleteShippingProfileRequest) error {
+	return s.repo.DeleteShippingProfile(ctx, req)
+}
+
+func (s *shopService) FollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error) {
+	return s.repo.FollowShop(ctx, req)
+}
+
+func (s *shopService) UnfollowShop(ctx context.Context, req *entity.FollowShopRequest) (*entity.FollowShopResponse, error) {
+	return s.repo.UnfollowShop(ctx, req)
+}
+
+func (s *shopService) GetFollowedShops(ctx context.Context, req *entity.FollowedShopsRequest) (*entity.FollowedShopsResponse, error) {
+	resp, err := s.repo.GetFollowedShops(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		ids = append(ids, item.Id)
+	}
+
+	schedules, err := s.repo.GetShopSchedules(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range resp.Items {
+		if schedule, ok := schedules[resp.Items[i].Id]; ok {
+			resp.Items[i].IsOpen = schedule.IsOpen(now)
+		}
+	}
+
+	return resp, nil
+}
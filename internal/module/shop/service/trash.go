@@ -0,0 +1,57 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/shop/entity"
+	"context"
+	"time"
+)
+
+// purgeBatchSize is how many shops are purged per transaction.
+const purgeBatchSize = 100
+
+// trashRetention is how long deleted shops can be restored.
+func trashRetention() time.Duration {
+	return time.Duration(config.Envs.Trash.RetentionDays) * 24 * time.Hour
+}
+
+func (s *shopService) GetShopTrash(ctx context.Context, req *entity.ShopTrashRequest) (*entity.ShopTrashResponse, error) {
+	retention := trashRetention()
+
+	resp, err := s.repo.GetShopTrash(ctx, req, time.Now().Add(-retention))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Items {
+		resp.Items[i].PurgeAt = resp.Items[i].DeletedAt.Add(retention)
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) RestoreShop(ctx context.Context, req *entity.RestoreShopRequest) (*entity.RestoreShopResponse, error) {
+	return s.repo.RestoreShop(ctx, req, time.Now().Add(-trashRetention()))
+}
+
+// PurgeShops hard deletes, batch by batch, the shops whose retention ended
+// before now. Their products must have been purged first.
+func (s *shopService) PurgeShops(ctx context.Context, now time.Time) (*entity.PurgeResult, error) {
+	var resp = &entity.PurgeResult{Images: make([]string, 0)}
+
+	for ctx.Err() == nil {
+		batch, err := s.repo.PurgeShops(ctx, now.Add(-trashRetention()), purgeBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Shops += batch.Shops
+		resp.Images = append(resp.Images, batch.Images...)
+
+		if batch.Shops < purgeBatchSize {
+			break
+		}
+	}
+
+	return resp, ctx.Err()
+}
@@ -27,8 +27,49 @@ type ProfileRequest struct {
 }
 
 type ProfileResponse struct {
-	Id    string `json:"id" db:"id"`
-	Name  string `json:"name" db:"name"`
-	Email string `json:"email" db:"email"`
-	Role  string `json:"-" db:"role"`
+	Id           string  `json:"id" db:"id"`
+	Name         string  `json:"name" db:"name"`
+	Email        string  `json:"email" db:"email"`
+	PendingEmail *string `json:"pending_email" db:"pending_email"`
+	AvatarUrl    *string `json:"avatar_url" db:"avatar_url"`
+	Role         string  `json:"-" db:"role"`
+}
+
+type UpdateProfileRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Name   *string `json:"name" validate:"omitempty,min=1,max=255"`
+	Avatar *string `json:"avatar"` // base64 encoded jpeg or png, ex: "data:image/png;base64,..."
+
+	AvatarUrl *string
+}
+
+type ChangeEmailRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangeEmailResponse struct {
+	PendingEmail string `json:"pending_email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `query:"token" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,strong_password,nefield=CurrentPassword"`
+
+	HassedPassword string
+}
+
+type DeleteAccountRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Password string `json:"password" validate:"required"`
 }
@@ -6,4 +6,7 @@ type UserResult struct {
 	Name  string `db:"name"`
 	Email string `db:"email"`
 	Pass  string `db:"password"`
+
+	// TokenVersion is carried by the tokens of the user, raising it revokes them
+	TokenVersion int `db:"token_version"`
 }
@@ -3,6 +3,8 @@ package rest
 import (
 	"codebase-app/internal/adapter"
 	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
+	integMailer "codebase-app/internal/integration/mailer"
 	integOauth "codebase-app/internal/integration/oauth2google"
 	oauth "codebase-app/internal/integration/oauth2google/entity"
 	"codebase-app/internal/middleware"
@@ -32,7 +34,10 @@ func NewUserHandler(o integOauth.Oauth2googleContract) *userHandler {
 	var handler = new(userHandler)
 
 	repo := repository.NewUserRepository(adapter.Adapters.ShopeefunPostgres)
-	service := service.NewUserService(repo, o)
+	service := service.NewUserService(repo, o,
+		integStorage.NewLocalStorageIntegration(),
+		integMailer.NewMailerIntegration(),
+	)
 
 	handler.integration = o
 
@@ -45,6 +50,11 @@ func (h *userHandler) Register(router fiber.Router) {
 	router.Post("/register", h.register)
 	router.Post("/login", h.login)
 	router.Get("/profile", middleware.AuthBearer, h.profile)
+	router.Patch("/profile", middleware.AuthBearer, h.updateProfile)
+	router.Delete("/profile", middleware.AuthBearer, h.deleteAccount)
+	router.Patch("/profile/email", middleware.AuthBearer, h.changeEmail)
+	router.Get("/profile/email/verify", h.verifyEmail)
+	router.Patch("/profile/password", middleware.AuthBearer, h.changePassword)
 	router.Get("/profile/:user_id", middleware.AuthBearer, h.profileByUserId)
 
 	router.Get("/oauth/google/url", h.oauthGoogleUrl)
@@ -147,6 +157,148 @@ func (h *userHandler) profile(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(res, ""))
 }
 
+func (h *userHandler) updateProfile(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateProfileRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::updateProfile - Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Msg("handler::updateProfile - Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	res, err := h.service.UpdateProfile(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(res, ""))
+}
+
+func (h *userHandler) changeEmail(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ChangeEmailRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::changeEmail - Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Msg("handler::changeEmail - Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	res, err := h.service.ChangeEmail(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(response.Success(res, "Silakan cek email baru anda untuk verifikasi"))
+}
+
+func (h *userHandler) verifyEmail(c *fiber.Ctx) error {
+	var (
+		req = new(entity.VerifyEmailRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Token = c.Query("token")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Msg("handler::verifyEmail - Invalid request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.VerifyEmail(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, "Email berhasil diverifikasi"))
+}
+
+func (h *userHandler) changePassword(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ChangePasswordRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::changePassword - Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Msg("handler::changePassword - Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	res, err := h.service.ChangePassword(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(res, ""))
+}
+
+func (h *userHandler) deleteAccount(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteAccountRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::deleteAccount - Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Msg("handler::deleteAccount - Invalid request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteAccount(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, "Akun berhasil dihapus"))
+}
+
 func (h *userHandler) oauthGoogleUrl(c *fiber.Ctx) error {
 	return c.Redirect(h.integration.GetUrl("/"), http.StatusTemporaryRedirect)
 }
@@ -4,18 +4,31 @@ import (
 	oauthgoogleent "codebase-app/internal/integration/oauth2google/entity"
 	"codebase-app/internal/module/user/entity"
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error)
 	FindByEmail(ctx context.Context, email string) (*entity.UserResult, error)
 	FindById(ctx context.Context, id string) (*entity.ProfileResponse, error)
+	FindCredentialById(ctx context.Context, id string) (*entity.UserResult, error)
+	IsEmailRegistered(ctx context.Context, email string) (bool, error)
+	UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.ProfileResponse, error)
+	CreateEmailVerification(ctx context.Context, userId, email, tokenHash string, expiresAt time.Time) error
+	VerifyEmail(ctx context.Context, tokenHash string) error
+	UpdatePassword(ctx context.Context, userId, hashedPassword string) (int, error)
+	DeleteAccount(ctx context.Context, userId string) error
 }
 
 type UserService interface {
 	Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error)
 	Login(ctx context.Context, req *entity.LoginRequest) (*entity.LoginResponse, error)
 	Profile(ctx context.Context, req *entity.ProfileRequest) (*entity.ProfileResponse, error)
+	UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.ProfileResponse, error)
+	ChangeEmail(ctx context.Context, req *entity.ChangeEmailRequest) (*entity.ChangeEmailResponse, error)
+	VerifyEmail(ctx context.Context, req *entity.VerifyEmailRequest) error
+	ChangePassword(ctx context.Context, req *entity.ChangePasswordRequest) (*entity.LoginResponse, error)
+	DeleteAccount(ctx context.Context, req *entity.DeleteAccountRequest) error
 	GetOauthGoogleUrl(ctx context.Context) (string, error)
 	LoginGoogle(ctx context.Context, req *oauthgoogleent.UserInfoResponse) (*entity.LoginResponse, error)
 }
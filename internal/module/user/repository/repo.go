@@ -1,11 +1,15 @@
 package repository
 
 import (
+	auditEnt "codebase-app/internal/module/audit/entity"
+	auditRepo "codebase-app/internal/module/audit/repository"
+	shopRepo "codebase-app/internal/module/shop/repository"
 	"codebase-app/internal/module/user/entity"
 	"codebase-app/internal/module/user/ports"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -24,6 +28,61 @@ func NewUserRepository(db *sqlx.DB) *userRepository {
 	}
 }
 
+// userSnapshot is the state of a user recorded in the audit log, it never
+// contains the password hash.
+type userSnapshot struct {
+	Id           string  `json:"id" db:"id"`
+	Name         string  `json:"name" db:"name"`
+	Email        string  `json:"email" db:"email"`
+	PendingEmail *string `json:"pending_email" db:"pending_email"`
+	AvatarUrl    *string `json:"avatar_url" db:"avatar_url"`
+}
+
+func (r *userRepository) getUserForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*userSnapshot, error) {
+	var snapshot = new(userSnapshot)
+
+	query := `
+		SELECT id, name, email, pending_email, avatar_url
+		FROM users
+		WHERE id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, snapshot, r.db.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repo::getUserForUpdate - User not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("User tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repo::getUserForUpdate - Failed to get user")
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// withTx runs fn in a transaction, committing when fn succeeds.
+func (r *userRepository) withTx(ctx context.Context, name string, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repo::" + name + " - Failed to begin transaction")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repo::" + name + " - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repo::" + name + " - Failed to commit transaction")
+		}
+	}()
+
+	return fn(tx)
+}
+
 func (r *userRepository) Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error) {
 	var res = new(entity.RegisterResponse)
 
@@ -38,10 +97,23 @@ func (r *userRepository) Register(ctx context.Context, req *entity.RegisterReque
 			(SELECT id FROM roles WHERE name = 'end_user'),
 			?, ?, ?
 		)
-		RETURNING id
+		RETURNING id, name, email, pending_email, avatar_url
 	`
 
-	err := r.db.QueryRowContext(ctx, r.db.Rebind(query), req.Email, req.Name, req.HassedPassword).Scan(&res.Id)
+	err := r.withTx(ctx, "Register", func(tx *sqlx.Tx) error {
+		var after = new(userSnapshot)
+		if err := tx.GetContext(ctx, after, r.db.Rebind(query), req.Email, req.Name, req.HassedPassword); err != nil {
+			return err
+		}
+		res.Id = after.Id
+
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionCreate,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   after.Id,
+			After:      after,
+		})
+	})
 	if err != nil {
 		pqErr, ok := err.(*pq.Error)
 		if !ok {
@@ -71,13 +143,15 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*entity
 			r.name AS role,
 			u.name,
 			u.email,
-			u.password
+			u.password,
+			u.token_version
 		FROM
 			users u
 		LEFT JOIN
 			roles r ON u.role_id = r.id
 		WHERE
 			u.email = ?
+			AND u.deleted_at IS NULL
 	`
 
 	err := r.db.GetContext(ctx, res, r.db.Rebind(query), email)
@@ -101,13 +175,16 @@ func (r *userRepository) FindById(ctx context.Context, id string) (*entity.Profi
 			u.id,
 			r.name AS role,
 			u.name,
-			u.email
+			u.email,
+			u.pending_email,
+			u.avatar_url
 		FROM
 			users u
 		LEFT JOIN
 			roles r ON u.role_id = r.id
 		WHERE
 			u.id = ?
+			AND u.deleted_at IS NULL
 	`
 
 	err := r.db.GetContext(ctx, res, r.db.Rebind(query), id)
@@ -123,3 +200,335 @@ func (r *userRepository) FindById(ctx context.Context, id string) (*entity.Profi
 
 	return res, nil
 }
+
+func (r *userRepository) FindCredentialById(ctx context.Context, id string) (*entity.UserResult, error) {
+	var res = new(entity.UserResult)
+
+	query := `
+		SELECT
+			u.id,
+			r.name AS role,
+			u.name,
+			u.email,
+			u.password,
+			u.token_version
+		FROM
+			users u
+		LEFT JOIN
+			roles r ON u.role_id = r.id
+		WHERE
+			u.id = ?
+			AND u.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, res, r.db.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Err(err).Str("id", id).Msg("repo::FindCredentialById - User not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("User tidak ditemukan"))
+		}
+
+		log.Error().Err(err).Str("id", id).Msg("repo::FindCredentialById - Failed to get user")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *userRepository) IsEmailRegistered(ctx context.Context, email string) (bool, error) {
+	var exists bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM users WHERE email = ?
+		)
+	`
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(query), email)
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("repo::IsEmailRegistered - Failed to check email")
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.ProfileResponse, error) {
+	err := r.withTx(ctx, "UpdateProfile", func(tx *sqlx.Tx) error {
+		before, err := r.getUserForUpdate(ctx, tx, req.UserId)
+		if err != nil {
+			return err
+		}
+
+		var after = new(userSnapshot)
+
+		query := `
+			UPDATE users
+			SET
+				name = COALESCE(?, name),
+				avatar_url = COALESCE(?, avatar_url),
+				updated_at = NOW()
+			WHERE
+				id = ?
+				AND deleted_at IS NULL
+			RETURNING id, name, email, pending_email, avatar_url
+		`
+
+		err = tx.GetContext(ctx, after, r.db.Rebind(query), req.Name, req.AvatarUrl, req.UserId)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repo::UpdateProfile - Failed to update user")
+			return err
+		}
+
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   req.UserId,
+			Before:     before,
+			After:      after,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindById(ctx, req.UserId)
+}
+
+func (r *userRepository) CreateEmailVerification(ctx context.Context, userId, email, tokenHash string, expiresAt time.Time) error {
+	return r.withTx(ctx, "CreateEmailVerification", func(tx *sqlx.Tx) error {
+		before, err := r.getUserForUpdate(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		// only the latest requested email can be verified
+		query := `
+			DELETE FROM user_email_verifications
+			WHERE user_id = ? AND verified_at IS NULL
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::CreateEmailVerification - Failed to delete previous verifications")
+			return err
+		}
+
+		query = `
+			INSERT INTO user_email_verifications (user_id, email, token_hash, expires_at)
+			VALUES (?, ?, ?, ?)
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), userId, email, tokenHash, expiresAt); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::CreateEmailVerification - Failed to insert verification")
+			return err
+		}
+
+		query = `
+			UPDATE users
+			SET pending_email = ?, updated_at = NOW()
+			WHERE id = ? AND deleted_at IS NULL
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), email, userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::CreateEmailVerification - Failed to set pending email")
+			return err
+		}
+
+		after := *before
+		after.PendingEmail = &email
+
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   userId,
+			Before:     before,
+			After:      &after,
+		})
+	})
+}
+
+func (r *userRepository) VerifyEmail(ctx context.Context, tokenHash string) error {
+	return r.withTx(ctx, "VerifyEmail", func(tx *sqlx.Tx) error {
+		var verification struct {
+			UserId string `db:"user_id"`
+			Email  string `db:"email"`
+		}
+
+		query := `
+			UPDATE user_email_verifications
+			SET verified_at = NOW()
+			WHERE
+				token_hash = ?
+				AND verified_at IS NULL
+				AND expires_at > NOW()
+			RETURNING user_id, email
+		`
+
+		err := tx.GetContext(ctx, &verification, r.db.Rebind(query), tokenHash)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Warn().Msg("repo::VerifyEmail - Token not found or expired")
+				return errmsg.NewCustomErrors(400, errmsg.WithMessage("Token verifikasi tidak valid atau sudah kedaluwarsa"))
+			}
+			log.Error().Err(err).Msg("repo::VerifyEmail - Failed to verify token")
+			return err
+		}
+
+		before, err := r.getUserForUpdate(ctx, tx, verification.UserId)
+		if err != nil {
+			return err
+		}
+
+		var after = new(userSnapshot)
+
+		query = `
+			UPDATE users
+			SET
+				email = ?,
+				pending_email = NULL,
+				email_verified_at = NOW(),
+				updated_at = NOW()
+			WHERE id = ? AND deleted_at IS NULL
+			RETURNING id, name, email, pending_email, avatar_url
+		`
+
+		err = tx.GetContext(ctx, after, r.db.Rebind(query), verification.Email, verification.UserId)
+		if err != nil {
+			pqErr, ok := err.(*pq.Error)
+			if ok && pqErr.Code.Name() == "unique_violation" {
+				log.Warn().Err(err).Any("payload", verification).Msg("repo::VerifyEmail - Email already registered")
+				return errmsg.NewCustomErrors(409, errmsg.WithMessage("Email sudah terdaftar"))
+			}
+			log.Error().Err(err).Any("payload", verification).Msg("repo::VerifyEmail - Failed to update email")
+			return err
+		}
+
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   verification.UserId,
+			Before:     before,
+			After:      after,
+		})
+	})
+}
+
+// UpdatePassword changes the password of the user and returns their new token
+// version, the tokens issued before are rejected by the auth middleware.
+func (r *userRepository) UpdatePassword(ctx context.Context, userId, hashedPassword string) (tokenVersion int, err error) {
+	err = r.withTx(ctx, "UpdatePassword", func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE users
+			SET
+				password = ?,
+				token_version = token_version + 1,
+				updated_at = NOW()
+			WHERE id = ? AND deleted_at IS NULL
+			RETURNING token_version
+		`
+
+		err := tx.GetContext(ctx, &tokenVersion, r.db.Rebind(query), hashedPassword, userId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				log.Warn().Str("user_id", userId).Msg("repo::UpdatePassword - User not found")
+				return errmsg.NewCustomErrors(404, errmsg.WithMessage("User tidak ditemukan"))
+			}
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::UpdatePassword - Failed to update password")
+			return err
+		}
+
+		// the hash itself is never written to the audit log
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionUpdate,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   userId,
+			Before:     map[string]any{"password": "[redacted]"},
+			After:      map[string]any{"password": "[changed]"},
+		})
+	})
+
+	return tokenVersion, err
+}
+
+func (r *userRepository) DeleteAccount(ctx context.Context, userId string) error {
+	return r.withTx(ctx, "DeleteAccount", func(tx *sqlx.Tx) error {
+		before, err := r.getUserForUpdate(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		// the shops go away the way a shop deletion does: their open stock
+		// reservations are released and the product.deleted and shop.deleted
+		// events tell downstream services which orders are affected
+		if _, err = shopRepo.DeleteUserShops(ctx, tx, userId); err != nil {
+			return err
+		}
+
+		query := `
+			DELETE FROM user_email_verifications
+			WHERE user_id = ?
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::DeleteAccount - Failed to delete email verifications")
+			return err
+		}
+
+		// anonymize personal data, the row is kept so foreign keys stay valid
+		query = `
+			UPDATE users
+			SET
+				name = 'Deleted User',
+				email = 'deleted+' || id || '@anonymized.invalid',
+				whatsapp_number = NULL,
+				password = '',
+				avatar_url = NULL,
+				pending_email = NULL,
+				email_verified_at = NULL,
+				token_version = token_version + 1,
+				updated_at = NOW(),
+				deleted_at = NOW()
+			WHERE id = ? AND deleted_at IS NULL
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::DeleteAccount - Failed to anonymize user")
+			return err
+		}
+
+		// the earlier snapshots of the user hold their name and email, and the
+		// entries they made their ip, only the fact that a change happened is kept
+		query = `
+			UPDATE audit_logs
+			SET
+				before = CASE WHEN before IS NULL THEN NULL ELSE '{}' END,
+				after = CASE WHEN after IS NULL THEN NULL ELSE '{}' END,
+				diff = '{}'
+			WHERE entity_type = ? AND entity_id = ?
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), auditEnt.EntityUser, userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::DeleteAccount - Failed to scrub audit snapshots")
+			return err
+		}
+
+		query = `
+			UPDATE audit_logs
+			SET ip = NULL
+			WHERE actor_id = ? AND ip IS NOT NULL
+		`
+
+		if _, err := tx.ExecContext(ctx, r.db.Rebind(query), userId); err != nil {
+			log.Error().Err(err).Str("user_id", userId).Msg("repo::DeleteAccount - Failed to scrub audit ips")
+			return err
+		}
+
+		// personal data is not kept in the trail of a deleted account
+		return auditRepo.Record(ctx, tx, &auditEnt.Entry{
+			Action:     auditEnt.ActionDelete,
+			EntityType: auditEnt.EntityUser,
+			EntityId:   userId,
+			Before:     map[string]any{"id": before.Id},
+		})
+	})
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"codebase-app/internal/infrastructure/config"
+	integStorage "codebase-app/internal/integration/localstorage"
+	integMailer "codebase-app/internal/integration/mailer"
 	integOauth "codebase-app/internal/integration/oauth2google"
 	oauthgoogleent "codebase-app/internal/integration/oauth2google/entity"
 	"codebase-app/internal/module/user/entity"
@@ -9,6 +12,7 @@ import (
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/jwthandler"
 	"context"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -16,15 +20,26 @@ import (
 
 var _ ports.UserService = &userService{}
 
+const emailVerificationTTL = 24 * time.Hour
+
 type userService struct {
-	repo ports.UserRepository
-	o    integOauth.Oauth2googleContract
+	repo    ports.UserRepository
+	o       integOauth.Oauth2googleContract
+	storage integStorage.LocalStorageContract
+	mailer  integMailer.MailerContract
 }
 
-func NewUserService(repo ports.UserRepository, o integOauth.Oauth2googleContract) *userService {
+func NewUserService(
+	repo ports.UserRepository,
+	o integOauth.Oauth2googleContract,
+	storage integStorage.LocalStorageContract,
+	mailer integMailer.MailerContract,
+) *userService {
 	return &userService{
-		repo: repo,
-		o:    o,
+		repo:    repo,
+		o:       o,
+		storage: storage,
+		mailer:  mailer,
 	}
 }
 
@@ -62,6 +77,7 @@ func (s *userService) Login(ctx context.Context, req *entity.LoginRequest) (*ent
 	token, err := jwthandler.GenerateTokenString(jwthandler.CostumClaimsPayload{
 		UserId:          user.Id,
 		Role:            user.Role,
+		TokenVersion:    user.TokenVersion,
 		TokenExpiration: time.Now().Add(time.Hour * 24),
 	})
 	if err != nil {
@@ -107,6 +123,7 @@ func (s *userService) LoginGoogle(ctx context.Context, req *oauthgoogleent.UserI
 	token, err := jwthandler.GenerateTokenString(jwthandler.CostumClaimsPayload{
 		UserId:          user.Id,
 		Role:            user.Role,
+		TokenVersion:    user.TokenVersion,
 		TokenExpiration: time.Now().Add(time.Hour * 24),
 	})
 	if err != nil {
@@ -116,3 +133,125 @@ func (s *userService) LoginGoogle(ctx context.Context, req *oauthgoogleent.UserI
 	res.Token = token
 	return res, nil
 }
+
+func (s *userService) UpdateProfile(ctx context.Context, req *entity.UpdateProfileRequest) (*entity.ProfileResponse, error) {
+	if req.Avatar != nil {
+		publicPath := config.Envs.App.LocalStoragePublicPath
+
+		fullpath, err := s.storage.Save(*req.Avatar, publicPath+"/avatars")
+		if err != nil {
+			if err == integStorage.ErrFileTypeNotSupported {
+				return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("avatar", "avatar harus berupa gambar jpeg atau png."))
+			}
+			log.Error().Err(err).Str("user_id", req.UserId).Msg("service::UpdateProfile - Failed to save avatar")
+			return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("avatar", "avatar tidak valid."))
+		}
+
+		url := config.Envs.App.BaseURL + "/products/storage" + strings.TrimPrefix(fullpath, publicPath)
+		req.AvatarUrl = &url
+	}
+
+	return s.repo.UpdateProfile(ctx, req)
+}
+
+func (s *userService) ChangeEmail(ctx context.Context, req *entity.ChangeEmailRequest) (*entity.ChangeEmailResponse, error) {
+	user, err := s.repo.FindCredentialById(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkg.ComparePassword(user.Pass, req.Password) {
+		log.Warn().Str("user_id", req.UserId).Msg("service::ChangeEmail - Password not match")
+		return nil, errmsg.NewCustomErrors(401, errmsg.WithMessage("Password salah"))
+	}
+
+	if strings.EqualFold(user.Email, req.Email) {
+		return nil, errmsg.NewCustomErrors(400, errmsg.WithErrors("email", "email sama dengan email saat ini."))
+	}
+
+	registered, err := s.repo.IsEmailRegistered(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if registered {
+		log.Warn().Str("user_id", req.UserId).Str("email", req.Email).Msg("service::ChangeEmail - Email already registered")
+		return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Email sudah terdaftar"))
+	}
+
+	token, err := pkg.GenerateRandomToken(32)
+	if err != nil {
+		log.Error().Err(err).Msg("service::ChangeEmail - Failed to generate token")
+		return nil, err
+	}
+
+	err = s.repo.CreateEmailVerification(ctx, req.UserId, req.Email, pkg.HashToken(token), time.Now().Add(emailVerificationTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	link := config.Envs.App.BaseURL + "/products/profile/email/verify?token=" + token
+	body := "Halo " + user.Name + ",\n\nKlik tautan berikut untuk memverifikasi email baru anda:\n" + link +
+		"\n\nTautan ini berlaku selama 24 jam."
+
+	if err := s.mailer.Send(ctx, req.Email, "Verifikasi email baru", body); err != nil {
+		return nil, errmsg.NewCustomErrors(500, errmsg.WithMessage("Gagal mengirim email verifikasi"))
+	}
+
+	return &entity.ChangeEmailResponse{PendingEmail: req.Email}, nil
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, req *entity.VerifyEmailRequest) error {
+	return s.repo.VerifyEmail(ctx, pkg.HashToken(req.Token))
+}
+
+func (s *userService) ChangePassword(ctx context.Context, req *entity.ChangePasswordRequest) (*entity.LoginResponse, error) {
+	user, err := s.repo.FindCredentialById(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkg.ComparePassword(user.Pass, req.CurrentPassword) {
+		log.Warn().Str("user_id", req.UserId).Msg("service::ChangePassword - Password not match")
+		return nil, errmsg.NewCustomErrors(401, errmsg.WithMessage("Password saat ini salah"))
+	}
+
+	hashed, err := pkg.HashPassword(req.NewPassword)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", req.UserId).Msg("service::ChangePassword - Failed to hash password")
+		return nil, errmsg.NewCustomErrors(500, errmsg.WithMessage("Gagal menghash password"))
+	}
+	req.HassedPassword = hashed
+
+	tokenVersion, err := s.repo.UpdatePassword(ctx, req.UserId, req.HassedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// existing tokens are revoked, hand out a fresh one for the current session
+	token, err := jwthandler.GenerateTokenString(jwthandler.CostumClaimsPayload{
+		UserId:          user.Id,
+		Role:            user.Role,
+		TokenVersion:    tokenVersion,
+		TokenExpiration: time.Now().Add(time.Hour * 24),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResponse{Token: token}, nil
+}
+
+func (s *userService) DeleteAccount(ctx context.Context, req *entity.DeleteAccountRequest) error {
+	user, err := s.repo.FindCredentialById(ctx, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !pkg.ComparePassword(user.Pass, req.Password) {
+		log.Warn().Str("user_id", req.UserId).Msg("service::DeleteAccount - Password not match")
+		return errmsg.NewCustomErrors(401, errmsg.WithMessage("Password salah"))
+	}
+
+	return s.repo.DeleteAccount(ctx, req.UserId)
+}
@@ -0,0 +1,191 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed" // gave up after MaxAttempts, can still be redelivered by hand
+
+	// MaxAttempts is how many times a delivery is tried before giving up.
+	MaxAttempts = 10
+
+	// MaxConsecutiveFailures is how many failed attempts in a row disable a
+	// webhook, its owner enables it again once the receiver is fixed.
+	MaxConsecutiveFailures = 20
+
+	minRetryDelay = 10 * time.Second
+	maxRetryDelay = 6 * time.Hour
+)
+
+// EventTypes are the domain events a webhook can subscribe to.
+var EventTypes = []string{
+	"product.created",
+	"product.updated",
+	"product.deleted",
+	"product.restored",
+	"stock.changed",
+	"stock.low",
+	"stock.out",
+	"shop.updated",
+	"shop.status_changed",
+	"shop.deleted",
+	"shop.restored",
+}
+
+// RetryDelay returns how long to wait before trying again a delivery that
+// failed attempts times, doubling from 10 seconds up to 6 hours.
+func RetryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
+
+type CreateWebhookRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId     string   `params:"shop_id" validate:"required,uuid"`
+	Url        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,unique_in_slice,dive,oneof=product.created product.updated product.deleted product.restored stock.changed stock.low stock.out shop.updated shop.status_changed shop.deleted shop.restored"`
+
+	Secret string
+}
+
+type UpdateWebhookRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId     string   `params:"shop_id" validate:"required,uuid"`
+	Id         string   `params:"id" validate:"required,uuid"`
+	Url        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,unique_in_slice,dive,oneof=product.created product.updated product.deleted product.restored stock.changed stock.low stock.out shop.updated shop.status_changed shop.deleted shop.restored"`
+	IsActive   bool     `json:"is_active"` // enabling a disabled webhook resets its failures
+}
+
+type WebhookRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId string `params:"shop_id" validate:"required,uuid"`
+	Id     string `params:"id" validate:"required,uuid"`
+}
+
+type GetWebhooksRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId string `params:"shop_id" validate:"required,uuid"`
+}
+
+type Webhook struct {
+	Id                  string     `json:"id" db:"id"`
+	ShopId              string     `json:"shop_id" db:"shop_id"`
+	Url                 string     `json:"url" db:"url"`
+	EventTypes          []string   `json:"event_types" db:"-"`
+	IsActive            bool       `json:"is_active" db:"is_active"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at" db:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type WebhookWithSecret struct {
+	Webhook
+	Secret string `json:"secret"` // only returned on creation and rotation
+}
+
+type GetDeliveriesRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId   string `params:"shop_id" validate:"required,uuid"`
+	Id       string `params:"id" validate:"required,uuid"`
+	Status   string `query:"status" validate:"omitempty,oneof=pending succeeded failed"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetDeliveriesRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type Delivery struct {
+	Id            string     `json:"id" db:"id"`
+	WebhookId     string     `json:"webhook_id" db:"subscription_id"`
+	EventId       string     `json:"event_id" db:"event_id"`
+	EventType     string     `json:"event_type" db:"event_type"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	ResponseCode  *int       `json:"response_code" db:"response_code"`
+	LastError     *string    `json:"last_error" db:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" db:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+
+	AttemptLogs []Attempt `json:"attempt_logs" db:"-"`
+}
+
+type GetDeliveriesResponse struct {
+	Items []Delivery `json:"items"`
+	Meta  types.Meta `json:"meta"`
+}
+
+type RedeliverRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId     string `params:"shop_id" validate:"required,uuid"`
+	Id         string `params:"id" validate:"required,uuid"`
+	DeliveryId string `params:"delivery_id" validate:"required,uuid"`
+}
+
+// Attempt is a request made for a delivery and what the receiver answered.
+type Attempt struct {
+	ResponseCode *int      `json:"response_code" db:"response_code"`
+	ResponseBody *string   `json:"response_body" db:"response_body"`
+	Error        *string   `json:"error" db:"error"`
+	DurationMs   int       `json:"duration_ms" db:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Job is a delivery claimed by the worker, with what is needed to send it.
+type Job struct {
+	Id             string          `db:"id"`
+	SubscriptionId string          `db:"subscription_id"`
+	EventId        string          `db:"event_id"`
+	EventType      string          `db:"event_type"`
+	Body           json.RawMessage `db:"body"`
+	Attempts       int             `db:"attempts"`
+	Url            string          `db:"url"`
+	Secret         string          `db:"secret"`
+}
+
+// Result is the outcome of one attempt of a Job.
+type Result struct {
+	ResponseCode *int
+	ResponseBody string
+	Error        string
+	Duration     time.Duration
+}
+
+// Succeeded reports whether the receiver acknowledged the delivery with a
+// 2xx status.
+func (r *Result) Succeeded() bool {
+	return r.Error == "" && r.ResponseCode != nil && *r.ResponseCode >= 200 && *r.ResponseCode <= 299
+}
+
+// DeliverResult tells what a worker run did.
+type DeliverResult struct {
+	Succeeded int `json:"succeeded"`
+	Retried   int `json:"retried"`
+	Failed    int `json:"failed"`
+	Disabled  int `json:"disabled"`
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/webhook/entity"
+	"codebase-app/internal/module/webhook/ports"
+	"codebase-app/internal/module/webhook/repository"
+	"codebase-app/internal/module/webhook/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/netguard"
+	"codebase-app/pkg/response"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type webhookHandler struct {
+	service ports.WebhookService
+}
+
+func NewWebhookHandler() *webhookHandler {
+	var (
+		handler = new(webhookHandler)
+		repo    = repository.NewWebhookRepository(adapter.Adapters.ShopeefunPostgres)
+		guard   = netguard.New(config.Envs.Webhook.AllowLoopback)
+		client  = guard.Client(time.Duration(config.Envs.Webhook.Timeout) * time.Second)
+		service = service.NewWebhookService(repo, guard, client, config.Envs.Webhook.BatchSize)
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *webhookHandler) Register(router fiber.Router) {
+	router.Get("/shops/:id/webhooks", middleware.UserIdHeader, h.GetWebhooks)
+	router.Post("/shops/:id/webhooks", middleware.UserIdHeader, h.CreateWebhook)
+	router.Put("/shops/:id/webhooks/:webhook_id", middleware.UserIdHeader, h.UpdateWebhook)
+	router.Delete("/shops/:id/webhooks/:webhook_id", middleware.UserIdHeader, h.DeleteWebhook)
+	router.Post("/shops/:id/webhooks/:webhook_id/rotate-secret", middleware.UserIdHeader, h.RotateSecret)
+	router.Get("/shops/:id/webhooks/:webhook_id/deliveries", middleware.UserIdHeader, h.GetDeliveries)
+	router.Post("/shops/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", middleware.UserIdHeader, h.Redeliver)
+}
+
+func (h *webhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateWebhookRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::CreateWebhook - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateWebhook - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateWebhook(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, "Simpan secret ini, secret tidak akan ditampilkan lagi"))
+}
+
+func (h *webhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetWebhooksRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetWebhooks - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetWebhooks(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UpdateWebhookRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::UpdateWebhook - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateWebhook - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.UpdateWebhook(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	var (
+		req = new(entity.WebhookRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteWebhook - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := h.service.DeleteWebhook(ctx, req); err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *webhookHandler) RotateSecret(c *fiber.Ctx) error {
+	var (
+		req = new(entity.WebhookRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::RotateSecret - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.RotateSecret(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, "Simpan secret ini, secret tidak akan ditampilkan lagi"))
+}
+
+func (h *webhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetDeliveriesRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetDeliveries - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetDeliveries - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetDeliveries(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *webhookHandler) Redeliver(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RedeliverRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.Id = c.Params("webhook_id")
+	req.DeliveryId = c.Params("delivery_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::Redeliver - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.Redeliver(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/webhook/ports"
+	"codebase-app/internal/module/webhook/repository"
+	"codebase-app/internal/module/webhook/service"
+	"codebase-app/pkg/netguard"
+	"codebase-app/pkg/scheduler"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pollInterval is how often due deliveries are looked for
+const pollInterval = 2 * time.Second
+
+type webhookWorker struct {
+	service ports.WebhookService
+}
+
+func NewWebhookWorker() *webhookWorker {
+	var (
+		worker = new(webhookWorker)
+		repo   = repository.NewWebhookRepository(adapter.Adapters.ShopeefunPostgres)
+		guard  = netguard.New(config.Envs.Webhook.AllowLoopback)
+		client = guard.Client(time.Duration(config.Envs.Webhook.Timeout) * time.Second)
+	)
+	worker.service = service.NewWebhookService(repo, guard, client, config.Envs.Webhook.BatchSize)
+
+	return worker
+}
+
+// Start sends the queued webhook deliveries until ctx is done.
+func (w *webhookWorker) Start(ctx context.Context) {
+	scheduler.NewTimer("webhook_delivery", pollInterval).Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		res, err := w.service.Deliver(ctx)
+		if res.Succeeded+res.Retried+res.Failed > 0 {
+			log.Info().Any("result", res).Msg("worker::Webhook - Sent deliveries")
+		}
+
+		return nil, err
+	})
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"codebase-app/internal/module/webhook/entity"
+	"context"
+	"time"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (*entity.Webhook, error)
+	GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, req *entity.WebhookRequest) error
+	RotateSecret(ctx context.Context, req *entity.WebhookRequest, secret string) (*entity.Webhook, error)
+	GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (*entity.GetDeliveriesResponse, error)
+	Redeliver(ctx context.Context, req *entity.RedeliverRequest) (*entity.Delivery, error)
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.Job, error)
+	RecordAttempt(ctx context.Context, job *entity.Job, res *entity.Result, retryAt *time.Time) (disabled bool, err error)
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (*entity.WebhookWithSecret, error)
+	GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, req *entity.WebhookRequest) error
+	RotateSecret(ctx context.Context, req *entity.WebhookRequest) (*entity.WebhookWithSecret, error)
+	GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (*entity.GetDeliveriesResponse, error)
+	Redeliver(ctx context.Context, req *entity.RedeliverRequest) (*entity.Delivery, error)
+
+	Deliver(ctx context.Context) (entity.DeliverResult, error)
+}
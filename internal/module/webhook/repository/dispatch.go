@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"codebase-app/pkg/event"
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type dispatcher struct {
+	db sqlx.ExecerContext
+}
+
+// NewDispatcher returns a publisher queueing a delivery of each event to the
+// active webhooks of its shop subscribed to its type. It runs before the sink
+// of the outbox relay, an event relayed twice is only queued once.
+func NewDispatcher(db sqlx.ExecerContext) event.Publisher {
+	return &dispatcher{db: db}
+}
+
+func (d *dispatcher) Publish(ctx context.Context, events ...event.Event) error {
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, body)
+		SELECT id, $1, $2, $3
+		FROM webhook_subscriptions
+		WHERE shop_id = $4 AND is_active AND $2 = ANY(event_types)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`
+
+	for _, e := range events {
+		shopId := shopIdOf(e)
+		if shopId == "" {
+			continue
+		}
+
+		body, err := json.Marshal(e)
+		if err != nil {
+			log.Error().Err(err).Str("event_id", e.Id).Msg("repository::Dispatch - Failed to marshal event")
+			return err
+		}
+
+		if _, err = d.db.ExecContext(ctx, query, e.Id, e.Type, body, shopId); err != nil {
+			log.Error().Err(err).Str("event_id", e.Id).Msg("repository::Dispatch - Failed to queue deliveries")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// shopIdOf returns the shop an event is about: the aggregate of shop events,
+// the shop_id of the payload of the others.
+func shopIdOf(e event.Event) string {
+	if e.AggregateType == "shop" {
+		return e.AggregateId
+	}
+
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return ""
+	}
+
+	var ref struct {
+		ShopId string `json:"shop_id"`
+	}
+	if err = json.Unmarshal(payload, &ref); err != nil {
+		return ""
+	}
+
+	return ref.ShopId
+}
@@ -0,0 +1,509 @@
+package repository
+
+import (
+	"codebase-app/internal/module/webhook/entity"
+	"codebase-app/internal/module/webhook/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.WebhookRepository = &webhookRepository{}
+
+// maxResponseBody is how much of a receiver response is kept in the
+// delivery log.
+const maxResponseBody = 1024
+
+// responseBody returns the part of a receiver response kept in the delivery
+// log. The response can be anything, it is made valid UTF-8 without NUL, which
+// Postgres rejects in text, and cut on a rune boundary.
+func responseBody(body string) string {
+	body = strings.ToValidUTF8(strings.ReplaceAll(body, "\x00", ""), "\uFFFD")
+	if len(body) <= maxResponseBody {
+		return body
+	}
+
+	end := maxResponseBody
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return body[:end]
+}
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *webhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+type webhookDao struct {
+	entity.Webhook
+	EventTypes pq.StringArray `db:"event_types"`
+}
+
+func (d webhookDao) toEntity() entity.Webhook {
+	res := d.Webhook
+	res.EventTypes = []string(d.EventTypes)
+	return res
+}
+
+const webhookColumns = `
+	id, shop_id, url, event_types, is_active, consecutive_failures, disabled_at, created_at, updated_at
+`
+
+func (r *webhookRepository) CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (*entity.Webhook, error) {
+	var data webhookDao
+
+	query := `
+		INSERT INTO webhook_subscriptions (shop_id, url, event_types, secret)
+		VALUES (?, ?, ?, ?)
+		RETURNING` + webhookColumns
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.ShopId,
+		req.Url,
+		pq.StringArray(req.EventTypes),
+		req.Secret,
+	).StructScan(&data)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", req.ShopId).Msg("repository::CreateWebhook - Failed to create webhook")
+		return nil, err
+	}
+
+	res := data.toEntity()
+	return &res, nil
+}
+
+func (r *webhookRepository) GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) ([]entity.Webhook, error) {
+	var (
+		data = make([]webhookDao, 0)
+		res  = make([]entity.Webhook, 0)
+	)
+
+	query := `
+		SELECT` + webhookColumns + `
+		FROM webhook_subscriptions
+		WHERE shop_id = ?
+		ORDER BY created_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.ShopId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetWebhooks - Failed to get webhooks")
+		return nil, err
+	}
+
+	for _, d := range data {
+		res = append(res, d.toEntity())
+	}
+
+	return res, nil
+}
+
+// UpdateWebhook changes a webhook. Enabling it again clears the failures that
+// disabled it.
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (*entity.Webhook, error) {
+	var data webhookDao
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET
+			url = ?,
+			event_types = ?,
+			is_active = ?,
+			consecutive_failures = CASE WHEN ? AND NOT is_active THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN ? THEN NULL ELSE disabled_at END,
+			updated_at = NOW()
+		WHERE id = ? AND shop_id = ?
+		RETURNING` + webhookColumns
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.Url,
+		pq.StringArray(req.EventTypes),
+		req.IsActive,
+		req.IsActive,
+		req.IsActive,
+		req.Id,
+		req.ShopId,
+	).StructScan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::UpdateWebhook - Webhook not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Webhook tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateWebhook - Failed to update webhook")
+		return nil, err
+	}
+
+	res := data.toEntity()
+	return &res, nil
+}
+
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, req *entity.WebhookRequest) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = ? AND shop_id = ?`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.ShopId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteWebhook - Failed to delete webhook")
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		log.Warn().Any("payload", req).Msg("repository::DeleteWebhook - Webhook not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Webhook tidak ditemukan"))
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) RotateSecret(ctx context.Context, req *entity.WebhookRequest, secret string) (*entity.Webhook, error) {
+	var data webhookDao
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET secret = ?, updated_at = NOW()
+		WHERE id = ? AND shop_id = ?
+		RETURNING` + webhookColumns
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), secret, req.Id, req.ShopId).StructScan(&data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::RotateSecret - Webhook not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Webhook tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::RotateSecret - Failed to rotate secret")
+		return nil, err
+	}
+
+	res := data.toEntity()
+	return &res, nil
+}
+
+const deliveryColumns = `
+	d.id, d.subscription_id, d.event_id, d.event_type, d.status, d.attempts, d.response_code,
+	d.last_error, d.next_attempt_at, d.delivered_at, d.created_at
+`
+
+// GetDeliveries lists the deliveries of a webhook, newest first, with the
+// attempts made for each.
+func (r *webhookRepository) GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (*entity.GetDeliveriesResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.Delivery
+	}
+
+	var (
+		resp = new(entity.GetDeliveriesResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.Delivery, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(d.id) OVER() AS total_data,` + deliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE
+			d.subscription_id = ?
+			AND s.shop_id = ?
+			AND (? = '' OR d.status = ?)
+		ORDER BY d.created_at DESC, d.id
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.Id,
+		req.ShopId,
+		req.Status,
+		req.Status,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetDeliveries - Failed to get deliveries")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	var (
+		ids   = make([]string, 0, len(data))
+		index = make(map[string]int, len(data))
+	)
+
+	for i, d := range data {
+		d.AttemptLogs = make([]entity.Attempt, 0)
+		resp.Items = append(resp.Items, d.Delivery)
+		ids = append(ids, d.Id)
+		index[d.Id] = i
+	}
+
+	if len(ids) > 0 {
+		attempts := make([]struct {
+			DeliveryId string `db:"delivery_id"`
+			entity.Attempt
+		}, 0)
+
+		query = `
+			SELECT delivery_id, response_code, response_body, error, duration_ms, created_at
+			FROM webhook_delivery_attempts
+			WHERE delivery_id = ANY(?)
+			ORDER BY id
+		`
+
+		if err = r.db.SelectContext(ctx, &attempts, r.db.Rebind(query), pq.StringArray(ids)); err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::GetDeliveries - Failed to get attempts")
+			return nil, err
+		}
+
+		for _, a := range attempts {
+			item := &resp.Items[index[a.DeliveryId]]
+			item.AttemptLogs = append(item.AttemptLogs, a.Attempt)
+		}
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// Redeliver queues a delivery again right away with a fresh set of attempts,
+// whatever its status.
+func (r *webhookRepository) Redeliver(ctx context.Context, req *entity.RedeliverRequest) (*entity.Delivery, error) {
+	var (
+		res      = new(entity.Delivery)
+		isActive bool
+	)
+
+	query := `
+		SELECT s.is_active
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.id = ? AND d.subscription_id = ? AND s.shop_id = ?
+	`
+
+	err := r.db.GetContext(ctx, &isActive, r.db.Rebind(query), req.DeliveryId, req.Id, req.ShopId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("payload", req).Msg("repository::Redeliver - Delivery not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Delivery tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::Redeliver - Failed to get delivery")
+		return nil, err
+	}
+
+	if !isActive {
+		log.Warn().Any("payload", req).Msg("repository::Redeliver - Webhook is disabled")
+		return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Webhook tidak aktif, aktifkan kembali sebelum mengirim ulang"))
+	}
+
+	query = `
+		UPDATE webhook_deliveries d
+		SET
+			status = 'pending',
+			attempts = 0,
+			next_attempt_at = NOW(),
+			locked_until = NULL,
+			updated_at = NOW()
+		WHERE d.id = ?
+		RETURNING` + deliveryColumns
+
+	if err = r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.DeliveryId).StructScan(res); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::Redeliver - Failed to queue delivery")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *webhookRepository) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	var isOwner bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM shops
+			WHERE
+				user_id = ?
+				AND id = ?
+				AND deleted_at IS NULL
+		)
+	`
+
+	err := r.db.GetContext(ctx, &isOwner, r.db.Rebind(query), userId, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userId).Str("shop_id", shopId).Msg("repository::IsShopOwner - Failed to check shop owner")
+		return false, err
+	}
+
+	return isOwner, nil
+}
+
+// ClaimDeliveries leases up to limit due deliveries of the active webhooks,
+// oldest first. A lease outliving a crashed worker lets another one send it.
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.Job, error) {
+	var jobs = make([]entity.Job, 0, limit)
+
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET locked_until = NOW() + ? * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT d.id
+				FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE
+					d.status = 'pending'
+					AND d.next_attempt_at <= NOW()
+					AND (d.locked_until IS NULL OR d.locked_until < NOW())
+					AND s.is_active
+				ORDER BY d.next_attempt_at
+				LIMIT ?
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING id, subscription_id, event_id, event_type, body, attempts
+		)
+		SELECT c.*, s.url, s.secret
+		FROM claimed c
+		JOIN webhook_subscriptions s ON s.id = c.subscription_id
+	`
+
+	if err := r.db.SelectContext(ctx, &jobs, r.db.Rebind(query), lease.Milliseconds(), limit); err != nil {
+		log.Error().Err(err).Msg("repository::ClaimDeliveries - Failed to claim deliveries")
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// RecordAttempt logs an attempt of a delivery and moves it to its next
+// status: succeeded, pending until retryAt, or failed when retryAt is nil.
+// The webhook is disabled once too many attempts failed in a row, disabled
+// tells whether this attempt did it.
+func (r *webhookRepository) RecordAttempt(ctx context.Context, job *entity.Job, res *entity.Result, retryAt *time.Time) (disabled bool, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("delivery_id", job.Id).Msg("repository::RecordAttempt - Failed to begin transaction")
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::RecordAttempt - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::RecordAttempt - Failed to commit transaction")
+		}
+	}()
+
+	var (
+		body      = responseBody(res.ResponseBody)
+		errorText *string
+	)
+
+	if res.Error != "" {
+		errorText = &res.Error
+	}
+
+	query := `
+		INSERT INTO webhook_delivery_attempts (delivery_id, response_code, response_body, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), job.Id, res.ResponseCode, body, errorText, res.Duration.Milliseconds())
+	if err != nil {
+		log.Error().Err(err).Str("delivery_id", job.Id).Msg("repository::RecordAttempt - Failed to insert attempt")
+		return false, err
+	}
+
+	succeeded := res.Succeeded()
+
+	status := entity.DeliveryFailed
+	switch {
+	case succeeded:
+		status = entity.DeliverySucceeded
+	case retryAt != nil:
+		status = entity.DeliveryPending
+	}
+
+	lastError := errorText
+	if !succeeded && lastError == nil && res.ResponseCode != nil {
+		text := "receiver responded " + strconv.Itoa(*res.ResponseCode)
+		lastError = &text
+	}
+
+	query = `
+		UPDATE webhook_deliveries
+		SET
+			status = ?,
+			attempts = attempts + 1,
+			response_code = ?,
+			last_error = ?,
+			next_attempt_at = COALESCE(?, next_attempt_at),
+			locked_until = NULL,
+			delivered_at = CASE WHEN ? THEN NOW() ELSE delivered_at END,
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, r.db.Rebind(query), status, res.ResponseCode, lastError, retryAt, succeeded, job.Id)
+	if err != nil {
+		log.Error().Err(err).Str("delivery_id", job.Id).Msg("repository::RecordAttempt - Failed to update delivery")
+		return false, err
+	}
+
+	if succeeded {
+		query = `
+			UPDATE webhook_subscriptions
+			SET consecutive_failures = 0
+			WHERE id = ? AND consecutive_failures > 0
+		`
+
+		if _, err = tx.ExecContext(ctx, r.db.Rebind(query), job.SubscriptionId); err != nil {
+			log.Error().Err(err).Str("delivery_id", job.Id).Msg("repository::RecordAttempt - Failed to reset failures")
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	query = `
+		UPDATE webhook_subscriptions
+		SET
+			consecutive_failures = consecutive_failures + 1,
+			is_active = consecutive_failures + 1 < ?,
+			disabled_at = CASE WHEN consecutive_failures + 1 >= ? THEN NOW() ELSE disabled_at END,
+			updated_at = NOW()
+		WHERE id = ? AND is_active
+		RETURNING NOT is_active
+	`
+
+	err = tx.GetContext(ctx, &disabled, r.db.Rebind(query), entity.MaxConsecutiveFailures, entity.MaxConsecutiveFailures, job.SubscriptionId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// disabled meanwhile by its owner or another attempt
+			err = nil
+			return false, nil
+		}
+		log.Error().Err(err).Str("delivery_id", job.Id).Msg("repository::RecordAttempt - Failed to count failure")
+		return false, err
+	}
+
+	return disabled, nil
+}
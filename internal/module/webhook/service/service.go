@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"codebase-app/internal/module/webhook/entity"
+	"codebase-app/internal/module/webhook/ports"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/netguard"
+	"codebase-app/pkg/signature"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// secretPrefix is prepended to every generated secret so leaked ones are
+	// easy to spot.
+	secretPrefix = "whsec_"
+
+	// deliverLease is how long a claimed delivery is kept from other workers,
+	// longer than a request can take.
+	deliverLease = time.Minute
+)
+
+var _ ports.WebhookService = &webhookService{}
+
+type webhookService struct {
+	repo      ports.WebhookRepository
+	guard     *netguard.Guard
+	client    *http.Client // dials through guard too, the url can resolve elsewhere after it was checked
+	batchSize int
+}
+
+func NewWebhookService(repo ports.WebhookRepository, guard *netguard.Guard, client *http.Client, batchSize int) *webhookService {
+	return &webhookService{
+		repo:      repo,
+		guard:     guard,
+		client:    client,
+		batchSize: batchSize,
+	}
+}
+
+func (s *webhookService) checkShopOwner(ctx context.Context, userId, shopId string) error {
+	isOwner, err := s.repo.IsShopOwner(ctx, userId, shopId)
+	if err != nil {
+		return err
+	}
+
+	if !isOwner {
+		log.Warn().Str("user_id", userId).Str("shop_id", shopId).Msg("service::checkShopOwner - User is not shop owner")
+		return errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return nil
+}
+
+// checkUrl rejects a webhook url resolving to an internal address, like the
+// cloud metadata service, so webhooks cannot be used to reach the network of
+// the service.
+func (s *webhookService) checkUrl(ctx context.Context, url string) error {
+	if err := s.guard.CheckURL(ctx, url); err != nil {
+		log.Warn().Err(err).Str("url", url).Msg("service::checkUrl - Url is not allowed")
+		return errmsg.NewCustomErrors(400, errmsg.WithErrors("url", "url must resolve to a public address."))
+	}
+
+	return nil
+}
+
+func newSecret() (string, error) {
+	secret, err := pkg.GenerateRandomToken(24)
+	if err != nil {
+		log.Error().Err(err).Msg("service::newSecret - Failed to generate secret")
+		return "", err
+	}
+
+	return secretPrefix + secret, nil
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (*entity.WebhookWithSecret, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUrl(ctx, req.Url); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	req.Secret = secret
+
+	webhook, err := s.repo.CreateWebhook(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.WebhookWithSecret{Webhook: *webhook, Secret: secret}, nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) ([]entity.Webhook, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetWebhooks(ctx, req)
+}
+
+func (s *webhookService) UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (*entity.Webhook, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUrl(ctx, req.Url); err != nil {
+		return nil, err
+	}
+
+	return s.repo.UpdateWebhook(ctx, req)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, req *entity.WebhookRequest) error {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteWebhook(ctx, req)
+}
+
+func (s *webhookService) RotateSecret(ctx context.Context, req *entity.WebhookRequest) (*entity.WebhookWithSecret, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.repo.RotateSecret(ctx, req, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.WebhookWithSecret{Webhook: *webhook, Secret: secret}, nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (*entity.GetDeliveriesResponse, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDeliveries(ctx, req)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, req *entity.RedeliverRequest) (*entity.Delivery, error) {
+	if err := s.checkShopOwner(ctx, req.UserId, req.ShopId); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Redeliver(ctx, req)
+}
+
+// Deliver sends the due deliveries until none is left. A failed delivery is
+// tried again later with a growing delay, until it is given up after
+// MaxAttempts. A delivery whose attempt could not be recorded is sent again
+// once its lease expires, the rest of the batch goes on.
+func (s *webhookService) Deliver(ctx context.Context) (entity.DeliverResult, error) {
+	var res entity.DeliverResult
+
+	for ctx.Err() == nil {
+		jobs, err := s.repo.ClaimDeliveries(ctx, s.batchSize, deliverLease)
+		if err != nil {
+			return res, err
+		}
+
+		for i := range jobs {
+			s.deliver(ctx, &jobs[i], &res)
+		}
+
+		if len(jobs) < s.batchSize {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (s *webhookService) deliver(ctx context.Context, job *entity.Job, res *entity.DeliverResult) {
+	var (
+		result   = s.send(ctx, job)
+		attempts = job.Attempts + 1
+		retryAt  *time.Time
+	)
+
+	if !result.Succeeded() && attempts < entity.MaxAttempts {
+		next := time.Now().Add(entity.RetryDelay(attempts))
+		retryAt = &next
+	}
+
+	disabled, err := s.repo.RecordAttempt(ctx, job, &result, retryAt)
+	if err != nil {
+		log.Error().Err(err).Str("delivery_id", job.Id).Msg("service::Deliver - Failed to record attempt")
+		return
+	}
+
+	switch {
+	case result.Succeeded():
+		res.Succeeded++
+	case retryAt != nil:
+		res.Retried++
+		log.Warn().Str("delivery_id", job.Id).Any("code", result.ResponseCode).Str("error", result.Error).Int("attempts", attempts).Msg("service::Deliver - Delivery failed")
+	default:
+		res.Failed++
+		log.Warn().Str("delivery_id", job.Id).Any("code", result.ResponseCode).Str("error", result.Error).Msg("service::Deliver - Giving up on delivery")
+	}
+
+	if disabled {
+		res.Disabled++
+		log.Warn().Str("webhook_id", job.SubscriptionId).Msg("service::Deliver - Webhook disabled after too many failures")
+	}
+}
+
+// send posts the event of a delivery to its webhook, signed with the secret
+// of the webhook.
+func (s *webhookService) send(ctx context.Context, job *entity.Job) entity.Result {
+	var (
+		result    entity.Result
+		start     = time.Now()
+		timestamp = strconv.FormatInt(start.Unix(), 10)
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Url, bytes.NewReader(job.Body))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "shopeefun-webhook/1.0")
+	req.Header.Set(signature.HeaderWebhookId, job.Id)
+	req.Header.Set(signature.HeaderWebhookEvent, job.EventType)
+	req.Header.Set(signature.HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(signature.HeaderWebhookSignature, signature.SignWebhook(job.Secret, timestamp, job.Body))
+
+	resp, err := s.client.Do(req)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	result.ResponseCode = &resp.StatusCode
+	result.ResponseBody = string(body)
+
+	return result
+}
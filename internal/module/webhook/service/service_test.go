@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"codebase-app/internal/module/webhook/entity"
+	mockPort "codebase-app/mock/module/webhook/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/netguard"
+	"codebase-app/pkg/signature"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceList struct {
+	suite.Suite
+	mockWebhookRepo *mockPort.MockWebhookRepo
+	service         *webhookService
+}
+
+func (suite *ServiceList) SetupTest() {
+	// the test receivers listen on the loopback
+	guard := netguard.New(true)
+
+	suite.mockWebhookRepo = mockPort.NewMockWebhookRepo()
+	suite.service = NewWebhookService(suite.mockWebhookRepo, guard, guard.Client(time.Second), 10)
+}
+
+func newJob(id, url string) entity.Job {
+	body, _ := json.Marshal(map[string]any{"id": "01J9", "type": "stock.changed"})
+
+	return entity.Job{
+		Id:             id,
+		SubscriptionId: "webhook-1",
+		EventId:        "01J9",
+		EventType:      "stock.changed",
+		Body:           body,
+		Url:            url,
+		Secret:         "whsec_test",
+	}
+}
+
+// receiver is a webhook endpoint answering with status and counting the
+// deliveries with a valid signature.
+func (suite *ServiceList) receiver(status int) (*httptest.Server, *atomic.Int32) {
+	var valid atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if signature.VerifyWebhook("whsec_test", r.Header.Get(signature.HeaderWebhookSignature), r.Header.Get(signature.HeaderWebhookTimestamp), body) {
+			valid.Add(1)
+		}
+		suite.Equal("stock.changed", r.Header.Get(signature.HeaderWebhookEvent))
+
+		if status == http.StatusFound {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", status)
+			return
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	suite.T().Cleanup(srv.Close)
+
+	return srv, &valid
+}
+
+// onRecord records the result of the attempts of the delivery id.
+func (suite *ServiceList) onRecord(id string, retryAt any, disabled bool, err error) *[]entity.Result {
+	var results []entity.Result
+
+	suite.mockWebhookRepo.On("RecordAttempt", mock.Anything, mock.MatchedBy(func(job *entity.Job) bool {
+		return job.Id == id
+	}), mock.Anything, retryAt).Run(func(args mock.Arguments) {
+		results = append(results, *args.Get(2).(*entity.Result))
+	}).Return(disabled, err)
+
+	return &results
+}
+
+func isRetryAt(attempts int) any {
+	return mock.MatchedBy(func(retryAt *time.Time) bool {
+		return retryAt != nil && time.Until(*retryAt).Round(time.Second) == entity.RetryDelay(attempts)
+	})
+}
+
+// Testing Deliver
+
+func (suite *ServiceList) TestDeliver_SignsRequest() {
+	ctx := context.Background()
+	srv, valid := suite.receiver(http.StatusNoContent)
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{newJob("delivery-1", srv.URL)}, nil).Once()
+	results := suite.onRecord("delivery-1", (*time.Time)(nil), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Succeeded: 1}, res)
+	suite.Equal(int32(1), valid.Load())
+	if suite.Len(*results, 1) && suite.NotNil((*results)[0].ResponseCode) {
+		suite.Equal(http.StatusNoContent, *(*results)[0].ResponseCode)
+	}
+}
+
+func (suite *ServiceList) TestDeliver_RetriesWithBackoff() {
+	ctx := context.Background()
+	srv, _ := suite.receiver(http.StatusServiceUnavailable)
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{newJob("delivery-1", srv.URL)}, nil).Once()
+	suite.onRecord("delivery-1", isRetryAt(1), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Retried: 1}, res)
+	suite.mockWebhookRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestDeliver_GivesUpAfterMaxAttempts() {
+	ctx := context.Background()
+	srv, _ := suite.receiver(http.StatusInternalServerError)
+	job := newJob("delivery-1", srv.URL)
+	job.Attempts = entity.MaxAttempts - 1
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{job}, nil).Once()
+	suite.onRecord("delivery-1", (*time.Time)(nil), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Failed: 1}, res)
+	suite.mockWebhookRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestDeliver_DisablesFailingWebhook() {
+	ctx := context.Background()
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{newJob("delivery-1", "http://127.0.0.1:1")}, nil).Once()
+	results := suite.onRecord("delivery-1", isRetryAt(1), true, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Retried: 1, Disabled: 1}, res)
+	if suite.Len(*results, 1) {
+		suite.Nil((*results)[0].ResponseCode)
+		suite.NotEmpty((*results)[0].Error)
+	}
+}
+
+func (suite *ServiceList) TestDeliver_DoesNotFollowRedirects() {
+	ctx := context.Background()
+	srv, _ := suite.receiver(http.StatusFound)
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{newJob("delivery-1", srv.URL)}, nil).Once()
+	results := suite.onRecord("delivery-1", isRetryAt(1), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Retried: 1}, res)
+	if suite.Len(*results, 1) && suite.NotNil((*results)[0].ResponseCode) {
+		suite.Equal(http.StatusFound, *(*results)[0].ResponseCode)
+	}
+}
+
+func (suite *ServiceList) TestDeliver_RefusesInternalAddress() {
+	ctx := context.Background()
+	srv, valid := suite.receiver(http.StatusNoContent)
+	guard := netguard.New(false)
+	suite.service = NewWebhookService(suite.mockWebhookRepo, guard, guard.Client(time.Second), 10)
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{newJob("delivery-1", srv.URL)}, nil).Once()
+	results := suite.onRecord("delivery-1", isRetryAt(1), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Retried: 1}, res)
+	suite.Equal(int32(0), valid.Load())
+	if suite.Len(*results, 1) {
+		suite.Contains((*results)[0].Error, netguard.ErrForbiddenAddress.Error())
+	}
+}
+
+func (suite *ServiceList) TestDeliver_RecordErrorKeepsBatchGoing() {
+	ctx := context.Background()
+	srv, valid := suite.receiver(http.StatusNoContent)
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return([]entity.Job{
+		newJob("delivery-1", srv.URL),
+		newJob("delivery-2", srv.URL),
+	}, nil).Once()
+	suite.onRecord("delivery-1", (*time.Time)(nil), false, errors.New("error"))
+	suite.onRecord("delivery-2", (*time.Time)(nil), false, nil)
+	res, err := suite.service.Deliver(ctx)
+
+	suite.Nil(err)
+	suite.Equal(entity.DeliverResult{Succeeded: 1}, res)
+	suite.Equal(int32(2), valid.Load())
+}
+
+func (suite *ServiceList) TestDeliver_ClaimError() {
+	ctx := context.Background()
+
+	suite.mockWebhookRepo.On("ClaimDeliveries", ctx, 10, deliverLease).Return(nil, errors.New("error"))
+	_, err := suite.service.Deliver(ctx)
+
+	suite.Equal(errors.New("error"), err)
+	suite.mockWebhookRepo.AssertNotCalled(suite.T(), "RecordAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Testing CreateWebhook
+
+func (suite *ServiceList) TestCreateWebhook_ReturnsSecret() {
+	ctx := context.Background()
+	req := &entity.CreateWebhookRequest{UserId: "owner", ShopId: "shop", Url: "https://93.184.216.34/hook", EventTypes: []string{"stock.changed"}}
+
+	suite.mockWebhookRepo.On("IsShopOwner", ctx, "owner", "shop").Return(true, nil)
+	suite.mockWebhookRepo.On("CreateWebhook", ctx, req).Return(&entity.Webhook{Id: "webhook-1", Url: req.Url}, nil)
+	resp, err := suite.service.CreateWebhook(ctx, req)
+
+	suite.Nil(err)
+	suite.True(strings.HasPrefix(resp.Secret, secretPrefix))
+	suite.Equal(resp.Secret, req.Secret)
+}
+
+func (suite *ServiceList) TestCreateWebhook_NotShopOwner() {
+	ctx := context.Background()
+	req := &entity.CreateWebhookRequest{UserId: "other", ShopId: "shop", Url: "https://93.184.216.34/hook", EventTypes: []string{"stock.changed"}}
+
+	suite.mockWebhookRepo.On("IsShopOwner", ctx, "other", "shop").Return(false, nil)
+	_, err := suite.service.CreateWebhook(ctx, req)
+
+	customErr, ok := err.(*errmsg.CustomError)
+	suite.True(ok)
+	suite.Equal(403, customErr.Code)
+	suite.mockWebhookRepo.AssertNotCalled(suite.T(), "CreateWebhook", mock.Anything, mock.Anything)
+}
+
+func (suite *ServiceList) TestCreateWebhook_InternalUrl() {
+	ctx := context.Background()
+	guard := netguard.New(false)
+	suite.service = NewWebhookService(suite.mockWebhookRepo, guard, guard.Client(time.Second), 10)
+
+	suite.mockWebhookRepo.On("IsShopOwner", ctx, "owner", "shop").Return(true, nil)
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://10.0.0.5/hook", "http://127.0.0.1:8080/hook"} {
+		req := &entity.CreateWebhookRequest{UserId: "owner", ShopId: "shop", Url: url, EventTypes: []string{"stock.changed"}}
+		_, err := suite.service.CreateWebhook(ctx, req)
+
+		customErr, ok := err.(*errmsg.CustomError)
+		suite.True(ok, url)
+		suite.Equal(400, customErr.Code, url)
+	}
+	suite.mockWebhookRepo.AssertNotCalled(suite.T(), "CreateWebhook", mock.Anything, mock.Anything)
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceList))
+}
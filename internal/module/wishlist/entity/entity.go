@@ -0,0 +1,102 @@
+package entity
+
+import (
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/pkg/types"
+	"time"
+)
+
+const (
+	EventPriceDropped = "wishlist.price_dropped"
+	EventBackInStock  = "wishlist.back_in_stock"
+)
+
+type WishlistRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ProductId string `params:"product_id" validate:"required,uuid"`
+}
+
+type WishlistResponse struct {
+	ProductId  string `json:"product_id"`
+	Wishlisted bool   `json:"wishlisted"`
+}
+
+type GetWishlistRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	Page     int `query:"page" validate:"required"`
+	Paginate int `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetWishlistRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type WishlistItem struct {
+	ProductId string    `json:"product_id" db:"product_id"`
+	AddedAt   time.Time `json:"added_at" db:"created_at"`
+
+	// nil when the product was deleted or its shop is no longer public
+	Product *productEnt.Product `json:"product" db:"-"`
+}
+
+type GetWishlistResponse struct {
+	Items []WishlistItem `json:"items"`
+	Meta  types.Meta     `json:"meta"`
+}
+
+type GetWishlistCountsRequest struct {
+	UserId string `validate:"required,uuid"`
+
+	ShopId   string `params:"id" validate:"required,uuid"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required,max=100"`
+}
+
+func (r *GetWishlistCountsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type WishlistCount struct {
+	ProductId     string `json:"product_id" db:"product_id"`
+	Name          string `json:"name" db:"name"`
+	WishlistCount int    `json:"wishlist_count" db:"wishlist_count"`
+}
+
+type GetWishlistCountsResponse struct {
+	Items []WishlistCount `json:"items"`
+	Meta  types.Meta      `json:"meta"`
+}
+
+// Change is a wishlisted product whose price or availability differs from
+// what the user was last notified about.
+type Change struct {
+	UserId     string      `db:"user_id"`
+	ProductId  string      `db:"product_id"`
+	Currency   string      `db:"currency"`
+	OldPrice   types.Money `db:"old_price"`
+	NewPrice   types.Money `db:"new_price"`
+	WasInStock bool        `db:"was_in_stock"`
+	InStock    bool        `db:"in_stock"`
+}
+
+func (c *Change) PriceDropped() bool {
+	return c.NewPrice.Cmp(c.OldPrice) < 0
+}
+
+func (c *Change) BackInStock() bool {
+	return !c.WasInStock && c.InStock
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	outboxRepo "codebase-app/internal/module/outbox/repository"
+	productRepo "codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/wishlist/entity"
+	"codebase-app/internal/module/wishlist/ports"
+	"codebase-app/internal/module/wishlist/repository"
+	"codebase-app/internal/module/wishlist/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+type wishlistHandler struct {
+	service ports.WishlistService
+}
+
+func NewWishlistHandler() *wishlistHandler {
+	var (
+		handler = new(wishlistHandler)
+		db      = adapter.Adapters.ShopeefunPostgres
+		repo    = repository.NewWishlistRepository(db)
+		service = service.NewWishlistService(repo, productRepo.NewProductRepository(db), outboxRepo.NewPublisher(db))
+	)
+	handler.service = service
+
+	return handler
+}
+
+func (h *wishlistHandler) Register(router fiber.Router) {
+	router.Get("/wishlist", middleware.AuthBearer, h.GetWishlist)
+	router.Post("/wishlist/:product_id", middleware.AuthBearer, h.AddToWishlist)
+	router.Delete("/wishlist/:product_id", middleware.AuthBearer, h.RemoveFromWishlist)
+	router.Get("/shops/:id/wishlist-counts", middleware.UserIdHeader, h.GetWishlistCounts)
+}
+
+func (h *wishlistHandler) GetWishlist(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetWishlistRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetWishlist - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetWishlist - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetWishlist(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *wishlistHandler) AddToWishlist(c *fiber.Ctx) error {
+	return h.wishlist(c, true)
+}
+
+func (h *wishlistHandler) RemoveFromWishlist(c *fiber.Ctx) error {
+	return h.wishlist(c, false)
+}
+
+func (h *wishlistHandler) wishlist(c *fiber.Ctx, add bool) error {
+	var (
+		req = new(entity.WishlistRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	req.UserId = l.UserId
+	req.ProductId = c.Params("product_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::Wishlist - Validate request params")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	var (
+		resp *entity.WishlistResponse
+		err  error
+	)
+	if add {
+		resp, err = h.service.AddToWishlist(ctx, req)
+	} else {
+		resp, err = h.service.RemoveFromWishlist(ctx, req)
+	}
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *wishlistHandler) GetWishlistCounts(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetWishlistCountsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::GetWishlistCounts - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetWishlistCounts - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetWishlistCounts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
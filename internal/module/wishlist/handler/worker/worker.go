@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"codebase-app/internal/adapter"
+	outboxRepo "codebase-app/internal/module/outbox/repository"
+	productRepo "codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/wishlist/ports"
+	"codebase-app/internal/module/wishlist/repository"
+	"codebase-app/internal/module/wishlist/service"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// interval between two scans for price drops and restocks of wishlisted
+// products
+const interval = time.Minute
+
+type wishlistWorker struct {
+	service ports.WishlistService
+}
+
+func NewWishlistWorker() *wishlistWorker {
+	var (
+		worker  = new(wishlistWorker)
+		db      = adapter.Adapters.ShopeefunPostgres
+		repo    = repository.NewWishlistRepository(db)
+		service = service.NewWishlistService(repo, productRepo.NewProductRepository(db), outboxRepo.NewPublisher(db))
+	)
+	worker.service = service
+
+	return worker
+}
+
+// Start notifies wishlist changes every interval until ctx is done.
+func (w *wishlistWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("worker::Wishlist - Stopped")
+			return
+		case <-ticker.C:
+			n, err := w.service.NotifyChanges(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("worker::Wishlist - Failed to notify changes")
+				continue
+			}
+
+			if n > 0 {
+				log.Info().Int("total", n).Msg("worker::Wishlist - Notified changes")
+			}
+		}
+	}
+}
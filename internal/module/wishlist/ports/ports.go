@@ -0,0 +1,30 @@
+package ports
+
+import (
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/wishlist/entity"
+	"context"
+)
+
+type WishlistRepository interface {
+	AddToWishlist(ctx context.Context, req *entity.WishlistRequest) error
+	RemoveFromWishlist(ctx context.Context, req *entity.WishlistRequest) error
+	GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (*entity.GetWishlistResponse, error)
+	GetWishlistCounts(ctx context.Context, req *entity.GetWishlistCountsRequest) (*entity.GetWishlistCountsResponse, error)
+	DetectChanges(ctx context.Context, limit int, notify func([]entity.Change) error) (int, error)
+}
+
+// ProductCatalog is the part of the product repository the wishlist reads
+// from, so listed items are priced and filtered exactly like GetProducts.
+type ProductCatalog interface {
+	GetProducts(ctx context.Context, req *productEnt.GetProductsRequest) (productEnt.GetProductsResponse, error)
+	IsShopOwner(ctx context.Context, userId, shopId string) (bool, error)
+}
+
+type WishlistService interface {
+	AddToWishlist(ctx context.Context, req *entity.WishlistRequest) (*entity.WishlistResponse, error)
+	RemoveFromWishlist(ctx context.Context, req *entity.WishlistRequest) (*entity.WishlistResponse, error)
+	GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (*entity.GetWishlistResponse, error)
+	GetWishlistCounts(ctx context.Context, req *entity.GetWishlistCountsRequest) (*entity.GetWishlistCountsResponse, error)
+	NotifyChanges(ctx context.Context) (int, error)
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	productRepo "codebase-app/internal/module/product/repository"
+	"codebase-app/internal/module/wishlist/entity"
+	"codebase-app/internal/module/wishlist/ports"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.WishlistRepository = &wishlistRepository{}
+
+type wishlistRepository struct {
+	db *sqlx.DB
+}
+
+func NewWishlistRepository(db *sqlx.DB) *wishlistRepository {
+	return &wishlistRepository{
+		db: db,
+	}
+}
+
+func (r *wishlistRepository) AddToWishlist(ctx context.Context, req *entity.WishlistRequest) error {
+	var exists bool
+
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE id = ? AND deleted_at IS NULL)`
+
+	if err := r.db.GetContext(ctx, &exists, r.db.Rebind(query), req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::AddToWishlist - Failed to check product")
+		return err
+	}
+
+	if !exists {
+		log.Warn().Any("payload", req).Msg("repository::AddToWishlist - Product not found")
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Product not found"))
+	}
+
+	// the current price and stock are the baseline for change notifications
+	query = `
+		INSERT INTO wishlist_items (user_id, product_id, last_price, last_in_stock)
+		SELECT ?, id, ` + productRepo.EffectivePriceSQL + `, stock > 0
+		FROM products
+		WHERE id = ?
+		ON CONFLICT (user_id, product_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.UserId, req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::AddToWishlist - Failed to insert wishlist item")
+		return err
+	}
+
+	return nil
+}
+
+func (r *wishlistRepository) RemoveFromWishlist(ctx context.Context, req *entity.WishlistRequest) error {
+	query := `DELETE FROM wishlist_items WHERE user_id = ? AND product_id = ?`
+
+	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.UserId, req.ProductId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RemoveFromWishlist - Failed to delete wishlist item")
+		return err
+	}
+
+	return nil
+}
+
+func (r *wishlistRepository) GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (*entity.GetWishlistResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.WishlistItem
+	}
+
+	var (
+		resp = new(entity.GetWishlistResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.WishlistItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() as total_data,
+			product_id,
+			created_at
+		FROM wishlist_items
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.UserId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetWishlist - Failed to get wishlist")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.WishlistItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *wishlistRepository) GetWishlistCounts(ctx context.Context, req *entity.GetWishlistCountsRequest) (*entity.GetWishlistCountsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.WishlistCount
+	}
+
+	var (
+		resp = new(entity.GetWishlistCountsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.WishlistCount, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() as total_data,
+			p.id AS product_id,
+			p.name,
+			COUNT(w.user_id) AS wishlist_count
+		FROM products p
+		LEFT JOIN wishlist_items w ON w.product_id = p.id
+		WHERE
+			p.shop_id = ?
+			AND p.deleted_at IS NULL
+		GROUP BY p.id, p.name
+		ORDER BY wishlist_count DESC, p.name ASC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.ShopId,
+		req.Paginate,
+		req.Paginate*(req.Page-1),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetWishlistCounts - Failed to get wishlist counts")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.WishlistCount)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// DetectChanges moves the baseline of up to limit wishlist items whose product
+// price or availability changed, and passes the changes to notify within the
+// same transaction. The baseline is only committed when notify succeeds, so a
+// failed notification is retried on the next run. Rows locked by another
+// instance are skipped.
+func (r *wishlistRepository) DetectChanges(ctx context.Context, limit int, notify func([]entity.Change) error) (n int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DetectChanges - Failed to begin transaction")
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			if errRollback := tx.Rollback(); errRollback != nil {
+				log.Error().Err(errRollback).Msg("repository::DetectChanges - Failed to rollback transaction")
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			log.Error().Err(err).Msg("repository::DetectChanges - Failed to commit transaction")
+		}
+	}()
+
+	var changes = make([]entity.Change, 0)
+
+	query := `
+		WITH changed AS (
+			SELECT
+				w.user_id,
+				w.product_id,
+				w.last_price,
+				w.last_in_stock,
+				` + productRepo.EffectivePriceSQL + ` AS price,
+				p.currency,
+				p.stock > 0 AS in_stock
+			FROM wishlist_items w
+			JOIN products p ON p.id = w.product_id
+			WHERE
+				p.deleted_at IS NULL
+				AND (` + productRepo.EffectivePriceSQL + ` <> w.last_price OR (p.stock > 0) <> w.last_in_stock)
+			LIMIT ?
+			FOR UPDATE OF w SKIP LOCKED
+		)
+		UPDATE wishlist_items w
+		SET
+			last_price = c.price,
+			last_in_stock = c.in_stock
+		FROM changed c
+		WHERE w.user_id = c.user_id AND w.product_id = c.product_id
+		RETURNING
+			w.user_id,
+			w.product_id,
+			c.currency,
+			c.last_price AS old_price,
+			c.price AS new_price,
+			c.last_in_stock AS was_in_stock,
+			c.in_stock
+	`
+
+	if err = tx.SelectContext(ctx, &changes, r.db.Rebind(query), limit); err != nil {
+		log.Error().Err(err).Msg("repository::DetectChanges - Failed to update wishlist baseline")
+		return 0, err
+	}
+
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	if err = notify(changes); err != nil {
+		return 0, err
+	}
+
+	return len(changes), nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/wishlist/entity"
+	"codebase-app/internal/module/wishlist/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/event"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.WishlistService = &wishlistService{}
+
+// changeBatchSize is the number of wishlist items notified per transaction.
+const changeBatchSize = 500
+
+type wishlistService struct {
+	repo      ports.WishlistRepository
+	catalog   ports.ProductCatalog
+	publisher event.Publisher
+}
+
+func NewWishlistService(repo ports.WishlistRepository, catalog ports.ProductCatalog, publisher event.Publisher) *wishlistService {
+	return &wishlistService{
+		repo:      repo,
+		catalog:   catalog,
+		publisher: publisher,
+	}
+}
+
+func (s *wishlistService) AddToWishlist(ctx context.Context, req *entity.WishlistRequest) (*entity.WishlistResponse, error) {
+	if err := s.repo.AddToWishlist(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.WishlistResponse{ProductId: req.ProductId, Wishlisted: true}, nil
+}
+
+func (s *wishlistService) RemoveFromWishlist(ctx context.Context, req *entity.WishlistRequest) (*entity.WishlistResponse, error) {
+	if err := s.repo.RemoveFromWishlist(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.WishlistResponse{ProductId: req.ProductId, Wishlisted: false}, nil
+}
+
+func (s *wishlistService) GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (*entity.GetWishlistResponse, error) {
+	resp, err := s.repo.GetWishlist(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Items) == 0 {
+		return resp, nil
+	}
+
+	ids := make([]string, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		ids = append(ids, item.ProductId)
+	}
+
+	products, err := s.catalog.GetProducts(ctx, &productEnt.GetProductsRequest{
+		ProductIds: ids,
+		Page:       1,
+		Limit:      len(ids),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]productEnt.Product, len(products.Items))
+	for _, p := range products.Items {
+		byId[p.Id] = p
+	}
+
+	for i, item := range resp.Items {
+		if p, ok := byId[item.ProductId]; ok {
+			resp.Items[i].Product = &p
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *wishlistService) GetWishlistCounts(ctx context.Context, req *entity.GetWishlistCountsRequest) (*entity.GetWishlistCountsResponse, error) {
+	isOwner, err := s.catalog.IsShopOwner(ctx, req.UserId, req.ShopId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isOwner {
+		log.Warn().Any("payload", req).Msg("service::GetWishlistCounts - User is not shop owner")
+		return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("User is not shop owner"))
+	}
+
+	return s.repo.GetWishlistCounts(ctx, req)
+}
+
+// NotifyChanges publishes a price drop or back in stock event for every
+// wishlisted product that changed since the user was last notified, and
+// returns the number of wishlist items processed.
+func (s *wishlistService) NotifyChanges(ctx context.Context) (int, error) {
+	var total int
+
+	for {
+		n, err := s.repo.DetectChanges(ctx, changeBatchSize, func(changes []entity.Change) error {
+			return s.publisher.Publish(ctx, changeEvents(changes)...)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += n
+		if n < changeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// changeEvents maps the changes to events. A price increase or a product going
+// out of stock only moves the baseline and is not notified.
+func changeEvents(changes []entity.Change) []event.Event {
+	events := make([]event.Event, 0, len(changes))
+
+	for _, c := range changes {
+		if c.PriceDropped() {
+			events = append(events, event.New(entity.EventPriceDropped, "product", c.ProductId, map[string]any{
+				"user_id":    c.UserId,
+				"product_id": c.ProductId,
+				"currency":   c.Currency,
+				"old_price":  c.OldPrice,
+				"new_price":  c.NewPrice,
+			}))
+		}
+
+		if c.BackInStock() {
+			events = append(events, event.New(entity.EventBackInStock, "product", c.ProductId, map[string]any{
+				"user_id":    c.UserId,
+				"product_id": c.ProductId,
+				"currency":   c.Currency,
+				"price":      c.NewPrice,
+			}))
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/wishlist/entity"
+	"codebase-app/internal/module/wishlist/ports"
+	mockPort "codebase-app/mock/module/wishlist/ports"
+	mockEvent "codebase-app/mock/pkg/event"
+	"codebase-app/pkg/event"
+	"codebase-app/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceList struct {
+	suite.Suite
+	mockWishlistRepo *mockPort.MockWishlistRepo
+	mockCatalog      *mockPort.MockProductCatalog
+	mockPublisher    *mockEvent.MockPublisher
+	service          ports.WishlistService
+}
+
+func (suite *ServiceList) SetupTest() {
+	suite.mockWishlistRepo = mockPort.NewMockWishlistRepo()
+	suite.mockCatalog = mockPort.NewMockProductCatalog()
+	suite.mockPublisher = mockEvent.NewMockPublisher()
+	suite.service = NewWishlistService(suite.mockWishlistRepo, suite.mockCatalog, suite.mockPublisher)
+}
+
+// Testing GetWishlist
+
+func (suite *ServiceList) TestGetWishlist_JoinsVisibleProducts() {
+	ctx := context.Background()
+	req := &entity.GetWishlistRequest{UserId: "u1"}
+
+	suite.mockWishlistRepo.On("GetWishlist", ctx, req).Return(&entity.GetWishlistResponse{Items: []entity.WishlistItem{
+		{ProductId: "p1"},
+		{ProductId: "p2"},
+	}}, nil)
+	suite.mockCatalog.On("GetProducts", ctx, mock.MatchedBy(func(req *productEnt.GetProductsRequest) bool {
+		return assert.ObjectsAreEqual([]string{"p1", "p2"}, req.ProductIds) && req.Limit == 2
+	})).Return(productEnt.GetProductsResponse{Items: []productEnt.Product{
+		{Id: "p2", Price: types.NewMoney(1000)},
+	}}, nil)
+	resp, err := suite.service.GetWishlist(ctx, req)
+
+	suite.Nil(err)
+	suite.Nil(resp.Items[0].Product)
+	suite.Equal(types.NewMoney(1000), resp.Items[1].Product.Price)
+	suite.mockCatalog.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceList) TestGetWishlist_RepoError() {
+	ctx := context.Background()
+	req := &entity.GetWishlistRequest{UserId: "u1"}
+
+	suite.mockWishlistRepo.On("GetWishlist", ctx, req).Return(nil, errors.New("error"))
+	_, err := suite.service.GetWishlist(ctx, req)
+
+	suite.Equal(errors.New("error"), err)
+	suite.mockCatalog.AssertNotCalled(suite.T(), "GetProducts", mock.Anything, mock.Anything)
+}
+
+// Testing NotifyChanges
+
+func (suite *ServiceList) TestNotifyChanges_PublishesDropsAndRestocks() {
+	ctx := context.Background()
+	changes := []entity.Change{
+		{UserId: "u1", ProductId: "p1", OldPrice: types.NewMoney(1000), NewPrice: types.NewMoney(900), WasInStock: true, InStock: true},
+		{UserId: "u1", ProductId: "p2", OldPrice: types.NewMoney(1000), NewPrice: types.NewMoney(1000), WasInStock: false, InStock: true},
+		{UserId: "u2", ProductId: "p3", OldPrice: types.NewMoney(1000), NewPrice: types.NewMoney(1200), WasInStock: true, InStock: false},
+	}
+
+	var published []event.Event
+	suite.mockWishlistRepo.On("DetectChanges", ctx, changeBatchSize, mock.Anything).Return(changes, nil)
+	suite.mockPublisher.On("Publish", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).([]event.Event)
+	}).Return(nil)
+	n, err := suite.service.NotifyChanges(ctx)
+
+	suite.Nil(err)
+	suite.Equal(3, n)
+	suite.Len(published, 2)
+	suite.Equal(entity.EventPriceDropped, published[0].Type)
+	suite.Equal("p1", published[0].AggregateId)
+	suite.Equal(entity.EventBackInStock, published[1].Type)
+	suite.Equal("p2", published[1].AggregateId)
+}
+
+func (suite *ServiceList) TestNotifyChanges_PublishError() {
+	ctx := context.Background()
+	changes := []entity.Change{
+		{UserId: "u1", ProductId: "p1", OldPrice: types.NewMoney(1000), NewPrice: types.NewMoney(900), WasInStock: true, InStock: true},
+	}
+
+	suite.mockWishlistRepo.On("DetectChanges", ctx, changeBatchSize, mock.Anything).Return(changes, nil)
+	suite.mockPublisher.On("Publish", ctx, mock.Anything).Return(errors.New("error"))
+	_, err := suite.service.NotifyChanges(ctx)
+
+	suite.Equal(errors.New("error"), err)
+}
+
+func TestService(t *testing.T) {
+	suite.Run(t, new(ServiceList))
+}
@@ -1,8 +1,18 @@
 package route
 
 import (
+	"codebase-app/internal/infrastructure/config"
+	integOauth "codebase-app/internal/integration/oauth2google"
+	handlerApiKey "codebase-app/internal/module/apikey/handler/rest"
+	handlerAudit "codebase-app/internal/module/audit/handler/rest"
+	handlerNotification "codebase-app/internal/module/notification/handler/rest"
 	handlerProduct "codebase-app/internal/module/product/handler/rest"
+	handlerQuestion "codebase-app/internal/module/question/handler/rest"
+	handlerReview "codebase-app/internal/module/review/handler/rest"
 	handlerShop "codebase-app/internal/module/shop/handler/rest"
+	handlerUser "codebase-app/internal/module/user/handler/rest"
+	handlerWebhook "codebase-app/internal/module/webhook/handler/rest"
+	handlerWishlist "codebase-app/internal/module/wishlist/handler/rest"
 	"codebase-app/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,8 +24,18 @@ func SetupRoutes(app *fiber.App) {
 		api = app.Group("/products")
 	)
 
+	api.Static("/storage", config.Envs.App.LocalStoragePublicPath)
+
+	handlerUser.NewUserHandler(integOauth.NewOauth2googleIntegration()).Register(api)
+	handlerApiKey.NewApiKeyHandler().Register(api)
+	handlerAudit.NewAuditHandler().Register(api)
 	handlerShop.NewShopHandler().Register(api)
+	handlerWebhook.NewWebhookHandler().Register(api)
 	handlerProduct.NewProductHandler().Register(api)
+	handlerReview.NewReviewHandler().Register(api)
+	handlerQuestion.NewQuestionHandler().Register(api)
+	handlerWishlist.NewWishlistHandler().Register(api)
+	handlerNotification.NewNotificationHandler().Register(api)
 
 	// fallback route
 	app.Use(func(c *fiber.Ctx) error {
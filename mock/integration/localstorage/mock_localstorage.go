@@ -0,0 +1,51 @@
+package mock_localstorage
+
+import (
+	integStorage "codebase-app/internal/integration/localstorage"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLocalStorage struct {
+	mock.Mock
+}
+
+func NewMockLocalStorage() *MockLocalStorage {
+	return &MockLocalStorage{}
+}
+
+var _ integStorage.LocalStorageContract = &MockLocalStorage{}
+
+func (m *MockLocalStorage) Save(base64String, path string) (string, error) {
+	args := m.Called(base64String, path)
+	var (
+		resp string
+		err  error
+	)
+
+	if n, ok := args.Get(0).(string); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockLocalStorage) Remove(fullpath string) error {
+	args := m.Called(fullpath)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
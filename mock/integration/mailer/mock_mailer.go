@@ -0,0 +1,32 @@
+package mock_mailer
+
+import (
+	integMailer "codebase-app/internal/integration/mailer"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func NewMockMailer() *MockMailer {
+	return &MockMailer{}
+}
+
+var _ integMailer.MailerContract = &MockMailer{}
+
+func (m *MockMailer) Send(ctx context.Context, to, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
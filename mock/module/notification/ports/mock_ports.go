@@ -0,0 +1,119 @@
+package mock_ports
+
+import (
+	"codebase-app/internal/module/notification/entity"
+	"codebase-app/internal/module/notification/ports"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func NewMockNotificationRepo() *MockNotificationRepo {
+	return &MockNotificationRepo{}
+}
+
+var _ ports.NotificationRepository = &MockNotificationRepo{}
+
+func (m *MockNotificationRepo) GetNotifications(ctx context.Context, req *entity.GetNotificationsRequest) (*entity.GetNotificationsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.GetNotificationsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.GetNotificationsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockNotificationRepo) GetNotification(ctx context.Context, id string) (*entity.Notification, error) {
+	args := m.Called(ctx, id)
+	var (
+		resp *entity.Notification
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Notification); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, userId string) (int, error) {
+	args := m.Called(ctx, userId)
+	var (
+		resp int
+		err  error
+	)
+
+	if n, ok := args.Get(0).(int); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, req *entity.NotificationRequest) (*entity.Notification, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Notification
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Notification); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, req *entity.MarkAllReadRequest) (*entity.MarkAllReadResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.MarkAllReadResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.MarkAllReadResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
@@ -0,0 +1,88 @@
+package mock_ports
+
+import (
+	"codebase-app/internal/module/outbox/entity"
+	"codebase-app/internal/module/outbox/ports"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+func NewMockOutboxRepo() *MockOutboxRepo {
+	return &MockOutboxRepo{}
+}
+
+var _ ports.OutboxRepository = &MockOutboxRepo{}
+
+func (m *MockOutboxRepo) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	args := m.Called(ctx, limit, lease)
+	var (
+		resp []entity.OutboxEvent
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.OutboxEvent); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockOutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockOutboxRepo) MarkFailed(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	args := m.Called(ctx, id, reason, retryAt)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	var (
+		resp int
+		err  error
+	)
+
+	if n, ok := args.Get(0).(int); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
@@ -3,7 +3,9 @@ package mock_ports
 import (
 	"codebase-app/internal/module/product/entity"
 	"codebase-app/internal/module/product/ports"
+	"codebase-app/pkg/types"
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -126,6 +128,26 @@ func (m *MockProductRepo) IsShopOwner(ctx context.Context, userId, shopId string
 	return resp, err
 }
 
+func (m *MockProductRepo) IsProductsOwner(ctx context.Context, userId, shopId string, productIds []string) (bool, error) {
+	args := m.Called(ctx, userId, shopId, productIds)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
 func (m *MockProductRepo) IsProductOwner(ctx context.Context, userId, productId string) (bool, error) {
 	args := m.Called(ctx, userId, productId)
 	var (
@@ -145,3 +167,651 @@ func (m *MockProductRepo) IsProductOwner(ctx context.Context, userId, productId
 
 	return resp, err
 }
+
+func (m *MockProductRepo) ResolveProductSlug(ctx context.Context, slug string) (entity.SlugLookup, error) {
+	args := m.Called(ctx, slug)
+	var (
+		resp entity.SlugLookup
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.SlugLookup); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) ReserveStock(ctx context.Context, req *entity.ReserveStockRequest) (entity.ReservationResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.ReservationResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ReservationResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) CommitReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.ReservationResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ReservationResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) ReleaseReservation(ctx context.Context, req *entity.ReservationRequest) (entity.ReservationResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.ReservationResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ReservationResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetFeed(ctx context.Context, req *entity.GetFeedRequest) (entity.GetFeedResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.GetFeedResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.GetFeedResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) CreatePriceSchedule(ctx context.Context, req *entity.CreatePriceScheduleRequest) (entity.PriceSchedule, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.PriceSchedule
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.PriceSchedule); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetPriceSchedules(ctx context.Context, productId string) ([]entity.PriceSchedule, error) {
+	args := m.Called(ctx, productId)
+	var (
+		resp []entity.PriceSchedule
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.PriceSchedule); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) CancelPriceSchedule(ctx context.Context, req *entity.PriceScheduleRequest) error {
+	args := m.Called(ctx, req)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockProductRepo) ApplyPriceSchedules(ctx context.Context, now time.Time) (entity.ApplyPriceSchedulesResult, error) {
+	args := m.Called(ctx, now)
+	var (
+		resp entity.ApplyPriceSchedulesResult
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ApplyPriceSchedulesResult); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (entity.GetPriceHistoryResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.GetPriceHistoryResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.GetPriceHistoryResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	args := m.Called(ctx)
+	var (
+		resp []entity.ExchangeRate
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.ExchangeRate); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetRatesTo(ctx context.Context, quote string) (map[string]types.Rate, error) {
+	args := m.Called(ctx, quote)
+	var (
+		resp map[string]types.Rate
+		err  error
+	)
+
+	if n, ok := args.Get(0).(map[string]types.Rate); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) UpsertExchangeRate(ctx context.Context, req *entity.UpsertExchangeRateRequest) (entity.ExchangeRate, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.ExchangeRate
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ExchangeRate); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) ExportProducts(ctx context.Context, req *entity.GetProductsRequest, fn func(p *entity.ExportProduct) error) error {
+	args := m.Called(ctx, req, fn)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).([]entity.ExportProduct); ok {
+		for i := range n {
+			if err = fn(&n[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockProductRepo) BulkUpdateProducts(ctx context.Context, req *entity.BulkProductsRequest) (entity.BulkProductsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.BulkProductsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.BulkProductsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetProductTrash(ctx context.Context, req *entity.GetProductTrashRequest, since time.Time) (entity.GetProductTrashResponse, error) {
+	args := m.Called(ctx, req, since)
+	var (
+		resp entity.GetProductTrashResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.GetProductTrashResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) RestoreProduct(ctx context.Context, req *entity.RestoreProductRequest, since time.Time) (entity.UpsertProductResponse, error) {
+	args := m.Called(ctx, req, since)
+	var (
+		resp entity.UpsertProductResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.UpsertProductResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) PurgeProducts(ctx context.Context, before time.Time, limit int) (entity.PurgeResult, error) {
+	args := m.Called(ctx, before, limit)
+	var (
+		resp entity.PurgeResult
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.PurgeResult); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) SetLowStockThreshold(ctx context.Context, req *entity.SetLowStockThresholdRequest) (entity.SetLowStockThresholdResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.SetLowStockThresholdResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.SetLowStockThresholdResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetLowStockProducts(ctx context.Context, req *entity.GetLowStockRequest) (entity.GetLowStockResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp entity.GetLowStockResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.GetLowStockResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) NotifyStockAlerts(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	var (
+		resp int
+		err  error
+	)
+
+	if n, ok := args.Get(0).(int); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) GetStockAlertEmail(ctx context.Context, alertId int64) (string, error) {
+	args := m.Called(ctx, alertId)
+	var (
+		resp string
+		err  error
+	)
+
+	if n, ok := args.Get(0).(string); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductRepo) MarkStockAlertEmailed(ctx context.Context, alertId int64) error {
+	args := m.Called(ctx, alertId)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+type MockImportRepo struct {
+	mock.Mock
+}
+
+func NewMockImportRepo() *MockImportRepo {
+	return &MockImportRepo{}
+}
+
+var _ ports.ImportRepository = &MockImportRepo{}
+
+func (m *MockImportRepo) CreateImportJob(ctx context.Context, req *entity.CreateImportJobRequest, status string) (entity.ImportJob, error) {
+	args := m.Called(ctx, req, status)
+	var (
+		resp entity.ImportJob
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ImportJob); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockImportRepo) GetImportJob(ctx context.Context, id string) (entity.ImportJob, error) {
+	args := m.Called(ctx, id)
+	var (
+		resp entity.ImportJob
+		err  error
+	)
+
+	if n, ok := args.Get(0).(entity.ImportJob); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockImportRepo) GetImportErrors(ctx context.Context, id string) ([]entity.ImportRowError, error) {
+	args := m.Called(ctx, id)
+	var (
+		resp []entity.ImportRowError
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.ImportRowError); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockImportRepo) ClaimImportJob(ctx context.Context) (*entity.ImportJob, error) {
+	args := m.Called(ctx)
+	var (
+		resp *entity.ImportJob
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.ImportJob); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockImportRepo) HeartbeatImportJob(ctx context.Context, job *entity.ImportJob) error {
+	args := m.Called(ctx, job)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockImportRepo) UpdateImportProgress(ctx context.Context, job *entity.ImportJob) error {
+	args := m.Called(ctx, job)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockImportRepo) FinishImportJob(ctx context.Context, job *entity.ImportJob, res *entity.ImportResult) error {
+	args := m.Called(ctx, job, res)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockImportRepo) MissingCategoryIds(ctx context.Context, ids []string) ([]string, error) {
+	args := m.Called(ctx, ids)
+	var (
+		resp []string
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]string); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockImportRepo) ImportProducts(ctx context.Context, job *entity.ImportJob, rows []entity.ImportRow, progress func(imported int)) (int, int, error) {
+	args := m.Called(ctx, job, rows, progress)
+	var (
+		resp  int
+		resp2 int
+		err   error
+	)
+
+	if n, ok := args.Get(0).(int); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(int); ok {
+
+		resp2 = n
+	}
+
+	if n, ok := args.Get(2).(error); ok {
+
+		err = n
+	}
+
+	return resp, resp2, err
+}
+
+func (m *MockImportRepo) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	args := m.Called(ctx, userId, shopId)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
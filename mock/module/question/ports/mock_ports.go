@@ -0,0 +1,169 @@
+package mock_ports
+
+import (
+	"codebase-app/internal/module/question/entity"
+	"codebase-app/internal/module/question/ports"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockQuestionRepo struct {
+	mock.Mock
+}
+
+func NewMockQuestionRepo() *MockQuestionRepo {
+	return &MockQuestionRepo{}
+}
+
+var _ ports.QuestionRepository = &MockQuestionRepo{}
+
+func (m *MockQuestionRepo) CreateQuestion(ctx context.Context, req *entity.CreateQuestionRequest) (*entity.Question, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Question
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Question); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockQuestionRepo) GetQuestion(ctx context.Context, id string) (*entity.Question, error) {
+	args := m.Called(ctx, id)
+	var (
+		resp *entity.Question
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Question); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockQuestionRepo) GetQuestions(ctx context.Context, req *entity.GetQuestionsRequest) (*entity.QuestionsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.QuestionsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.QuestionsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockQuestionRepo) CreateAnswer(ctx context.Context, req *entity.CreateAnswerRequest) (*entity.Answer, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Answer
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Answer); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockQuestionRepo) MarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.MarkHelpfulResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.MarkHelpfulResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockQuestionRepo) UnmarkHelpful(ctx context.Context, req *entity.MarkHelpfulRequest) (*entity.MarkHelpfulResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.MarkHelpfulResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.MarkHelpfulResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+type MockProductOwnerChecker struct {
+	mock.Mock
+}
+
+func NewMockProductOwnerChecker() *MockProductOwnerChecker {
+	return &MockProductOwnerChecker{}
+}
+
+var _ ports.ProductOwnerChecker = &MockProductOwnerChecker{}
+
+func (m *MockProductOwnerChecker) IsProductOwner(ctx context.Context, userId, productId string) (bool, error) {
+	args := m.Called(ctx, userId, productId)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
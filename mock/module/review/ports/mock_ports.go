@@ -0,0 +1,173 @@
+package mock_ports
+
+import (
+	"codebase-app/internal/module/review/entity"
+	"codebase-app/internal/module/review/ports"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewRepo struct {
+	mock.Mock
+}
+
+func NewMockReviewRepo() *MockReviewRepo {
+	return &MockReviewRepo{}
+}
+
+var _ ports.ReviewRepository = &MockReviewRepo{}
+
+func (m *MockReviewRepo) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.Review, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Review
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Review); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.Review, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Review
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Review); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	args := m.Called(ctx, req)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockReviewRepo) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.Review, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Review
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Review); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) ReportReview(ctx context.Context, req *entity.ReportReviewRequest) (*entity.ReportReviewResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.ReportReviewResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.ReportReviewResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) ModerateReview(ctx context.Context, req *entity.ModerateReviewRequest) (*entity.Review, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Review
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Review); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.ReviewsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.ReviewsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.ReviewsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockReviewRepo) GetAdminReviews(ctx context.Context, req *entity.AdminReviewsRequest) (*entity.ReviewsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.ReviewsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.ReviewsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
@@ -0,0 +1,214 @@
+package mock_ports
+
+import (
+	"codebase-app/internal/module/webhook/entity"
+	"codebase-app/internal/module/webhook/ports"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func NewMockWebhookRepo() *MockWebhookRepo {
+	return &MockWebhookRepo{}
+}
+
+var _ ports.WebhookRepository = &MockWebhookRepo{}
+
+func (m *MockWebhookRepo) CreateWebhook(ctx context.Context, req *entity.CreateWebhookRequest) (*entity.Webhook, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Webhook
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Webhook); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) GetWebhooks(ctx context.Context, req *entity.GetWebhooksRequest) ([]entity.Webhook, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp []entity.Webhook
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.Webhook); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) UpdateWebhook(ctx context.Context, req *entity.UpdateWebhookRequest) (*entity.Webhook, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Webhook
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Webhook); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) DeleteWebhook(ctx context.Context, req *entity.WebhookRequest) error {
+	args := m.Called(ctx, req)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockWebhookRepo) RotateSecret(ctx context.Context, req *entity.WebhookRequest, secret string) (*entity.Webhook, error) {
+	args := m.Called(ctx, req, secret)
+	var (
+		resp *entity.Webhook
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Webhook); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) GetDeliveries(ctx context.Context, req *entity.GetDeliveriesRequest) (*entity.GetDeliveriesResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.GetDeliveriesResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.GetDeliveriesResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) Redeliver(ctx context.Context, req *entity.RedeliverRequest) (*entity.Delivery, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.Delivery
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.Delivery); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	args := m.Called(ctx, userId, shopId)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.Job, error) {
+	args := m.Called(ctx, limit, lease)
+	var (
+		resp []entity.Job
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.Job); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWebhookRepo) RecordAttempt(ctx context.Context, job *entity.Job, res *entity.Result, retryAt *time.Time) (bool, error) {
+	args := m.Called(ctx, job, res, retryAt)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
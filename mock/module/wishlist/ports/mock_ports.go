@@ -0,0 +1,160 @@
+package mock_ports
+
+import (
+	productEnt "codebase-app/internal/module/product/entity"
+	"codebase-app/internal/module/wishlist/entity"
+	"codebase-app/internal/module/wishlist/ports"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWishlistRepo struct {
+	mock.Mock
+}
+
+func NewMockWishlistRepo() *MockWishlistRepo {
+	return &MockWishlistRepo{}
+}
+
+var _ ports.WishlistRepository = &MockWishlistRepo{}
+
+func (m *MockWishlistRepo) AddToWishlist(ctx context.Context, req *entity.WishlistRequest) error {
+	args := m.Called(ctx, req)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockWishlistRepo) RemoveFromWishlist(ctx context.Context, req *entity.WishlistRequest) error {
+	args := m.Called(ctx, req)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
+
+func (m *MockWishlistRepo) GetWishlist(ctx context.Context, req *entity.GetWishlistRequest) (*entity.GetWishlistResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.GetWishlistResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.GetWishlistResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWishlistRepo) GetWishlistCounts(ctx context.Context, req *entity.GetWishlistCountsRequest) (*entity.GetWishlistCountsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp *entity.GetWishlistCountsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(*entity.GetWishlistCountsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockWishlistRepo) DetectChanges(ctx context.Context, limit int, notify func([]entity.Change) error) (int, error) {
+	args := m.Called(ctx, limit, notify)
+	var (
+		resp int
+		err  error
+	)
+
+	if n, ok := args.Get(0).([]entity.Change); ok && len(n) > 0 {
+		resp = len(n)
+		if err = notify(n); err != nil {
+			return 0, err
+		}
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+type MockProductCatalog struct {
+	mock.Mock
+}
+
+func NewMockProductCatalog() *MockProductCatalog {
+	return &MockProductCatalog{}
+}
+
+var _ ports.ProductCatalog = &MockProductCatalog{}
+
+func (m *MockProductCatalog) GetProducts(ctx context.Context, req *productEnt.GetProductsRequest) (productEnt.GetProductsResponse, error) {
+	args := m.Called(ctx, req)
+	var (
+		resp productEnt.GetProductsResponse
+		err  error
+	)
+
+	if n, ok := args.Get(0).(productEnt.GetProductsResponse); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
+
+func (m *MockProductCatalog) IsShopOwner(ctx context.Context, userId, shopId string) (bool, error) {
+	args := m.Called(ctx, userId, shopId)
+	var (
+		resp bool
+		err  error
+	)
+
+	if n, ok := args.Get(0).(bool); ok {
+
+		resp = n
+	}
+
+	if n, ok := args.Get(1).(error); ok {
+
+		err = n
+	}
+
+	return resp, err
+}
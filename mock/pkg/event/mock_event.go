@@ -0,0 +1,32 @@
+package mock_event
+
+import (
+	"codebase-app/pkg/event"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func NewMockPublisher() *MockPublisher {
+	return &MockPublisher{}
+}
+
+var _ event.Publisher = &MockPublisher{}
+
+func (m *MockPublisher) Publish(ctx context.Context, events ...event.Event) error {
+	args := m.Called(ctx, events)
+	var (
+		err error
+	)
+
+	if n, ok := args.Get(0).(error); ok {
+
+		err = n
+	}
+
+	return err
+}
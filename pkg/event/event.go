@@ -0,0 +1,50 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Event is a domain event emitted after a change to an aggregate (a shop, a
+// product, ...), consumed by downstream services.
+type Event struct {
+	Id            string    `json:"id"`
+	Type          string    `json:"type"`
+	AggregateType string    `json:"aggregate_type"`
+	AggregateId   string    `json:"aggregate_id"`
+	Payload       any       `json:"payload"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+func New(eventType, aggregateType, aggregateId string, payload any) Event {
+	return Event{
+		Id:            ulid.Make().String(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Payload:       payload,
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher returns a publisher that only writes events to the log.
+func NewLogPublisher() Publisher {
+	return &logPublisher{}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, e := range events {
+		log.Info().Any("event", e).Msg("event::Publish")
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The publishers below are the sinks the outbox relay delivers events to.
+// They return an error as soon as one event could not be delivered, the
+// events before it are delivered.
+
+type writerPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterPublisher returns a publisher writing events to w as JSON lines,
+// ex: os.Stdout.
+func NewWriterPublisher(w io.Writer) Publisher {
+	return &writerPublisher{enc: json.NewEncoder(w)}
+}
+
+func (p *writerPublisher) Publish(ctx context.Context, events ...Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range events {
+		if err := p.enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type httpPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher returns a publisher posting each event as JSON to url. Any
+// status other than 2xx is an error. The event id is sent in the
+// X-Event-Id header so the receiver can drop the events delivered twice.
+func NewHTTPPublisher(url string, timeout time.Duration) Publisher {
+	return &httpPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, e := range events {
+		body, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Event-Id", e.Id)
+		req.Header.Set("X-Event-Type", e.Type)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("event: %s responded %d to event %s", p.url, resp.StatusCode, e.Id)
+		}
+	}
+
+	return nil
+}
+
+// MemoryPublisher keeps the published events in memory, it is the sink of
+// tests. Publish fails with Err when set.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []Event
+	Err    error
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, events ...Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Err != nil {
+		return p.Err
+	}
+
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Event(nil), p.events...)
+}
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher returns a publisher handing the events to each publisher
+// in turn, it stops at the first one failing. The publishers before it get
+// the events again when they are retried, they should be idempotent.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, events...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
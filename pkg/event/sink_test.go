@@ -0,0 +1,132 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterPublisher(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		p   = NewWriterPublisher(&buf)
+	)
+
+	err := p.Publish(context.Background(), New("product.created", "product", "p1", nil), New("product.deleted", "product", "p1", nil))
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var e Event
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &e))
+	assert.Equal(t, "product.deleted", e.Type)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	var received []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		assert.Equal(t, e.Id, r.Header.Get("X-Event-Id"))
+		assert.Equal(t, e.Type, r.Header.Get("X-Event-Type"))
+
+		if e.AggregateId == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, e.Type)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPublisher(srv.URL, time.Second)
+
+	err := p.Publish(context.Background(), New("product.created", "product", "p1", nil), New("stock.changed", "product", "p1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"product.created", "stock.changed"}, received)
+
+	err = p.Publish(context.Background(), New("product.created", "product", "fail", nil))
+	assert.ErrorContains(t, err, "503")
+}
+
+// serveNATS answers a single client like a NATS server, sending the subjects
+// and payloads it receives to msgs.
+func serveNATS(t *testing.T, l net.Listener, msgs chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var (
+		r = bufio.NewReader(conn)
+		w = bufio.NewWriter(conn)
+	)
+
+	fmt.Fprint(w, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+	w.Flush()
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch fields := strings.Fields(line); fields[0] {
+		case "PUB":
+			var size int
+			fmt.Sscan(fields[2], &size)
+
+			data := make([]byte, size+2)
+			if _, err = io.ReadFull(r, data); err != nil {
+				return
+			}
+			msgs <- fields[1] + " " + string(data[:size])
+		case "PING":
+			fmt.Fprint(w, "PONG\r\n")
+			w.Flush()
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	msgs := make(chan string, 10)
+	go serveNATS(t, l, msgs)
+
+	p, err := NewNATSPublisher("nats://"+l.Addr().String(), "shopeefun", time.Second)
+	assert.NoError(t, err)
+
+	e := New("stock.changed", "product", "p1", map[string]int{"to": 3})
+	assert.NoError(t, p.Publish(context.Background(), e))
+
+	msg := <-msgs
+	subject, data, _ := strings.Cut(msg, " ")
+	assert.Equal(t, "shopeefun.stock.changed", subject)
+
+	var got Event
+	assert.NoError(t, json.Unmarshal([]byte(data), &got))
+	assert.Equal(t, e.Id, got.Id)
+}
+
+func TestNATSPublisherInvalidURL(t *testing.T) {
+	_, err := NewNATSPublisher("http://localhost:4222", "shopeefun", time.Second)
+	assert.Error(t, err)
+}
@@ -10,8 +10,9 @@ import (
 
 func GenerateTokenString(payload CostumClaimsPayload) (string, error) {
 	claims := CustomClaims{
-		UserId: payload.UserId,
-		Role:   payload.Role,
+		UserId:       payload.UserId,
+		Role:         payload.Role,
+		TokenVersion: payload.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   "user",
 			Issuer:    "codebase-app",
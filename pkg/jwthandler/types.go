@@ -7,8 +7,9 @@ import (
 )
 
 type CustomClaims struct {
-	UserId string `json:"user_id"`
-	Role   string `json:"role"`
+	UserId       string `json:"user_id"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"ver"`
 	jwt.RegisteredClaims
 }
 
@@ -21,6 +22,7 @@ type CostumClaimsWs struct {
 type CostumClaimsPayload struct {
 	UserId          string    `json:"user_id"`
 	Role            string    `json:"role"`
+	TokenVersion    int       `json:"token_version"`
 	TokenExpiration time.Time `json:"token_expiration"`
 }
 
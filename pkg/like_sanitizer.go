@@ -0,0 +1,12 @@
+package pkg
+
+import "strings"
+
+// EscapeLike escapes the wildcards of a LIKE pattern so s matches literally,
+// the query must use ESCAPE '\'.
+func EscapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	s = strings.ReplaceAll(s, "_", `\_`)
+	return s
+}
@@ -0,0 +1,21 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"toko":      "toko",
+		"100%":      `100\%`,
+		"toko_kopi": `toko\_kopi`,
+		`a\b`:       `a\\b`,
+		`50%_\ off`: `50\%\_\\ off`,
+	}
+
+	for in, want := range tests {
+		assert.Equal(t, want, EscapeLike(in), in)
+	}
+}
@@ -0,0 +1,122 @@
+// Package netguard keeps the requests sent to urls given by users, like
+// webhooks, away from the internal network of the service.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// forbidden are the ranges not covered by the netip predicates: shared
+// address space, used by some clouds for their metadata service, the
+// "this network" and benchmarking ranges, and the reserved ones.
+var forbidden = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// Guard tells which addresses may be reached.
+type Guard struct {
+	// AllowLoopback lets requests reach the host itself, for tests and local
+	// development only.
+	AllowLoopback bool
+}
+
+func New(allowLoopback bool) *Guard {
+	return &Guard{AllowLoopback: allowLoopback}
+}
+
+// Allowed reports whether ip is a public address, or a loopback one when they
+// are allowed. Private, link-local, including the 169.254.169.254 metadata
+// service, multicast and reserved addresses are not.
+func (g *Guard) Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if ip.IsLoopback() {
+		return g.AllowLoopback
+	}
+
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range forbidden {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckURL resolves the host of rawURL and fails with ErrForbiddenAddress
+// when any of its addresses is not allowed. The host can resolve elsewhere
+// later, requests must go through Client as well.
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return err
+	}
+
+	for _, ip := range ips {
+		if !g.Allowed(ip) {
+			return ErrForbiddenAddress
+		}
+	}
+
+	return nil
+}
+
+// Control checks the address a connection is about to be made to, once the
+// host is resolved, so a host resolving to an internal address after
+// CheckURL is still refused. It fits net.Dialer.Control.
+func (g *Guard) Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !g.Allowed(addrPort.Addr()) {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
+
+// Client returns an http client dialing through the guard, without proxy,
+// which does not follow redirects: a redirect is returned as the response.
+func (g *Guard) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: g.Control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
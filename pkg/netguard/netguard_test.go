@@ -0,0 +1,68 @@
+package netguard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	var (
+		guard = New(false)
+		tests = map[string]bool{
+			"93.184.216.34":   true,
+			"2606:4700::1111": true,
+			"127.0.0.1":       false,
+			"::1":             false,
+			"10.1.2.3":        false,
+			"172.16.0.1":      false,
+			"192.168.1.1":     false,
+			"169.254.169.254": false,
+			"100.100.100.200": false,
+			"0.0.0.0":         false,
+			"fd00:ec2::254":   false,
+			"fe80::1":         false,
+			"::ffff:10.0.0.1": false,
+			"224.0.0.1":       false,
+		}
+	)
+
+	for ip, want := range tests {
+		assert.Equal(t, want, guard.Allowed(netip.MustParseAddr(ip)), ip)
+	}
+
+	assert.True(t, New(true).Allowed(netip.MustParseAddr("127.0.0.1")))
+}
+
+func TestCheckURL(t *testing.T) {
+	guard := New(false)
+
+	assert.NoError(t, guard.CheckURL(context.Background(), "https://93.184.216.34/hook"))
+	assert.ErrorIs(t, guard.CheckURL(context.Background(), "http://169.254.169.254/latest/meta-data"), ErrForbiddenAddress)
+	assert.ErrorIs(t, guard.CheckURL(context.Background(), "http://[::1]:8080/hook"), ErrForbiddenAddress)
+}
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	_, err := New(false).Client(time.Second).Get(srv.URL)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	resp, err := New(true).Client(time.Second).Get(srv.URL + "/redirect")
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusFound, resp.StatusCode)
+	}
+}
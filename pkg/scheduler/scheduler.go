@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Job runs the work due at now and returns when it is due next, nil when
+// nothing is scheduled.
+type Job func(ctx context.Context, now time.Time) (next *time.Time, err error)
+
+// Timer runs a job exactly when it is next due. It sleeps at most maxWait so
+// work scheduled by another instance is picked up, and can be woken early when
+// this instance schedules new work.
+type Timer struct {
+	name    string
+	maxWait time.Duration
+	wake    chan struct{}
+}
+
+func NewTimer(name string, maxWait time.Duration) *Timer {
+	return &Timer{
+		name:    name,
+		maxWait: maxWait,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Wake makes a running timer run its job now. It never blocks, and wakes
+// received while the job is running coalesce into a single extra run.
+func (t *Timer) Wake() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs job until ctx is done.
+func (t *Timer) Run(ctx context.Context, job Job) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("timer", t.name).Msg("scheduler::Run - Stopped")
+			return
+		case <-t.wake:
+		case <-timer.C:
+		}
+
+		wait := t.maxWait
+
+		next, err := job(ctx, time.Now())
+		if err != nil {
+			log.Error().Err(err).Str("timer", t.name).Msg("scheduler::Run - Job failed")
+		} else if next != nil {
+			wait = min(wait, max(time.Until(*next), 0))
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerRunsAtNextDue(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		timer       = NewTimer("test", time.Hour)
+		runs        = make(chan time.Time, 10)
+		start       = time.Now()
+	)
+	defer cancel()
+
+	go timer.Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		runs <- now
+		next := start.Add(50 * time.Millisecond)
+		if now.After(next) {
+			return nil, nil
+		}
+		return &next, nil
+	})
+
+	<-runs // first run happens immediately
+
+	select {
+	case now := <-runs:
+		assert.False(t, now.Before(start.Add(50*time.Millisecond)))
+	case <-time.After(time.Second):
+		t.Fatal("job was not run when due")
+	}
+}
+
+func TestTimerWake(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		timer       = NewTimer("test", time.Hour)
+		runs        = make(chan struct{}, 10)
+	)
+	defer cancel()
+
+	go timer.Run(ctx, func(ctx context.Context, now time.Time) (*time.Time, error) {
+		runs <- struct{}{}
+		return nil, nil
+	})
+
+	<-runs
+	timer.Wake()
+
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("job was not run when woken")
+	}
+}
@@ -0,0 +1,69 @@
+package signature
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Client is an http client that signs every outgoing request with a shared key.
+type Client struct {
+	keyId  string
+	secret string
+	http   *http.Client
+}
+
+func NewClient(keyId, secret string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		keyId:  keyId,
+		secret: secret,
+		http:   httpClient,
+	}
+}
+
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if err := SignRequest(req, c.keyId, c.secret); err != nil {
+		return nil, err
+	}
+
+	return c.http.Do(req)
+}
+
+// SignRequest adds the signature headers to req. The body is read and
+// replaced so the request can still be sent afterwards.
+func SignRequest(req *http.Request, keyId, secret string) error {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+
+		body = b
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	var (
+		timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		nonce     = ulid.Make().String()
+	)
+
+	req.Header.Set(HeaderKeyId, keyId)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Sign(secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body))
+
+	return nil
+}
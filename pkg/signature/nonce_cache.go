@@ -0,0 +1,72 @@
+package signature
+
+import (
+	"sync"
+	"time"
+)
+
+// NonceCache remembers nonces for a limited time to reject replayed requests.
+//
+// The cache lives in the memory of the process: with several instances behind
+// a load balancer a nonce is only rejected by the instance that saw it first,
+// so the replay window across instances is bounded by the timestamp skew only.
+type NonceCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	nonces map[string]time.Time
+
+	// queue holds the nonces in insertion order, which is also their expiry
+	// order as they all live for ttl.
+	queue []nonceEntry
+}
+
+type nonceEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+func NewNonceCache(ttl time.Duration) *NonceCache {
+	return &NonceCache{
+		ttl:    ttl,
+		nonces: make(map[string]time.Time),
+	}
+}
+
+// Use records the nonce and reports whether it was unused. A nonce is only
+// accepted once until it expires from the cache.
+func (n *NonceCache) Use(nonce string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	n.evict(now)
+
+	if _, ok := n.nonces[nonce]; ok {
+		return false
+	}
+
+	expiresAt := now.Add(n.ttl)
+	n.nonces[nonce] = expiresAt
+	n.queue = append(n.queue, nonceEntry{nonce: nonce, expiresAt: expiresAt})
+	return true
+}
+
+// evict drops the expired nonces from the front of the queue, it stops at the
+// first one still valid.
+func (n *NonceCache) evict(now time.Time) {
+	i := 0
+	for ; i < len(n.queue) && now.After(n.queue[i].expiresAt); i++ {
+		delete(n.nonces, n.queue[i].nonce)
+	}
+
+	if i == 0 {
+		return
+	}
+
+	// reuse the backing array once the live nonces fit in its first half
+	if remaining := len(n.queue) - i; remaining < cap(n.queue)/2 {
+		n.queue = append(n.queue[:0], n.queue[i:]...)
+	} else {
+		n.queue = n.queue[i:]
+	}
+}
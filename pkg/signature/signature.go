@@ -0,0 +1,59 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Headers used to carry the signature of a service-to-service request.
+const (
+	HeaderKeyId     = "X-Signature-Key-Id"
+	HeaderTimestamp = "X-Signature-Timestamp"
+	HeaderNonce     = "X-Signature-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// BodyHash returns the hex encoded sha256 of a request body.
+func BodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// StringToSign builds the canonical string covered by the signature.
+// path must include the raw query string, if any.
+func StringToSign(method, path, timestamp, nonce string, body []byte) string {
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		BodyHash(body),
+	}, "\n")
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the canonical request.
+func Sign(secret, method, path, timestamp, nonce string, body []byte) string {
+	return mac(secret, []byte(StringToSign(method, path, timestamp, nonce, body)))
+}
+
+// Verify reports whether signature matches the canonical request.
+func Verify(secret, signature, method, path, timestamp, nonce string, body []byte) bool {
+	return equal(Sign(secret, method, path, timestamp, nonce, body), signature)
+}
+
+// mac returns the hex encoded HMAC-SHA256 of the concatenated parts, every
+// signature of the package is computed by it.
+func mac(secret string, parts ...[]byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// equal compares two signatures in constant time.
+func equal(expected, signature string) bool {
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,85 @@
+package signature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`[{"product_id":"1","stock":10}]`)
+	sign := Sign("secret", "PATCH", "/products/product-stocks", "1700000000", "nonce", body)
+
+	assert.True(t, Verify("secret", sign, "patch", "/products/product-stocks", "1700000000", "nonce", body))
+	assert.False(t, Verify("other", sign, "PATCH", "/products/product-stocks", "1700000000", "nonce", body))
+	assert.False(t, Verify("secret", sign, "PATCH", "/products/product-stocks?x=1", "1700000000", "nonce", body))
+	assert.False(t, Verify("secret", sign, "PATCH", "/products/product-stocks", "1700000001", "nonce", body))
+	assert.False(t, Verify("secret", sign, "PATCH", "/products/product-stocks", "1700000000", "nonce", []byte(`[]`)))
+}
+
+func TestSignAndVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":"01J9","type":"stock.changed"}`)
+	sign := SignWebhook("whsec", "1700000000", body)
+
+	assert.True(t, strings.HasPrefix(sign, "sha256="))
+	assert.True(t, VerifyWebhook("whsec", sign, "1700000000", body))
+	assert.False(t, VerifyWebhook("other", sign, "1700000000", body))
+	assert.False(t, VerifyWebhook("whsec", sign, "1700000001", body))
+	assert.False(t, VerifyWebhook("whsec", sign, "1700000000", []byte(`{}`)))
+}
+
+func TestSignAndVerifyURL(t *testing.T) {
+	expires := time.Now().Add(time.Minute).Unix()
+	sign := SignURL("secret", "http://localhost/api/storage/private/a.png", expires)
+
+	assert.True(t, VerifyURL("secret", sign, "http://localhost/api/storage/private/a.png", expires))
+	assert.False(t, VerifyURL("other", sign, "http://localhost/api/storage/private/a.png", expires))
+	assert.False(t, VerifyURL("secret", sign, "http://localhost/api/storage/private/b.png", expires))
+	assert.False(t, VerifyURL("secret", sign, "http://localhost/api/storage/private/a.png", expires+1))
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	assert.False(t, VerifyURL("secret", SignURL("secret", "http://localhost/a.png", expired), "http://localhost/a.png", expired))
+}
+
+func TestNonceCache(t *testing.T) {
+	cache := NewNonceCache(50 * time.Millisecond)
+
+	assert.True(t, cache.Use("a"))
+	assert.False(t, cache.Use("a"))
+	assert.True(t, cache.Use("b"))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, cache.Use("a"))
+}
+
+func TestClientSignsRequest(t *testing.T) {
+	var verified bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified = r.Header.Get(HeaderKeyId) == "order-service" && Verify("secret",
+			r.Header.Get(HeaderSignature),
+			r.Method,
+			r.URL.RequestURI(),
+			r.Header.Get(HeaderTimestamp),
+			r.Header.Get(HeaderNonce),
+			body,
+		)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPatch, srv.URL+"/products/product-stocks?source=order", strings.NewReader(`[]`))
+	assert.NoError(t, err)
+
+	resp, err := NewClient("order-service", "secret", srv.Client()).Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.True(t, verified)
+}
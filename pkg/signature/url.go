@@ -0,0 +1,45 @@
+package signature
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Query parameters carrying the signature of a signed url.
+const (
+	QueryExpires   = "expires"
+	QuerySignature = "signature"
+)
+
+// SignURL returns the hex encoded HMAC-SHA256 of rawURL, without the signature
+// query parameters, and of its expiration as an unix timestamp.
+func SignURL(secret, rawURL string, expires int64) string {
+	return mac(secret, []byte(rawURL), []byte(strconv.FormatInt(expires, 10)))
+}
+
+// VerifyURL reports whether signature matches rawURL and expires, and whether
+// the url has not expired yet.
+func VerifyURL(secret, signature, rawURL string, expires int64) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+
+	return equal(SignURL(secret, rawURL, expires), signature)
+}
+
+// SignedURL returns rawURL with its expiration and signature added to the
+// query string.
+func SignedURL(secret, rawURL string, expires int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set(QueryExpires, strconv.FormatInt(expires, 10))
+	q.Set(QuerySignature, SignURL(secret, rawURL, expires))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
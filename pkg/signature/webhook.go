@@ -0,0 +1,20 @@
+package signature
+
+// Headers sent with every webhook delivery. The receiver verifies the
+// signature with the secret of the webhook and should reject old timestamps.
+const (
+	HeaderWebhookId        = "X-Webhook-Id"    // delivery id, the same on every attempt
+	HeaderWebhookEvent     = "X-Webhook-Event" // event type, ex: stock.changed
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+)
+
+// SignWebhook returns the signature header value of a webhook delivery.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	return "sha256=" + mac(secret, []byte(timestamp), []byte("."), body)
+}
+
+// VerifyWebhook reports whether signature matches a webhook delivery.
+func VerifyWebhook(secret, signature, timestamp string, body []byte) bool {
+	return equal(SignWebhook(secret, timestamp, body), signature)
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength leaves room for a collision suffix within a VARCHAR(255).
+const maxSlugLength = 200
+
+// transliterations covers letters that do not decompose into a base letter
+// plus combining marks.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ı': "i",
+	'&': " dan ",
+}
+
+// Slugify converts s into a lowercase, URL-safe slug made of ASCII letters,
+// digits and single dashes, ex: "Toko Kopi Café" -> "toko-kopi-cafe".
+func Slugify(s string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range norm.NFKD.String(s) {
+		if t, ok := transliterations[r]; ok {
+			for _, tr := range t {
+				dash = writeSlugRune(&b, tr, dash)
+			}
+			continue
+		}
+
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		dash = writeSlugRune(&b, r, dash)
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+
+	return slug
+}
+
+func writeSlugRune(b *strings.Builder, r rune, dash bool) bool {
+	r = unicode.ToLower(r)
+
+	if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+		b.WriteRune(r)
+		return false
+	}
+
+	if !dash && b.Len() > 0 {
+		b.WriteByte('-')
+	}
+
+	return true
+}
+
+// UniqueSlug returns slug, or slug with the lowest numeric suffix, that is
+// not in taken, ex: ("toko", ["toko", "toko-2"]) -> "toko-3".
+func UniqueSlug(slug string, taken []string) string {
+	set := make(map[string]struct{}, len(taken))
+	for _, t := range taken {
+		set[t] = struct{}{}
+	}
+
+	candidate := slug
+	for n := 2; ; n++ {
+		if _, ok := set[candidate]; !ok {
+			return candidate
+		}
+		candidate = slug + "-" + strconv.Itoa(n)
+	}
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Toko Kopi":               "toko-kopi",
+		"  Toko   Kopi  ":         "toko-kopi",
+		"Café Crème Brûlée":       "cafe-creme-brulee",
+		"Straße & Söhne":          "strasse-dan-sohne",
+		"Łódź -- Ørsted!!":        "lodz-orsted",
+		"Kaos Polos (XL) 100%":    "kaos-polos-xl-100",
+		"日本":                      "",
+		"---":                     "",
+		"iPhone 15 Pro / Max 256": "iphone-15-pro-max-256",
+	}
+
+	for in, want := range tests {
+		assert.Equal(t, want, Slugify(in), in)
+	}
+}
+
+func TestSlugifyMaxLength(t *testing.T) {
+	slug := Slugify(strings.Repeat("ab ", 200))
+
+	assert.LessOrEqual(t, len(slug), maxSlugLength)
+	assert.False(t, strings.HasSuffix(slug, "-"))
+}
+
+func TestUniqueSlug(t *testing.T) {
+	assert.Equal(t, "toko", UniqueSlug("toko", nil))
+	assert.Equal(t, "toko", UniqueSlug("toko", []string{"toko-2"}))
+	assert.Equal(t, "toko-2", UniqueSlug("toko", []string{"toko"}))
+	assert.Equal(t, "toko-4", UniqueSlug("toko", []string{"toko", "toko-2", "toko-3", "toko-abc"}))
+}
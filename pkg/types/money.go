@@ -0,0 +1,333 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MoneyScale is the number of fractional digits kept by Money, matching the
+// DECIMAL(19,4) price columns.
+const MoneyScale = 4
+
+const moneyUnit = 10000 // 10^MoneyScale
+
+var errMoneyOverflow = errors.New("amount is out of range")
+
+// Money is an exact decimal amount stored as a count of 1/10000 units, so
+// sums and comparisons never suffer float rounding. It carries no currency,
+// the currency lives next to the amount (ex: products.currency).
+type Money int64
+
+// NewMoney returns the money of a whole amount, ex: NewMoney(1500) is 1500.0000.
+func NewMoney(amount int64) Money {
+	return Money(amount * moneyUnit)
+}
+
+// ParseMoney parses a decimal string with at most 4 fractional digits,
+// ex: "1500", "-12.5", "0.0001".
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, errors.New("must be a decimal number")
+	}
+
+	if len(frac) > MoneyScale {
+		// tolerate trailing zeros of a wider scale, ex: "1.500000"
+		if strings.Trim(frac[MoneyScale:], "0") != "" {
+			return 0, fmt.Errorf("must have at most %d decimal places", MoneyScale)
+		}
+		frac = frac[:MoneyScale]
+	}
+
+	var units int64
+	for _, part := range []string{whole, frac + strings.Repeat("0", MoneyScale-len(frac))} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, errors.New("must be a decimal number")
+			}
+
+			if units > (math.MaxInt64-int64(c-'0'))/10 {
+				return 0, errMoneyOverflow
+			}
+			units = units*10 + int64(c-'0')
+		}
+	}
+
+	if neg {
+		units = -units
+	}
+
+	return Money(units), nil
+}
+
+// MustParseMoney is ParseMoney for constants, it panics on an invalid amount.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// String formats the amount without trailing fractional zeros, ex: "1500", "12.5".
+func (m Money) String() string {
+	units := int64(m)
+
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+
+	// abs in uint64 so math.MinInt64 does not overflow
+	abs := uint64(units)
+	if units < 0 {
+		abs = -abs
+	}
+
+	whole := strconv.FormatUint(abs/moneyUnit, 10)
+	frac := strings.TrimRight(fmt.Sprintf("%04d", abs%moneyUnit), "0")
+	if frac == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + frac
+}
+
+// Add returns m + o.
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+// Sub returns m - o.
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul returns m multiplied by a quantity.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m < o:
+		return -1
+	case m > o:
+		return 1
+	}
+
+	return 0
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// Convert returns the amount multiplied by an exchange rate, rounded half away
+// from zero to the money scale.
+func (m Money) Convert(rate Rate) (Money, error) {
+	if rate.r == nil {
+		return 0, errors.New("rate is not set")
+	}
+
+	return divRound(new(big.Int).Mul(big.NewInt(int64(m)), rate.r.Num()), rate.r.Denom())
+}
+
+// Percent returns pct percent of the amount, rounded half away from zero to
+// the money scale, ex: NewMoney(200).Percent(MustParseMoney("12.5")) is 25.
+func (m Money) Percent(pct Money) (Money, error) {
+	return divRound(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(pct))), big.NewInt(100*moneyUnit))
+}
+
+// divRound returns num / den in money units, rounded half away from zero.
+func divRound(num, den *big.Int) (Money, error) {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() != 0 && new(big.Int).Mul(r.Abs(r), big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+
+	if !q.IsInt64() {
+		return 0, errMoneyOverflow
+	}
+
+	return Money(q.Int64()), nil
+}
+
+// MarshalJSON writes the amount as a JSON number, ex: 1500.5.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads the amount from a JSON number or a numeric string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	v, err := ParseMoney(strings.Trim(s, `"`))
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", s, err)
+	}
+
+	*m = v
+	return nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *Money) Scan(val interface{}) error {
+	var (
+		v   Money
+		err error
+	)
+
+	switch t := val.(type) {
+	case []uint8:
+		v, err = ParseMoney(string(t))
+	case string:
+		v, err = ParseMoney(t)
+	case int64:
+		if t > math.MaxInt64/moneyUnit || t < math.MinInt64/moneyUnit {
+			return errMoneyOverflow
+		}
+		v = NewMoney(t)
+	case float64:
+		// only expected from float expressions, rounded to the money scale
+		v, err = ParseMoney(strconv.FormatFloat(math.Round(t*moneyUnit)/moneyUnit, 'f', MoneyScale, 64))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", val)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	*m = v
+	return nil
+}
+
+// Value impl.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Rate is an exact exchange rate, the amount of the quote currency bought by
+// one unit of the base currency.
+type Rate struct {
+	r *big.Rat
+}
+
+// ParseRate parses a positive decimal rate, ex: "0.0000625".
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "/eE") {
+		return Rate{}, errors.New("must be a decimal number")
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Rate{}, errors.New("must be a decimal number")
+	}
+
+	if r.Sign() <= 0 {
+		return Rate{}, errors.New("must be greater than 0")
+	}
+
+	return Rate{r: r}, nil
+}
+
+// RateOne is the rate between a currency and itself.
+func RateOne() Rate {
+	return Rate{r: big.NewRat(1, 1)}
+}
+
+// IsZero reports whether the rate is unset.
+func (r Rate) IsZero() bool {
+	return r.r == nil
+}
+
+// Inverse returns the rate of the opposite direction.
+func (r Rate) Inverse() Rate {
+	if r.r == nil {
+		return r
+	}
+
+	return Rate{r: new(big.Rat).Inv(r.r)}
+}
+
+// String formats the rate with up to 12 fractional digits.
+func (r Rate) String() string {
+	if r.r == nil {
+		return "0"
+	}
+
+	s := r.r.FloatString(12)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON writes the rate as a JSON number.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads the rate from a JSON number or a numeric string.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	v, err := ParseRate(strings.Trim(s, `"`))
+	if err != nil {
+		return fmt.Errorf("invalid rate %s: %w", s, err)
+	}
+
+	*r = v
+	return nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *Rate) Scan(val interface{}) error {
+	var raw string
+	switch v := val.(type) {
+	case []uint8:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", val)
+	}
+
+	v, err := ParseRate(raw)
+	if err != nil {
+		return err
+	}
+
+	*r = v
+	return nil
+}
+
+// Value impl.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
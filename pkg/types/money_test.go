@@ -0,0 +1,128 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]string{
+		"1500":     "1500",
+		"1500.50":  "1500.5",
+		"-12.3400": "-12.34",
+		"0.0001":   "0.0001",
+		".5":       "0.5",
+		"+7":       "7",
+		"1.500000": "1.5",
+	}
+	for in, want := range cases {
+		m, err := ParseMoney(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, m.String(), in)
+	}
+
+	for _, invalid := range []string{"", ".", "-", "1.23456", "1e3", "abc", "1,5", "99999999999999999999"} {
+		_, err := ParseMoney(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exactly 0.3, unlike float64
+	sum := MustParseMoney("0.1").Add(MustParseMoney("0.2"))
+	assert.Equal(t, MustParseMoney("0.3"), sum)
+
+	assert.Equal(t, "29.97", MustParseMoney("9.99").Mul(3).String())
+	assert.Equal(t, -1, NewMoney(1).Cmp(NewMoney(2)))
+	assert.Equal(t, "-0.5", NewMoney(1).Sub(MustParseMoney("1.5")).String())
+}
+
+func TestMoneyConvert(t *testing.T) {
+	rate, err := ParseRate("0.0000625")
+	assert.NoError(t, err)
+
+	usd, err := NewMoney(150000).Convert(rate)
+	assert.NoError(t, err)
+	assert.Equal(t, "9.375", usd.String())
+
+	// rounded half away from zero to 4 places
+	third, _ := ParseRate("0.33335")
+	m, err := NewMoney(1).Convert(third)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3334", m.String())
+
+	m, err = NewMoney(-1).Convert(third)
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.3334", m.String())
+
+	idr, err := usd.Convert(rate.Inverse())
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(150000), idr)
+
+	_, err = NewMoney(1).Convert(Rate{})
+	assert.Error(t, err)
+
+	for _, invalid := range []string{"0", "-1", "1/3", "1e2", "x"} {
+		_, err := ParseRate(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMoneyPercent(t *testing.T) {
+	m, err := NewMoney(200).Percent(MustParseMoney("12.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, NewMoney(25), m)
+
+	// rounded half away from zero to 4 places
+	m, err = MustParseMoney("0.0005").Percent(NewMoney(-10))
+	assert.NoError(t, err)
+	assert.Equal(t, "-0.0001", m.String())
+
+	m, err = MustParseMoney("99999.99").Percent(NewMoney(10))
+	assert.NoError(t, err)
+	assert.Equal(t, "9999.999", m.String())
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		Price Money  `json:"price"`
+		Min   *Money `json:"min"`
+		Rate  Rate   `json:"rate"`
+	}
+
+	err := json.Unmarshal([]byte(`{"price": 1500.25, "min": "10", "rate": 0.5}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParseMoney("1500.25"), v.Price)
+	assert.Equal(t, NewMoney(10), *v.Min)
+
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price": 1500.25, "min": 10, "rate": 0.5}`, string(b))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"price": 1.23456}`), &v))
+}
+
+func TestMoneyScan(t *testing.T) {
+	var m Money
+
+	assert.NoError(t, m.Scan([]uint8("1500.5000")))
+	assert.Equal(t, MustParseMoney("1500.5"), m)
+
+	assert.NoError(t, m.Scan(int64(7)))
+	assert.Equal(t, NewMoney(7), m)
+
+	assert.NoError(t, m.Scan(0.1))
+	assert.Equal(t, MustParseMoney("0.1"), m)
+
+	assert.Error(t, m.Scan(nil))
+
+	v, err := MustParseMoney("-2.5").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-2.5", v)
+
+	var r Rate
+	assert.NoError(t, r.Scan([]uint8("16000.000000000000")))
+	assert.Equal(t, "16000", r.String())
+}
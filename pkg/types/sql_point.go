@@ -5,7 +5,10 @@ import (
 	"database/sql/driver"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // Point represents an x,y coordinate in EPSG:4326 for PostGIS.
@@ -15,9 +18,54 @@ func (p *Point) String() string {
 	return fmt.Sprintf("SRID=4326;POINT(%v %v)", p[0], p[1])
 }
 
+// NewPoint returns the point of a latitude and longitude, stored as x=lng, y=lat.
+func NewPoint(lat, lng float64) Point {
+	return Point{lng, lat}
+}
+
+// Lat returns the latitude of the point.
+func (p Point) Lat() float64 {
+	return p[1]
+}
+
+// Lng returns the longitude of the point.
+func (p Point) Lng() float64 {
+	return p[0]
+}
+
+// ParseLatLng parses a "lat,lng" string, ex: "-6.2,106.816666".
+func ParseLatLng(s string) (Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Point{}, errors.New("must be in lat,lng format")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return Point{}, errors.New("latitude must be between -90 and 90")
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return Point{}, errors.New("longitude must be between -180 and 180")
+	}
+
+	return NewPoint(lat, lng), nil
+}
+
 // Scan implements the sql.Scanner interface.
 func (p *Point) Scan(val interface{}) error {
-	b, err := hex.DecodeString(string(val.([]uint8)))
+	var raw string
+	switch v := val.(type) {
+	case []uint8:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into Point", val)
+	}
+
+	b, err := hex.DecodeString(raw)
 	if err != nil {
 		return err
 	}
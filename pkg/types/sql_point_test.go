@@ -0,0 +1,32 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLatLng(t *testing.T) {
+	p, err := ParseLatLng("-6.2, 106.816666")
+	assert.NoError(t, err)
+	assert.Equal(t, -6.2, p.Lat())
+	assert.Equal(t, 106.816666, p.Lng())
+	assert.Equal(t, "SRID=4326;POINT(106.816666 -6.2)", p.String())
+
+	for _, invalid := range []string{"", "-6.2", "a,b", "91,0", "0,181", "1,2,3"} {
+		_, err := ParseLatLng(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestPointScan(t *testing.T) {
+	var p Point
+
+	// EWKB of SRID=4326;POINT(106.816666 -6.2) as returned by PostGIS
+	err := p.Scan([]uint8("0101000020E61000005470784144B45A40CDCCCCCCCCCC18C0"))
+	assert.NoError(t, err)
+	assert.InDelta(t, 106.816666, p.Lng(), 1e-9)
+	assert.InDelta(t, -6.2, p.Lat(), 1e-9)
+
+	assert.Error(t, p.Scan(nil))
+}